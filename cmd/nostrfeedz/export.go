@@ -0,0 +1,131 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/plebone/nostrfeedz-cli/internal/archive"
+	"github.com/plebone/nostrfeedz-cli/internal/cache"
+	"github.com/plebone/nostrfeedz-cli/internal/config"
+	"github.com/plebone/nostrfeedz-cli/internal/db"
+	"github.com/plebone/nostrfeedz-cli/internal/export"
+)
+
+const exportUsage = `Usage: nostrfeedz export [options] [filter query]
+
+Exports articles as markdown files, an HTML digest, an EPUB book or plain
+text. Without -item or -archive, the articles matching the filter query are
+exported, e.g.:
+
+  nostrfeedz export -format epub tag:nostr since:7d
+  nostrfeedz export -format markdown -o ~/notes/starred is:starred
+  nostrfeedz export -item <id> -o article.txt
+
+Options:
+`
+
+// runExport runs the export subcommand and returns the exit code
+func runExport(cfg *config.Config, database *db.DB, args []string) int {
+	fs := flag.NewFlagSet("export", flag.ContinueOnError)
+	fs.Usage = func() {
+		fmt.Fprint(fs.Output(), exportUsage)
+		fs.PrintDefaults()
+	}
+	formatName := fs.String("format", "html", "markdown, html, epub or text")
+	output := fs.String("o", "", "file to write, or directory for markdown (default: the export directory)")
+	itemID := fs.String("item", "", "export the item with this ID")
+	archived := fs.Bool("archive", false, "export the offline archive")
+	limit := fs.Int("limit", 0, "export at most this many matching articles (default all)")
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+
+	format, err := export.ParseFormat(*formatName)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 2
+	}
+	images, _ := cache.NewImageCache(config.GetImageCacheDir(), database)
+
+	var articles []export.Article
+	var title, name string
+	switch {
+	case *itemID != "":
+		item, err := database.GetFeedItem(*itemID)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error loading item %s: %v\n", *itemID, err)
+			return 1
+		}
+		articles, err = export.Load(database, []db.FeedItem{*item})
+		title, name = item.Title, "nostrfeedz-article"
+	case *archived:
+		if images == nil {
+			fmt.Fprintln(os.Stderr, "Error: the image cache is unavailable")
+			return 1
+		}
+		articles, err = archive.New(database, images, cfg.Archive.Starred).Articles()
+		title, name = "NostrFeedz archive, "+time.Now().Format("January 2, 2006"), "nostrfeedz-archive"
+	default:
+		query := strings.Join(fs.Args(), " ")
+		filter, perr := db.ParseItemFilter(query)
+		if perr != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", perr)
+			return 2
+		}
+		filter.Limit = -1
+		if *limit > 0 {
+			filter.Limit = *limit
+		}
+		var items []db.FeedItem
+		items, err = database.QueryFeedItems(filter)
+		if err == nil {
+			articles, err = export.Load(database, items)
+		}
+		title, name = "NostrFeedz", "nostrfeedz-export"
+		if query != "" {
+			title += ": " + query
+		}
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 1
+	}
+	if len(articles) == 0 {
+		fmt.Fprintln(os.Stderr, "No articles to export")
+		return 1
+	}
+
+	path := *output
+	if path == "" {
+		dir := config.GetExportDir(cfg)
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			return 1
+		}
+		path = filepath.Join(dir, fmt.Sprintf("%s-%s%s", name, time.Now().Format("2006-01-02"), format.Ext()))
+	} else if strings.HasPrefix(path, "~/") {
+		home, _ := os.UserHomeDir()
+		path = filepath.Join(home, path[2:])
+	}
+
+	// A nil cache must not become a non-nil interface
+	var cached export.Images
+	if images != nil {
+		cached = images
+	}
+	paths, err := export.Write(format, path, title, articles, cached)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error exporting: %v\n", err)
+		return 1
+	}
+	if len(paths) > 1 {
+		fmt.Printf("Exported %d articles to %s\n", len(articles), filepath.Dir(paths[0]))
+	} else {
+		fmt.Printf("Exported %d articles to %s\n", len(articles), paths[0])
+	}
+	return 0
+}
@@ -0,0 +1,73 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log/slog"
+	"os"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/plebone/nostrfeedz-cli/internal/app"
+	"github.com/plebone/nostrfeedz-cli/internal/config"
+	"github.com/plebone/nostrfeedz-cli/internal/db"
+	"github.com/plebone/nostrfeedz-cli/internal/logging"
+	"github.com/plebone/nostrfeedz-cli/pkg/styles"
+)
+
+func main() {
+	debug := flag.Bool("debug", false, "log at debug level regardless of config")
+	flag.Parse()
+
+	// Load configuration
+	cfg, err := config.Load()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error loading config: %v\n", err)
+		os.Exit(1)
+	}
+
+	// The TUI owns stdout/stderr, so everything else goes to the log file
+	level := logging.ParseLevel(cfg.Logging.Level)
+	if *debug {
+		level = slog.LevelDebug
+	}
+	logFile, err := logging.Setup(logging.Options{
+		Path:       config.GetLogPath(cfg),
+		Level:      level,
+		MaxSize:    int64(cfg.Logging.MaxSizeMB) * 1024 * 1024,
+		MaxBackups: cfg.Logging.MaxBackups,
+	})
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error opening log file: %v\n", err)
+		os.Exit(1)
+	}
+	defer logFile.Close()
+
+	slog.Info("starting nostrfeedz", "level", level.String())
+
+	// Initialize database
+	database, err := db.New(config.GetDatabasePath(cfg))
+	if err != nil {
+		slog.Error("failed to open database", "err", err)
+		fmt.Fprintf(os.Stderr, "Error initializing database: %v\n", err)
+		os.Exit(1)
+	}
+	defer database.Close()
+
+	if flag.Arg(0) == "export" {
+		code := runExport(cfg, database, flag.Args()[1:])
+		database.Close()
+		logFile.Close()
+		os.Exit(code)
+	}
+
+	// Glamour's "auto" style queries the terminal; do it once, before the
+	// TUI takes over stdin, instead of on every background render
+	styles.DetectBackground()
+
+	p := tea.NewProgram(app.New(cfg, database), tea.WithAltScreen())
+	if _, err := p.Run(); err != nil {
+		slog.Error("program exited with error", "err", err)
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+}
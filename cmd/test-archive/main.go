@@ -0,0 +1,218 @@
+package main
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"encoding/xml"
+	"fmt"
+	"image"
+	"image/color"
+	"image/png"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/plebone/nostrfeedz-cli/internal/archive"
+	"github.com/plebone/nostrfeedz-cli/internal/cache"
+	"github.com/plebone/nostrfeedz-cli/internal/db"
+	"github.com/plebone/nostrfeedz-cli/internal/export"
+)
+
+const page = `<html><head><title>Starred Post</title></head><body>
+<nav><a href="/">Home</a></nav>
+<article><h1>Starred Post</h1>
+<p>The full article is much longer than the summary in the feed, with commas, clauses, and detail.</p>
+<p>It has an image that must survive cache cleanup, because this article was starred by the reader.</p>
+<img src="/img/photo.png" alt="A photo">
+<p>And a closing paragraph, so the extractor has enough text to be confident about the content.</p>
+</article></body></html>`
+
+// Verifies archiving of starred items and archived feeds, image pinning
+// across cache cleanup, and HTML and EPUB export, against a local server.
+func main() {
+	fmt.Print("=== Offline Archive Test ===\n\n")
+
+	var pngData bytes.Buffer
+	img := image.NewRGBA(image.Rect(0, 0, 4, 4))
+	img.Set(1, 1, color.RGBA{R: 255, A: 255})
+	png.Encode(&pngData, img)
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/post", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		fmt.Fprint(w, page)
+	})
+	mux.HandleFunc("/img/", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "image/png")
+		w.Write(pngData.Bytes())
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	tmp, err := os.MkdirTemp("", "nostrfeedz-archive")
+	if err != nil {
+		fail("%v", err)
+	}
+	defer os.RemoveAll(tmp)
+	database, err := db.New(filepath.Join(tmp, "test.db"))
+	if err != nil {
+		fail("db: %v", err)
+	}
+	defer database.Close()
+	images, err := cache.NewImageCache(filepath.Join(tmp, "images"), database)
+	if err != nil {
+		fail("cache: %v", err)
+	}
+
+	now := time.Now()
+	database.CreateFeed(&db.Feed{ID: "rss", Type: "rss", URL: server.URL + "/feed", Title: "Blog", CreatedAt: now})
+	database.CreateFeed(&db.Feed{ID: "nostr", Type: "nostr", NPUB: "npub1test", Title: "Long-form", CreatedAt: now})
+	for _, item := range []db.FeedItem{
+		{ID: "starred", FeedID: "rss", GUID: "g1", Title: "Starred Post", Content: "<p>Summary…</p>",
+			URL: server.URL + "/post", IsFavorite: true, PublishedAt: now, CreatedAt: now},
+		{ID: "plain", FeedID: "rss", GUID: "g2", Title: "Plain Post", Content: "<p>Not kept</p>",
+			URL: server.URL + "/post", PublishedAt: now, CreatedAt: now},
+		{ID: "note", FeedID: "nostr", GUID: "g3", Title: "A Long-form Note", Author: "npub1test",
+			Content:     "# Heading\n\nMarkdown with **bold** text.\n\n![diagram](" + server.URL + "/img/diagram.png)",
+			PublishedAt: now.Add(-time.Hour), CreatedAt: now},
+	} {
+		if err := database.CreateFeedItem(&item); err != nil {
+			fail("create item: %v", err)
+		}
+	}
+
+	fmt.Println("1. Archiving starred items and archived feeds...")
+	if err := database.SetFeedArchive("nostr", true); err != nil {
+		fail("set feed archive: %v", err)
+	}
+	archiver := archive.New(database, images, true)
+	result, err := archiver.Sync(context.Background())
+	if err != nil || result.Archived != 2 || result.Failed != 0 {
+		fail("expected 2 archived, got %+v (%v)", result, err)
+	}
+	archived, _ := database.GetArchivedItems()
+	for _, item := range archived {
+		if item.Images != 1 {
+			fail("%s: expected 1 pinned image, got %d", item.ID, item.Images)
+		}
+		if item.ID == "starred" && !strings.Contains(item.FullContent, "much longer than the summary") {
+			fail("full article not stored: %q", item.FullContent)
+		}
+	}
+	fmt.Printf("   ✓ %d articles archived with their images\n", len(archived))
+
+	fmt.Println("\n2. Cleaning up the image cache...")
+	unpinned, err := images.Download(server.URL + "/img/unrelated.png")
+	if err != nil {
+		fail("download: %v", err)
+	}
+	images.SetLimits(1, cache.DefaultExpiration)
+	if err := images.EnforceSizeLimit(); err != nil {
+		fail("cleanup: %v", err)
+	}
+	images.SetLimits(cache.DefaultMaxSize, cache.DefaultExpiration)
+	if _, err := os.Stat(unpinned); !os.IsNotExist(err) {
+		fail("unpinned image was kept over the size limit")
+	}
+	for _, url := range []string{server.URL + "/img/photo.png", server.URL + "/img/diagram.png"} {
+		if !images.IsCached(url) {
+			fail("pinned image %s was removed", url)
+		}
+	}
+	fmt.Println("   ✓ Unpinned images evicted, archived images kept")
+
+	fmt.Println("\n3. Exporting as HTML...")
+	articles, err := archiver.Articles()
+	if err != nil || len(articles) != 2 {
+		fail("archived articles: %d, %v", len(articles), err)
+	}
+	var digest bytes.Buffer
+	if err := export.WriteHTML(&digest, "Archive", articles, images); err != nil {
+		fail("export: %v", err)
+	}
+	html := digest.String()
+	for _, want := range []string{"Starred Post", "A Long-form Note", "<strong>bold</strong>", "data:image/png;base64,", `href="#article-2"`} {
+		if !strings.Contains(html, want) {
+			fail("HTML export is missing %q", want)
+		}
+	}
+	if strings.Contains(html, server.URL+"/img/") || strings.Contains(html, "Home</a>") {
+		fail("HTML export is not self-contained or kept page chrome:\n%s", html)
+	}
+	fmt.Printf("   ✓ %d bytes, images embedded\n", digest.Len())
+
+	fmt.Println("\n4. Exporting as EPUB...")
+	var book bytes.Buffer
+	if err := export.WriteEPUB(&book, "Archive", articles, images); err != nil {
+		fail("export: %v", err)
+	}
+	checkEPUB(book.Bytes())
+
+	fmt.Println("\n5. Unstarring releases the archive...")
+	database.ToggleFavorite("starred")
+	result, err = archiver.Sync(context.Background())
+	if err != nil || result.Removed != 1 {
+		fail("expected 1 removed, got %+v (%v)", result, err)
+	}
+	pinned, _ := database.GetPinnedImages()
+	if len(pinned) != 1 || !strings.HasSuffix(pinned[0], "/img/diagram.png") {
+		fail("unexpected pins after unstarring: %v", pinned)
+	}
+	fmt.Println("   ✓ Archive and pins released")
+
+	fmt.Println("\n✅ Offline archive works")
+}
+
+func checkEPUB(data []byte) {
+	zr, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		fail("not a zip: %v", err)
+	}
+	if zr.File[0].Name != "mimetype" || zr.File[0].Method != zip.Store {
+		fail("mimetype must be the first, stored entry")
+	}
+
+	names := make(map[string]bool)
+	images := 0
+	for _, f := range zr.File {
+		names[f.Name] = true
+		if strings.HasPrefix(f.Name, "OEBPS/images/") {
+			images++
+		}
+		if !strings.HasSuffix(f.Name, ".xhtml") && !strings.HasSuffix(f.Name, ".opf") &&
+			!strings.HasSuffix(f.Name, ".ncx") && !strings.HasSuffix(f.Name, ".xml") {
+			continue
+		}
+		// Every document must be well-formed XML
+		r, _ := f.Open()
+		dec := xml.NewDecoder(r)
+		for {
+			if _, err := dec.Token(); err == io.EOF {
+				break
+			} else if err != nil {
+				fail("%s is not well-formed: %v", f.Name, err)
+			}
+		}
+		r.Close()
+	}
+	for _, want := range []string{"META-INF/container.xml", "OEBPS/content.opf", "OEBPS/nav.xhtml",
+		"OEBPS/toc.ncx", "OEBPS/article-001.xhtml", "OEBPS/article-002.xhtml"} {
+		if !names[want] {
+			fail("EPUB is missing %s", want)
+		}
+	}
+	if images != 2 {
+		fail("expected 2 packaged images, got %d", images)
+	}
+	fmt.Printf("   ✓ %d bytes, %d files, %d images, well-formed\n", len(data), len(zr.File), images)
+}
+
+func fail(format string, args ...interface{}) {
+	fmt.Printf("❌ "+format+"\n", args...)
+	os.Exit(1)
+}
@@ -0,0 +1,127 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/nbd-wtf/go-nostr"
+	nostrclient "github.com/plebone/nostrfeedz-cli/internal/nostr"
+	"github.com/plebone/nostrfeedz-cli/internal/testrelay"
+)
+
+// Verifies the NIP-42 AUTH handshake against a local relay that refuses
+// all requests until the client authenticates.
+func main() {
+	fmt.Print("=== NIP-42 Relay Authentication Test ===\n\n")
+
+	fmt.Println("1. Starting local relay (auth required)...")
+	relay := testrelay.Start(true)
+	defer relay.Close()
+	fmt.Printf("✓ Listening on %s\n", relay.URL)
+
+	// Seed the relay with an event to query for
+	authorKey := nostr.GeneratePrivateKey()
+	seed := &nostr.Event{Kind: 1, Content: "hello from a private relay", CreatedAt: nostr.Now(), Tags: nostr.Tags{}}
+	seed.Sign(authorKey)
+	relay.Publish(seed)
+
+	filter := nostr.Filter{Kinds: []int{1}, IDs: []string{seed.ID}}
+
+	fmt.Println("\n2. Querying with policy 'always'...")
+	client := newClient(relay.URL, nostrclient.AuthAlways)
+	events := query(client, filter)
+	if len(events) != 1 {
+		fail("expected 1 event after AUTH, got %d", len(events))
+	}
+	if len(relay.Authenticated()) != 1 || relay.Authenticated()[0] != client.GetPublicKey() {
+		fail("relay did not record our pubkey as authenticated")
+	}
+	expectResult(client, false)
+	fmt.Println("✓ Authenticated and received the event")
+
+	fmt.Println("\n3. Querying with policy 'never'...")
+	client = newClient(relay.URL, nostrclient.AuthNever)
+	events = query(client, filter)
+	if len(events) != 0 {
+		fail("expected no events without AUTH, got %d", len(events))
+	}
+	expectResult(client, true)
+	fmt.Println("✓ Refused to authenticate and reported the failure")
+
+	fmt.Println("\n4. Querying with policy 'ask' (approving the prompt)...")
+	client = newClient(relay.URL, nostrclient.AuthAsk)
+	prompts := make(chan nostrclient.AuthRequest, 1)
+	go func() {
+		req := <-client.AuthRequests()
+		fmt.Printf("   Prompt for %s -> approve\n", req.Relay)
+		req.Reply <- true
+		prompts <- req
+	}()
+	events = query(client, filter)
+	if len(events) != 1 {
+		fail("expected 1 event after approved AUTH, got %d", len(events))
+	}
+	expectResult(client, false)
+	select {
+	case <-(<-prompts).Done:
+	case <-time.After(time.Second):
+		fail("answered prompt was not marked done")
+	}
+	fmt.Println("✓ Prompted, authenticated, received the event and closed the prompt")
+
+	fmt.Println("\n5. Checking relay health with policy 'always'...")
+	client = newClient(relay.URL, nostrclient.AuthAlways)
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	status := client.CheckRelay(ctx, relay.URL)
+	cancel()
+	if !status.Connected || status.LastError != "" || !status.AuthRequired {
+		fail("unexpected status: connected=%v auth_required=%v error=%q",
+			status.Connected, status.AuthRequired, status.LastError)
+	}
+	fmt.Printf("✓ Connected, auth required, latency %s\n", status.Latency)
+
+	fmt.Println("\n=== All Tests Passed! ===")
+}
+
+func newClient(url string, policy nostrclient.AuthPolicy) *nostrclient.Client {
+	client := nostrclient.NewClient([]string{url})
+	if err := client.SetPrivateKeySigner(nostr.GeneratePrivateKey()); err != nil {
+		fail("failed to set signer: %v", err)
+	}
+	client.SetAuthPolicies(map[string]nostrclient.AuthPolicy{url: policy})
+	return client
+}
+
+func query(client *nostrclient.Client, filter nostr.Filter) []*nostr.Event {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	events, err := client.QueryEvents(ctx, filter)
+	if err != nil {
+		fail("query failed: %v", err)
+	}
+	return events
+}
+
+func expectResult(client *nostrclient.Client, wantErr bool) {
+	select {
+	case result := <-client.AuthResults():
+		if wantErr && result.Err == nil {
+			fail("expected an auth failure for %s", result.Relay)
+		}
+		if !wantErr && result.Err != nil {
+			fail("unexpected auth failure for %s: %v", result.Relay, result.Err)
+		}
+		if result.Err != nil {
+			fmt.Printf("   Status line: Auth failed for %s: %v\n", result.Relay, result.Err)
+		}
+	case <-time.After(5 * time.Second):
+		fail("no auth result reported")
+	}
+}
+
+func fail(format string, args ...interface{}) {
+	fmt.Printf("❌ "+format+"\n", args...)
+	os.Exit(1)
+}
@@ -0,0 +1,178 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/nbd-wtf/go-nostr"
+	"github.com/nbd-wtf/go-nostr/nip19"
+	"github.com/plebone/nostrfeedz-cli/internal/db"
+	nostrclient "github.com/plebone/nostrfeedz-cli/internal/nostr"
+	"github.com/plebone/nostrfeedz-cli/internal/testrelay"
+)
+
+// Verifies that stars round-trip through a NIP-51 bookmark list on a local
+// relay, keeping entries other clients added.
+func main() {
+	fmt.Print("=== NIP-51 Bookmark Sync Test ===\n\n")
+
+	relay := testrelay.Start(false)
+	defer relay.Close()
+
+	client := nostrclient.NewClient([]string{relay.URL})
+	if err := client.SetPrivateKeySigner(nostr.GeneratePrivateKey()); err != nil {
+		fail("failed to set signer: %v", err)
+	}
+	pubkey := client.GetPublicKey()
+
+	fmt.Println("1. Fetching before anything is published...")
+	list, err := client.FetchBookmarks(pubkey, "")
+	if err != nil || list != nil {
+		fail("expected no list, got %v (err %v)", list, err)
+	}
+	fmt.Println("✓ No list yet")
+
+	fmt.Println("\n2. Publishing a list with a foreign entry and our stars...")
+	list = &nostrclient.BookmarkList{
+		Tags:    nostr.Tags{{"a", "30023:" + pubkey + ":other-client"}},
+		Content: "encrypted-private-items",
+	}
+	list.Add(nostrclient.EventBookmark("ev1"))
+	list.Add(nostrclient.URLBookmark("https://example.com/post"))
+	if list.Add(nostrclient.URLBookmark("https://example.com/post")) {
+		fail("duplicate bookmark was added")
+	}
+	if err := client.PublishBookmarks(list); err != nil {
+		fail("publish failed: %v", err)
+	}
+	time.Sleep(200 * time.Millisecond)
+
+	fetched, err := client.FetchBookmarks(pubkey, "")
+	if err != nil || fetched == nil {
+		fail("fetch failed: %v", err)
+	}
+	if len(fetched.Tags) != 3 || fetched.Content != list.Content {
+		fail("list did not round-trip: %v %q", fetched.Tags, fetched.Content)
+	}
+	fmt.Printf("✓ Fetched %d entries, private content kept\n", len(fetched.Tags))
+
+	fmt.Println("\n3. Publishing a named set...")
+	set := &nostrclient.BookmarkList{Identifier: "reading"}
+	set.Add(nostrclient.EventBookmark("ev2"))
+	if err := client.PublishBookmarks(set); err != nil {
+		fail("publish failed: %v", err)
+	}
+	time.Sleep(200 * time.Millisecond)
+	fetched, err = client.FetchBookmarks(pubkey, "reading")
+	if err != nil || fetched == nil || len(fetched.EventIDs()) != 1 || fetched.EventIDs()[0] != "ev2" {
+		fail("set did not round-trip: %v (err %v)", fetched, err)
+	}
+	fmt.Println("✓ Kind 30003 set kept separate from the list")
+
+	fmt.Println("\n4. Applying the list to local stars...")
+	dir, _ := os.MkdirTemp("", "bookmarks")
+	defer os.RemoveAll(dir)
+	database, err := db.New(filepath.Join(dir, "feeds.db"))
+	if err != nil {
+		fail("db: %v", err)
+	}
+	defer database.Close()
+
+	database.CreateFeed(&db.Feed{ID: "f1", Type: "rss", URL: "https://example.com/feed", Title: "Example", CreatedAt: time.Now()})
+	// An article bookmarked in another client by coordinate, since edited
+	naddr, _ := nip19.EncodeEntity(pubkey, nostrclient.LongFormKind, "other-client", nil)
+	items := []db.FeedItem{
+		{ID: "i1", GUID: "ev1", URL: "nostr:note1abc"},
+		{ID: "i2", GUID: "g2", URL: "https://example.com/post"},
+		{ID: "i3", GUID: "g3", URL: "https://example.com/old", IsFavorite: true},
+		{ID: "i4", GUID: "edited-version", URL: "nostr:" + naddr},
+	}
+	for _, item := range items {
+		item.FeedID, item.Title, item.PublishedAt, item.CreatedAt = "f1", item.ID, time.Now(), time.Now()
+		if err := database.CreateFeedItem(&item); err != nil {
+			fail("db: %v", err)
+		}
+	}
+
+	list, _ = client.FetchBookmarks(pubkey, "")
+	if err := database.SetFavorites(list.EventIDs(), append(list.URLs(), list.ArticleLinks()...)); err != nil {
+		fail("set favorites: %v", err)
+	}
+	starred, _ := database.QueryFeedItems(db.ItemFilter{Starred: true, Limit: -1})
+	got := map[string]bool{}
+	for _, item := range starred {
+		got[item.ID] = true
+	}
+	if len(starred) != 3 || !got["i1"] || !got["i2"] || !got["i4"] {
+		fail("unexpected stars: %v", got)
+	}
+	fmt.Println("✓ Nostr and RSS items starred, edited article matched by coordinate, stale star removed")
+
+	fmt.Println("\n5. Keeping stars not published yet...")
+	database.ToggleFavorite("i3") // Starred offline
+	database.ToggleFavorite("i2") // Unstarred offline
+	pending, err := database.GetPendingStars()
+	if err != nil || len(pending) != 2 {
+		fail("expected 2 pending stars, got %+v (err %v)", pending, err)
+	}
+	for _, item := range pending {
+		if want := item.ID == "i3"; item.IsFavorite != want || item.URL == "" {
+			fail("pending star recorded wrong: %+v", item)
+		}
+	}
+	if err := database.SetFavorites(list.EventIDs(), append(list.URLs(), list.ArticleLinks()...)); err != nil {
+		fail("set favorites: %v", err)
+	}
+	if !isStarred(database, "i3") || isStarred(database, "i2") {
+		fail("the remote list overwrote stars not published yet")
+	}
+	database.ToggleFavorite("i3")
+	database.ToggleFavorite("i3")          // Starred again after being read as pending
+	database.ClearPendingStar("i3", false) // An older publish finishing
+	database.ClearPendingStar("i2", false)
+	if pending, _ := database.GetPendingStars(); len(pending) != 1 || pending[0].ID != "i3" {
+		fail("expected only the newer change pending, got %+v", pending)
+	}
+	database.ClearPendingStar("i3", true)
+	database.SetFavorites(list.EventIDs(), list.URLs())
+	if isStarred(database, "i3") || !isStarred(database, "i2") {
+		fail("published stars do not follow the list")
+	}
+	fmt.Println("✓ Pending stars and unstars survive a sync until published")
+
+	fmt.Println("\n6. Fetching and publishing with relays down...")
+	down := testrelay.Start(false)
+	down.Close()
+	offline := nostrclient.NewClient([]string{down.URL})
+	offline.SetPrivateKeySigner(nostr.GeneratePrivateKey())
+	if list, err := offline.FetchBookmarks(offline.GetPublicKey(), ""); err == nil {
+		fail("unreachable relays reported as an empty list: %v", list)
+	}
+	if err := offline.PublishBookmarks(&nostrclient.BookmarkList{}); err == nil {
+		fail("publishing to unreachable relays reported success")
+	}
+	mixed := nostrclient.NewClient([]string{down.URL, relay.URL})
+	if list, err := mixed.FetchBookmarks(pubkey, ""); err != nil || list == nil || len(list.Tags) != 3 {
+		fail("one reachable relay should be enough: %v (err %v)", list, err)
+	}
+	fmt.Println("✓ No list assumed and no publish reported without a relay answering")
+
+	fmt.Println("\n=== All Tests Passed! ===")
+}
+
+func isStarred(database *db.DB, id string) bool {
+	starred, _ := database.QueryFeedItems(db.ItemFilter{Starred: true, Limit: -1})
+	for _, item := range starred {
+		if item.ID == id {
+			return true
+		}
+	}
+	return false
+}
+
+func fail(format string, args ...interface{}) {
+	fmt.Printf("❌ "+format+"\n", args...)
+	os.Exit(1)
+}
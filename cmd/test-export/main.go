@@ -0,0 +1,183 @@
+package main
+
+import (
+	"archive/zip"
+	"bytes"
+	"fmt"
+	"image"
+	"image/color"
+	"image/png"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/plebone/nostrfeedz-cli/internal/cache"
+	"github.com/plebone/nostrfeedz-cli/internal/db"
+	"github.com/plebone/nostrfeedz-cli/internal/export"
+)
+
+// Verifies markdown, HTML, EPUB and plain text export of feed items,
+// with cached images embedded or copied next to the files.
+func main() {
+	fmt.Print("=== Export Test ===\n\n")
+
+	var pngData bytes.Buffer
+	img := image.NewRGBA(image.Rect(0, 0, 4, 4))
+	img.Set(2, 2, color.RGBA{B: 255, A: 255})
+	png.Encode(&pngData, img)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "image/png")
+		w.Write(pngData.Bytes())
+	}))
+	defer server.Close()
+
+	tmp, err := os.MkdirTemp("", "nostrfeedz-export")
+	if err != nil {
+		fail("%v", err)
+	}
+	defer os.RemoveAll(tmp)
+	database, err := db.New(filepath.Join(tmp, "test.db"))
+	if err != nil {
+		fail("db: %v", err)
+	}
+	defer database.Close()
+	images, err := cache.NewImageCache(filepath.Join(tmp, "images"), database)
+	if err != nil {
+		fail("cache: %v", err)
+	}
+
+	published := time.Date(2025, 3, 3, 10, 0, 0, 0, time.UTC)
+	database.CreateFeed(&db.Feed{ID: "rss", Type: "rss", URL: "https://blog.example/feed", Title: "Blog", CreatedAt: published})
+	database.CreateFeed(&db.Feed{ID: "nostr", Type: "nostr", NPUB: "npub1test", Title: "Long-form", CreatedAt: published})
+	items := []db.FeedItem{
+		{ID: "post", FeedID: "rss", GUID: "g1", Title: `Running a "Relay": Notes`, Author: "alice",
+			Content: `<p>Short summary.</p>`, URL: "https://blog.example/relay",
+			PublishedAt: published, CreatedAt: published},
+		{ID: "note", FeedID: "nostr", GUID: "g2", Title: "A Long-form Note", Author: "bob",
+			Content: "Intro with a [link](https://example.com/ref).\n\n" +
+				"- first point\n- second point\n\n> quoted words\n\n![diagram](" + server.URL + "/diagram.png)",
+			PublishedAt: published.Add(-time.Hour), CreatedAt: published},
+	}
+	for _, item := range items {
+		if err := database.CreateFeedItem(&item); err != nil {
+			fail("create item: %v", err)
+		}
+	}
+	database.SetItemFullContent("post", `<article><h2>Setup</h2><p>The full article, with <a href="/docs">docs</a>.</p>
+<script>alert(1)</script><pre>strfry --config strfry.conf</pre></article>`)
+	if _, err := images.Download(server.URL + "/diagram.png"); err != nil {
+		fail("download: %v", err)
+	}
+
+	fmt.Println("1. Loading items...")
+	articles, err := export.Load(database, items)
+	if err != nil || len(articles) != 2 {
+		fail("load: %d, %v", len(articles), err)
+	}
+	if articles[0].Feed != "Blog" || !strings.Contains(articles[0].Content, "The full article") {
+		fail("feed title or full article missing: %+v", articles[0])
+	}
+	fmt.Println("   ✓ Feed titles and full articles loaded")
+
+	fmt.Println("\n2. Markdown...")
+	dir := filepath.Join(tmp, "markdown")
+	paths, err := export.Write(export.Markdown, dir, "Digest", articles, images)
+	if err != nil || len(paths) != 2 {
+		fail("markdown: %v, %v", paths, err)
+	}
+	post := read(paths[0])
+	for _, want := range []string{"---\ntitle: \"Running a \\\"Relay\\\": Notes\"\n", "feed: \"Blog\"", "date: 2025-03-03T10:00:00Z",
+		"## Setup", "[docs](https://blog.example/docs)", "strfry --config"} {
+		if !strings.Contains(post, want) {
+			fail("markdown is missing %q:\n%s", want, post)
+		}
+	}
+	if strings.Contains(post, "alert(1)") {
+		fail("markdown kept a script:\n%s", post)
+	}
+	note := read(paths[1])
+	if !strings.Contains(note, "](images/") || !strings.Contains(note, "- first point") {
+		fail("markdown note lost its list or local image:\n%s", note)
+	}
+	copied, _ := filepath.Glob(filepath.Join(dir, "images", "*.png"))
+	if len(copied) != 1 {
+		fail("expected 1 copied image, got %v", copied)
+	}
+	fmt.Printf("   ✓ %s, %s and 1 image\n", filepath.Base(paths[0]), filepath.Base(paths[1]))
+
+	fmt.Println("\n3. HTML digest...")
+	paths, err = export.Write(export.HTML, filepath.Join(tmp, "digest.html"), "Digest", articles, images)
+	if err != nil {
+		fail("html: %v", err)
+	}
+	digest := read(paths[0])
+	if !strings.Contains(digest, "data:image/png;base64,") || !strings.Contains(digest, `href="#article-2"`) {
+		fail("HTML digest lacks embedded images or a table of contents")
+	}
+	fmt.Println("   ✓ Table of contents, images embedded")
+
+	fmt.Println("\n4. EPUB...")
+	paths, err = export.Write(export.EPUB, filepath.Join(tmp, "book.epub"), "Digest", articles, images)
+	if err != nil {
+		fail("epub: %v", err)
+	}
+	zr, err := zip.OpenReader(paths[0])
+	if err != nil {
+		fail("not a zip: %v", err)
+	}
+	names := map[string]bool{}
+	for _, f := range zr.File {
+		names[f.Name] = true
+	}
+	zr.Close()
+	if !names["OEBPS/nav.xhtml"] || !names["OEBPS/article-002.xhtml"] {
+		fail("EPUB is missing its TOC or articles: %v", names)
+	}
+	fmt.Printf("   ✓ %d files\n", len(names))
+
+	fmt.Println("\n5. Plain text...")
+	paths, err = export.Write(export.Text, filepath.Join(tmp, "digest.txt"), "Digest", articles, images)
+	if err != nil {
+		fail("text: %v", err)
+	}
+	text := read(paths[0])
+	for _, want := range []string{"Digest\n======", "Blog · alice · March 3, 2025", "docs <https://blog.example/docs>",
+		"    strfry --config strfry.conf", "- first point", "> quoted words", "[image: diagram]", "* * *"} {
+		if !strings.Contains(text, want) {
+			fail("text is missing %q:\n%s", want, text)
+		}
+	}
+	if strings.Contains(text, "<p>") || strings.Contains(text, "alert(1)") {
+		fail("text kept markup or scripts:\n%s", text)
+	}
+	fmt.Println("   ✓ Lists, quotes, code and links kept as text")
+
+	fmt.Println("\n6. Format names...")
+	for name, want := range map[string]export.Format{"md": export.Markdown, "HTML": export.HTML, ".epub": export.EPUB, "txt": export.Text} {
+		if got, err := export.ParseFormat(name); err != nil || got != want {
+			fail("ParseFormat(%q) = %q, %v", name, got, err)
+		}
+	}
+	if _, err := export.ParseFormat("pdf"); err == nil {
+		fail("pdf should be rejected")
+	}
+	fmt.Println("   ✓ Names and extensions accepted, unknown formats rejected")
+
+	fmt.Println("\n✅ Export works")
+}
+
+func read(path string) string {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		fail("%v", err)
+	}
+	return string(data)
+}
+
+func fail(format string, args ...interface{}) {
+	fmt.Printf("❌ "+format+"\n", args...)
+	os.Exit(1)
+}
@@ -0,0 +1,138 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/plebone/nostrfeedz-cli/internal/db"
+)
+
+// Verifies the item filter query language, filtered queries across feeds,
+// tags and categories, and saved filter views against a temporary database.
+func main() {
+	fmt.Print("=== Item Filter Test ===\n\n")
+
+	fmt.Println("1. Parsing filter queries...")
+	f, err := db.ParseItemFilter(`tag:"long reads" author:jack since:7d is:unread cat:Tech bitcoin https://example.com/a`)
+	if err != nil {
+		fail("parse: %v", err)
+	}
+	if f.Tag != "long reads" || f.Author != "jack" || f.Category != "Tech" || f.Read != db.ReadUnread ||
+		f.Keyword != "bitcoin https://example.com/a" {
+		fail("unexpected filter: %+v", f)
+	}
+	if age := time.Since(f.Since); age < 7*24*time.Hour-time.Minute || age > 7*24*time.Hour+time.Minute {
+		fail("since:7d parsed as %s ago", age)
+	}
+	f, err = db.ParseItemFilter("until:2026-01-02 is:starred feed:f2")
+	if err != nil || !f.Starred || f.Feed != "f2" || f.Until.Format("2006-01-02") != "2026-01-02" {
+		fail("unexpected filter: %+v (err %v)", f, err)
+	}
+	for _, bad := range []string{"is:maybe", "since:yesterday", "until:3x"} {
+		if _, err := db.ParseItemFilter(bad); err == nil {
+			fail("expected an error for %q", bad)
+		}
+	}
+	fmt.Println("✓ Terms, quoted values, relative and absolute dates parsed; URLs kept as keywords")
+
+	dir, _ := os.MkdirTemp("", "filters")
+	defer os.RemoveAll(dir)
+	database, err := db.New(filepath.Join(dir, "feeds.db"))
+	if err != nil {
+		fail("db: %v", err)
+	}
+	defer database.Close()
+
+	now := time.Now()
+	database.CreateCategory(&db.Category{ID: "c1", Name: "Tech"})
+	database.CreateTag(&db.Tag{ID: "t1", Name: "nostr"})
+	database.CreateFeed(&db.Feed{ID: "f1", Type: "rss", URL: "https://example.com/1", Title: "Tech Blog", CategoryID: "c1", CreatedAt: now})
+	database.CreateFeed(&db.Feed{ID: "f2", Type: "rss", URL: "https://example.com/2", Title: "Diary", CreatedAt: now})
+	database.AddFeedTag("f2", "t1")
+	for _, item := range []db.FeedItem{
+		{ID: "a", FeedID: "f1", Title: "Bitcoin fees", Author: "Jack", PublishedAt: now.Add(-time.Hour)},
+		{ID: "b", FeedID: "f1", Title: "Old news", Author: "Ann", PublishedAt: now.Add(-30 * 24 * time.Hour)},
+		{ID: "c", FeedID: "f2", Title: "Relays", Content: "about bitcoin", Author: "jack", PublishedAt: now.Add(-2 * time.Hour)},
+		{ID: "d", FeedID: "f2", Title: "Lunch", Author: "Ann", PublishedAt: now},
+	} {
+		item.GUID, item.CreatedAt = item.ID, now
+		database.CreateFeedItem(&item)
+	}
+	database.ToggleFavorite("d")
+
+	fmt.Println("\n2. Querying items...")
+	for query, want := range map[string]string{
+		"":                      "d a c b",
+		"bitcoin":               "a c",
+		"author:jack since:1d":  "a c",
+		"tag:NOSTR":             "d c",
+		"category:tech":         "a b",
+		"cat:uncategorized":     "d c",
+		"feed:diary is:starred": "d",
+	} {
+		filter, err := db.ParseItemFilter(query)
+		if err != nil {
+			fail("parse %q: %v", query, err)
+		}
+		items, err := database.QueryFeedItems(filter)
+		if err != nil {
+			fail("query %q: %v", query, err)
+		}
+		if got := ids(items); got != want {
+			fail("%q returned %q, want %q", query, got, want)
+		}
+	}
+	fmt.Println("✓ Keyword, author, date, tag, category, feed and starred filters combine, newest first")
+
+	fmt.Println("\n3. Counting and marking a view read...")
+	filter, _ := db.ParseItemFilter("bitcoin")
+	if count, err := database.CountUnreadItems(filter); err != nil || count != 2 {
+		fail("expected 2 unread, got %d (err %v)", count, err)
+	}
+	if count, err := database.MarkFilterRead(filter); err != nil || count != 2 {
+		fail("expected 2 items marked, got %d (err %v)", count, err)
+	}
+	unread, _ := db.ParseItemFilter("is:unread")
+	if items, _ := database.QueryFeedItems(unread); ids(items) != "d b" {
+		fail("unread items left: %q", ids(items))
+	}
+	fmt.Println("✓ Unread items counted and marked read for the filter only")
+
+	fmt.Println("\n4. Saving filter views...")
+	for _, sf := range []db.SavedFilter{
+		{ID: "s1", Name: "Unread nostr", Query: "tag:nostr is:unread"},
+		{ID: "s2", Name: "Jack", Query: "author:jack"},
+	} {
+		sf.CreatedAt = now
+		if err := database.CreateSavedFilter(&sf); err != nil {
+			fail("save %s: %v", sf.Name, err)
+		}
+	}
+	if err := database.CreateSavedFilter(&db.SavedFilter{ID: "s3", Name: "Broken", Query: "is:maybe", CreatedAt: now}); err == nil {
+		fail("saved a filter that does not parse")
+	}
+	database.DeleteSavedFilter("s1")
+	saved, err := database.GetSavedFilters()
+	if err != nil || len(saved) != 1 || saved[0].Name != "Jack" || saved[0].Query != "author:jack" {
+		fail("unexpected saved filters: %+v (err %v)", saved, err)
+	}
+	fmt.Println("✓ Valid filters saved and deleted, invalid queries refused")
+
+	fmt.Println("\n=== All Tests Passed! ===")
+}
+
+func ids(items []db.FeedItem) string {
+	out := make([]string, len(items))
+	for i, item := range items {
+		out[i] = item.ID
+	}
+	return strings.Join(out, " ")
+}
+
+func fail(format string, args ...interface{}) {
+	fmt.Printf("❌ "+format+"\n", args...)
+	os.Exit(1)
+}
@@ -0,0 +1,266 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	stdimage "image"
+	"image/png"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/plebone/nostrfeedz-cli/internal/cache"
+	"github.com/plebone/nostrfeedz-cli/internal/db"
+	"github.com/plebone/nostrfeedz-cli/internal/feed"
+)
+
+// Verifies the image cache index: metadata, least recently used eviction,
+// pinning of starred items' images, expiration and adoption of files cached
+// before the index existed. Also checks the downloader rejects non-images
+// and oversized images, shares concurrent downloads of a URL, and that
+// thumbnails are scaled to fit, refused for huge images and removed with
+// their image, and that inline images are drawn from the cache and memoized.
+func main() {
+	fmt.Print("=== Image Cache Test ===\n\n")
+
+	// Every image is a 1 KB GIF
+	image := append([]byte("GIF89a"), bytes.Repeat([]byte{0}, 1018)...)
+	// A GIF header claiming 65535x65535 pixels
+	huge := []byte("GIF89a\xff\xff\xff\xff\x00\x00\x00")
+	// A real 800x400 picture to make thumbnails of
+	var photo bytes.Buffer
+	png.Encode(&photo, stdimage.NewRGBA(stdimage.Rect(0, 0, 800, 400)))
+	var mu sync.Mutex
+	requests := map[string]int{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		requests[r.URL.Path]++
+		mu.Unlock()
+		switch r.URL.Path {
+		case "/page.png":
+			// Claims to be an image, but is not
+			w.Header().Set("Content-Type", "image/png")
+			w.Write([]byte("<html><body>Not found</body></html>"))
+			return
+		case "/slow.png":
+			time.Sleep(100 * time.Millisecond)
+		case "/huge.png":
+			w.Header().Set("Content-Type", "image/gif")
+			w.Write(huge)
+			return
+		case "/photo.png":
+			w.Header().Set("Content-Type", "image/png")
+			w.Write(photo.Bytes())
+			return
+		}
+		w.Header().Set("Content-Type", "image/gif")
+		w.Write(image)
+	}))
+	defer server.Close()
+
+	tmp, err := os.MkdirTemp("", "nostrfeedz-imagecache")
+	if err != nil {
+		fail("%v", err)
+	}
+	defer os.RemoveAll(tmp)
+	database, err := db.New(filepath.Join(tmp, "test.db"))
+	if err != nil {
+		fail("db: %v", err)
+	}
+	defer database.Close()
+	images, err := cache.NewImageCache(filepath.Join(tmp, "images"), database)
+	if err != nil {
+		fail("cache: %v", err)
+	}
+
+	now := time.Now()
+	database.CreateFeed(&db.Feed{ID: "feed", Type: "rss", URL: server.URL, Title: "Feed", CreatedAt: now})
+	for _, id := range []string{"starred", "plain"} {
+		database.CreateFeedItem(&db.FeedItem{ID: id, FeedID: "feed", GUID: id, Title: id, PublishedAt: now, CreatedAt: now})
+	}
+	database.ToggleFavorite("starred")
+	url := func(name string) string { return server.URL + "/" + name + ".png" }
+
+	fmt.Println("1. Indexing downloads...")
+	if err := images.Reference("starred", []string{url("fav")}); err != nil {
+		fail("reference: %v", err)
+	}
+	for _, name := range []string{"fav", "a", "b", "c"} {
+		if _, err := images.Download(url(name)); err != nil {
+			fail("download %s: %v", name, err)
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	img, err := database.GetCachedImage(url("fav"))
+	if err != nil || img == nil {
+		fail("fav is not indexed: %v", err)
+	}
+	if img.Size != 1024 || img.ContentType != "image/gif" || len(img.ItemIDs) != 1 || img.ItemIDs[0] != "starred" {
+		fail("unexpected index entry: %+v", img)
+	}
+	if size, _ := images.GetCacheSize(); size != 4*1024 {
+		fail("expected 4 KB cached, got %d", size)
+	}
+	fmt.Println("   ✓ Size, content type and referencing items recorded")
+
+	fmt.Println("\n2. Evicting least recently used first...")
+	// Using a makes b the least recently used unpinned image
+	time.Sleep(5 * time.Millisecond)
+	if _, err := images.GetCached(url("a")); err != nil {
+		fail("get a: %v", err)
+	}
+	images.SetLimits(3*1024, cache.DefaultExpiration)
+	if err := images.EnforceSizeLimit(); err != nil {
+		fail("enforce: %v", err)
+	}
+	expect(images, url, map[string]bool{"fav": true, "a": true, "b": false, "c": true})
+	fmt.Println("   ✓ b evicted, recently used a kept")
+
+	fmt.Println("\n3. Pinning starred items' images...")
+	images.SetLimits(1, cache.DefaultExpiration)
+	if err := images.EnforceSizeLimit(); err != nil {
+		fail("enforce: %v", err)
+	}
+	expect(images, url, map[string]bool{"fav": true, "a": false, "c": false})
+	database.ToggleFavorite("starred")
+	images.EnforceSizeLimit()
+	expect(images, url, map[string]bool{"fav": false})
+	fmt.Println("   ✓ Kept while starred, evicted once unstarred")
+
+	fmt.Println("\n4. Expiring unused images...")
+	images.SetLimits(cache.DefaultMaxSize, time.Hour)
+	// A file cached before the index existed, last used two hours ago
+	legacy := images.GetCachePath(url("legacy"))
+	os.WriteFile(legacy, image, 0644)
+	old := now.Add(-2 * time.Hour)
+	os.Chtimes(legacy, old, old)
+	stray := filepath.Join(tmp, "images", "stray.tmp")
+	os.WriteFile(stray, image, 0644)
+	os.Chtimes(stray, old, old)
+	if _, err := images.Download(url("fresh")); err != nil {
+		fail("download: %v", err)
+	}
+	if !images.IsCached(url("legacy")) {
+		fail("legacy file was not adopted")
+	}
+	if err := images.CleanupExpired(); err != nil {
+		fail("cleanup: %v", err)
+	}
+	expect(images, url, map[string]bool{"legacy": false, "fresh": true})
+	if _, err := os.Stat(stray); !os.IsNotExist(err) {
+		fail("old unindexed file was kept")
+	}
+	fmt.Println("   ✓ Old and unindexed files removed, fresh ones kept")
+
+	fmt.Println("\n5. Downloading safely...")
+	if _, err := images.Download(url("page")); err == nil {
+		fail("a page was cached as an image")
+	}
+	images.SetDownloadLimits(512, cache.DownloadTimeout)
+	if _, err := images.Download(url("big")); err == nil {
+		fail("an image over the size limit was cached")
+	}
+	images.SetDownloadLimits(cache.MaxImageBytes, cache.DownloadTimeout)
+	expect(images, url, map[string]bool{"page": false, "big": false})
+	var wg sync.WaitGroup
+	paths := make([]string, 8)
+	for i := range paths {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			path, err := images.Download(url("slow"))
+			if err != nil {
+				fail("download slow: %v", err)
+			}
+			paths[i] = path
+		}(i)
+	}
+	wg.Wait()
+	if requests["/slow.png"] != 1 {
+		fail("expected one request for concurrent downloads, got %d", requests["/slow.png"])
+	}
+	if filepath.Ext(paths[0]) != ".gif" {
+		fail("file not named after its content type: %s", paths[0])
+	}
+	for _, path := range paths[1:] {
+		if path != paths[0] {
+			fail("concurrent downloads returned %s and %s", paths[0], path)
+		}
+	}
+	leftovers, _ := filepath.Glob(filepath.Join(tmp, "images", ".download-*"))
+	if len(leftovers) > 0 {
+		fail("temporary files left behind: %v", leftovers)
+	}
+	fmt.Println("   ✓ Non-images and oversized images rejected, concurrent downloads shared")
+
+	fmt.Println("\n6. Making thumbnails...")
+	thumb, err := images.Thumbnail(url("photo"), cache.ThumbnailWidth, cache.ThumbnailHeight)
+	if err != nil {
+		fail("thumbnail: %v", err)
+	}
+	f, err := os.Open(thumb)
+	if err != nil {
+		fail("open thumbnail: %v", err)
+	}
+	cfg, err := png.DecodeConfig(f)
+	f.Close()
+	if err != nil || cfg.Width != 240 || cfg.Height != 120 {
+		fail("expected a 240x120 thumbnail, got %dx%d (%v)", cfg.Width, cfg.Height, err)
+	}
+	if again, _ := images.Thumbnail(url("photo"), cache.ThumbnailWidth, cache.ThumbnailHeight); again != thumb {
+		fail("thumbnail was not reused")
+	}
+	if _, err := images.Thumbnail(url("c"), cache.ThumbnailWidth, cache.ThumbnailHeight); err == nil {
+		fail("made a thumbnail of an undecodable image")
+	}
+	if _, err := images.Thumbnail(url("huge"), cache.ThumbnailWidth, cache.ThumbnailHeight); err == nil {
+		fail("decoded an image over the pixel limit")
+	}
+	images.SetLimits(1, cache.DefaultExpiration)
+	images.EnforceSizeLimit()
+	if _, err := os.Stat(thumb); !os.IsNotExist(err) {
+		fail("thumbnail kept after its image was evicted")
+	}
+	fmt.Println("   ✓ Scaled to fit, reused and removed with the image, huge images refused")
+
+	fmt.Println("\n7. Drawing images from the cache...")
+	images.SetLimits(cache.DefaultMaxSize, cache.DefaultExpiration)
+	if _, err := feed.NewImageRenderer(images, "braille"); err == nil {
+		fail("accepted an unknown image protocol")
+	}
+	renderer, err := feed.NewImageRenderer(images, "halfblocks")
+	if err != nil {
+		fail("image renderer: %v", err)
+	}
+	before := requests["/photo.png"]
+	drawn, err := renderer.Render(url("photo"), 40, 10)
+	if err != nil || drawn == "" {
+		fail("render: %v", err)
+	}
+	again, err := renderer.Render(url("photo"), 40, 10)
+	if err != nil || again != drawn || requests["/photo.png"] != before+1 {
+		fail("render was not memoized (%d requests)", requests["/photo.png"]-before)
+	}
+	if smaller, _ := renderer.Render(url("photo"), 20, 5); smaller == drawn {
+		fail("a different size reused the same rendering")
+	}
+	fmt.Println("   ✓ Downloaded once through the cache, memoized per size")
+
+	fmt.Println("\n✅ Image cache index works")
+}
+
+func expect(images *cache.ImageCache, url func(string) string, want map[string]bool) {
+	for name, cached := range want {
+		if got := images.IsCached(url(name)); got != cached {
+			fail("%s: cached = %v, want %v", name, got, cached)
+		}
+	}
+}
+
+func fail(format string, args ...interface{}) {
+	fmt.Printf("❌ "+format+"\n", args...)
+	os.Exit(1)
+}
@@ -0,0 +1,97 @@
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/plebone/nostrfeedz-cli/internal/keymap"
+)
+
+// Verifies the keymap presets, config overrides and the detection of keys
+// bound to more than one action.
+func main() {
+	fmt.Print("=== Keymap Test ===\n\n")
+
+	fmt.Println("1. Building the presets...")
+	for _, preset := range keymap.Presets {
+		k, conflicts, err := keymap.New(preset, nil)
+		if err != nil {
+			fail("preset %s: %v", preset, err)
+		}
+		if len(conflicts) > 0 {
+			fail("preset %s has conflicts: %v", preset, conflicts)
+		}
+		if k.Action(keymap.Reader, "q") != keymap.Quit {
+			fail("preset %s: global quit not reachable from the reader", preset)
+		}
+	}
+	if _, _, err := keymap.New("nano", nil); err == nil {
+		fail("expected an error for an unknown preset")
+	}
+	vim, _, _ := keymap.New("vim", nil)
+	if vim.Action(keymap.Feeds, "down") != "" || vim.Action(keymap.Feeds, "j") != keymap.Down {
+		fail("vim preset should move with j only")
+	}
+	fmt.Printf("✓ %d presets build without conflicts, unknown presets rejected\n", len(keymap.Presets))
+
+	fmt.Println("\n2. Overriding keys from the config...")
+	k, conflicts, err := keymap.New("default", map[string]map[string][]string{
+		"reader": {"page_down": {"space", " ctrl+d "}},
+	})
+	if err != nil || len(conflicts) > 0 {
+		fail("override failed: %v %v", err, conflicts)
+	}
+	if k.Action(keymap.Reader, " ") != keymap.PageDown || k.Action(keymap.Reader, "ctrl+d") != keymap.PageDown {
+		fail("overridden keys not bound")
+	}
+	if k.Action(keymap.Reader, "pgdown") == keymap.PageDown {
+		fail("override should replace the preset's keys")
+	}
+	if got := k.Keys(keymap.Reader, keymap.PageDown); got != "space/ctrl+d" {
+		fail("unexpected key label %q", got)
+	}
+	for _, bad := range []map[string]map[string][]string{
+		{"nowhere": {"up": {"k"}}},
+		{"reader": {"teleport": {"t"}}},
+	} {
+		if _, _, err := keymap.New("default", bad); err == nil {
+			fail("expected an error for %v", bad)
+		}
+	}
+	fmt.Println("✓ Overrides replace preset keys, unknown contexts and actions rejected")
+
+	fmt.Println("\n3. Detecting conflicts...")
+	k, conflicts, err = keymap.New("default", map[string]map[string][]string{
+		"feeds":  {"sync": {"j"}},
+		"reader": {"back": {"?"}},
+	})
+	if err != nil {
+		fail("new: %v", err)
+	}
+	want := map[string]bool{
+		"key \"j\" in feeds is bound to feeds.down and feeds.sync":    false,
+		"key \"?\" in reader is bound to global.help and reader.back": false,
+	}
+	for _, c := range conflicts {
+		if _, ok := want[c.Error()]; !ok {
+			fail("unexpected conflict: %s", c)
+		}
+		want[c.Error()] = true
+	}
+	for msg, found := range want {
+		if !found {
+			fail("conflict not reported: %s", msg)
+		}
+	}
+	if k.Action(keymap.Feeds, "j") != keymap.Down || k.Action(keymap.Reader, "?") != keymap.Back {
+		fail("the first action in a context and context over global should win")
+	}
+	fmt.Println("✓ Clashes within a context and with global keys reported, keymap still usable")
+
+	fmt.Println("\n=== All Tests Passed! ===")
+}
+
+func fail(format string, args ...interface{}) {
+	fmt.Printf("❌ "+format+"\n", args...)
+	os.Exit(1)
+}
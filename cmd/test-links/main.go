@@ -0,0 +1,147 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/nbd-wtf/go-nostr"
+	"github.com/plebone/nostrfeedz-cli/internal/feed"
+	nostrclient "github.com/plebone/nostrfeedz-cli/internal/nostr"
+	"github.com/plebone/nostrfeedz-cli/internal/testrelay"
+)
+
+const page = `<html><head><title>A Post</title><meta name="author" content="Jane">
+<link rel="alternate" type="application/rss+xml" href="/feed.xml"></head><body>
+<nav><a href="/">Home</a> <a href="/about">About</a></nav>
+<div class="sidebar"><p>Subscribe to the newsletter, it is great, really, trust me.</p></div>
+<article><h1>A Post</h1>
+<p>The first paragraph has plenty of text, some commas, and a <a href="/next">relative link</a> to the next post.</p>
+<p>The second paragraph links <a href="https://example.org/">elsewhere</a>, with more words, more commas, more content.</p>
+<img src="/photo.jpg">
+</article>
+<div class="comments"><p>Nice post, thanks, very helpful, wow, amazing, love it, ten out of ten.</p></div>
+</body></html>`
+
+const rss = `<?xml version="1.0"?><rss version="2.0"><channel><title>Jane's Blog</title>
+<item><title>A Post</title><link>/post</link></item></channel></rss>`
+
+// Verifies numbered link references, readability extraction of a linked
+// page and feed discovery against a local web server, and reading the
+// subscription list a discovered feed is added to from local relays.
+func main() {
+	fmt.Print("=== Article Links Test ===\n\n")
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/post", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		fmt.Fprint(w, page)
+	})
+	mux.HandleFunc("/feed.xml", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/rss+xml")
+		fmt.Fprint(w, rss)
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	fmt.Println("1. Numbering the links of an article...")
+	renderer, err := feed.NewRenderer(80, "dark")
+	if err != nil {
+		fail("renderer: %v", err)
+	}
+	content := "Read [the docs](/docs) and https://example.com/a. ![img](https://example.com/i.png)\n\n" +
+		"Again [the same](https://example.com/a), [top](#top)\n\n```\nhttps://in.code\n```"
+	links, err := renderer.ExtractLinks(content, false, "https://site.example/posts/1")
+	if err != nil {
+		fail("extract failed: %v", err)
+	}
+	if len(links) != 2 || links[0].URL != "https://site.example/docs" || links[0].Text != "the docs" ||
+		links[1].URL != "https://example.com/a" {
+		fail("unexpected links: %+v", links)
+	}
+	rendered, err := renderer.RenderContent(content, false)
+	if err != nil || !strings.Contains(rendered, "[1]") || !strings.Contains(rendered, "[2]") || strings.Contains(rendered, "[3]") {
+		fail("references missing from the rendered article:\n%s", rendered)
+	}
+	fmt.Println("✓ Relative links resolved, repeats share a number, images, anchors and code skipped")
+
+	fmt.Println("\n2. Reading a linked page...")
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	article, err := feed.FetchArticle(ctx, server.URL+"/post")
+	if err != nil {
+		fail("fetch failed: %v", err)
+	}
+	if article.Title != "A Post" || article.Byline != "Jane" {
+		fail("unexpected title or byline: %q %q", article.Title, article.Byline)
+	}
+	for _, want := range []string{"first paragraph", "second paragraph", `href="` + server.URL + `/next"`, `src="` + server.URL + `/photo.jpg"`} {
+		if !strings.Contains(article.Content, want) {
+			fail("content is missing %q:\n%s", want, article.Content)
+		}
+	}
+	for _, unwanted := range []string{"newsletter", "ten out of ten", "About"} {
+		if strings.Contains(article.Content, unwanted) {
+			fail("content kept boilerplate %q:\n%s", unwanted, article.Content)
+		}
+	}
+	fmt.Println("✓ Main content kept with absolute URLs, navigation, sidebar and comments dropped")
+
+	fmt.Println("\n3. Discovering feeds...")
+	for _, link := range []string{server.URL + "/post", server.URL + "/feed.xml"} {
+		found, err := feed.DiscoverFeed(ctx, link)
+		if err != nil || found.URL != server.URL+"/feed.xml" || found.Title != "Jane's Blog" {
+			fail("discovery from %s failed: %+v (err %v)", link, found, err)
+		}
+	}
+	if _, err := feed.DiscoverFeed(ctx, server.URL+"/missing"); err == nil {
+		fail("expected an error for a missing page")
+	}
+	fmt.Println("✓ Feed found from the page's alternate link and from the feed URL itself")
+
+	fmt.Println("\n4. Reading the subscription list to add a feed to...")
+	sk := nostr.GeneratePrivateKey()
+	pubkey, _ := nostr.GetPublicKey(sk)
+	stale, current := testrelay.Start(false), testrelay.Start(false)
+	defer stale.Close()
+	defer current.Close()
+	for _, published := range []struct {
+		relay   *testrelay.Relay
+		rss     string
+		created nostr.Timestamp
+	}{
+		{stale, "https://old.example/feed", nostr.Now() - 3600},
+		{current, "https://new.example/feed", nostr.Now()},
+	} {
+		ev := nostr.Event{
+			Kind:      nostrclient.SubscriptionListKind,
+			CreatedAt: published.created,
+			Tags:      nostr.Tags{{"d", nostrclient.SubscriptionDTag}},
+			Content:   `{"rss":["` + published.rss + `"]}`,
+		}
+		ev.Sign(sk)
+		published.relay.Publish(&ev)
+	}
+	client := nostrclient.NewClient([]string{stale.URL, current.URL})
+	list, err := client.FetchSubscriptions(pubkey)
+	if err != nil || list == nil || len(list.RSS) != 1 || list.RSS[0] != "https://new.example/feed" {
+		fail("expected the newest list, got %+v (err %v)", list, err)
+	}
+	down := testrelay.Start(false)
+	down.Close()
+	if list, err := nostrclient.NewClient([]string{down.URL}).FetchSubscriptions(pubkey); err == nil {
+		fail("unreachable relays reported as no list: %+v", list)
+	}
+	fmt.Println("✓ Newest copy across relays used, unreachable relays reported as an error")
+
+	fmt.Println("\n=== All Tests Passed! ===")
+}
+
+func fail(format string, args ...interface{}) {
+	fmt.Printf("❌ "+format+"\n", args...)
+	os.Exit(1)
+}
@@ -0,0 +1,88 @@
+package main
+
+import (
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/plebone/nostrfeedz-cli/internal/logging"
+)
+
+// Verifies level parsing, size-based log rotation with a bounded number of
+// backups, and the in-memory entries shown by the log viewer.
+func main() {
+	fmt.Print("=== Logging Test ===\n\n")
+
+	fmt.Println("1. Parsing levels...")
+	for name, want := range map[string]slog.Level{
+		"debug": slog.LevelDebug, "INFO": slog.LevelInfo, "warning": slog.LevelWarn,
+		"error": slog.LevelError, "": slog.LevelInfo, "verbose": slog.LevelInfo,
+	} {
+		if got := logging.ParseLevel(name); got != want {
+			fail("ParseLevel(%q) = %s, want %s", name, got, want)
+		}
+	}
+	fmt.Println("✓ Level names parsed, unknown names fall back to info")
+
+	dir, _ := os.MkdirTemp("", "logging")
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "logs", "nostrfeedz.log")
+	closer, err := logging.Setup(logging.Options{Path: path, Level: slog.LevelInfo, MaxSize: 1024, MaxBackups: 2})
+	if err != nil {
+		fail("setup: %v", err)
+	}
+
+	fmt.Println("\n2. Rotating the log file...")
+	line := strings.Repeat("x", 100)
+	for i := 0; i < 60; i++ {
+		slog.Info("filler", "n", i, "text", line)
+	}
+	closer.Close()
+	for _, name := range []string{path, path + ".1", path + ".2"} {
+		info, err := os.Stat(name)
+		if err != nil {
+			fail("%s missing: %v", filepath.Base(name), err)
+		}
+		if info.Size() > 1024 {
+			fail("%s grew to %d bytes past the 1024 byte limit", filepath.Base(name), info.Size())
+		}
+	}
+	if _, err := os.Stat(path + ".3"); err == nil {
+		fail("more backups kept than configured")
+	}
+	current, _ := os.ReadFile(path)
+	if !strings.Contains(string(current), "n=59") {
+		fail("newest entry not in the current file:\n%s", current)
+	}
+	fmt.Println("✓ Rotated at 1 KB, two backups kept, newest entries in the current file")
+
+	fmt.Println("\n3. Keeping recent entries for the viewer...")
+	closer, err = logging.Setup(logging.Options{Path: path, Level: slog.LevelWarn})
+	if err != nil {
+		fail("setup: %v", err)
+	}
+	defer closer.Close()
+	slog.Info("below the level")
+	slog.With("relay", "wss://nos.lol").WithGroup("req").Warn("relay slow", "ms", 900)
+	entries := logging.Recent(1)
+	if len(entries) != 1 {
+		fail("expected 1 entry, got %d", len(entries))
+	}
+	e := entries[0]
+	if e.Message != "relay slow" || e.Level != slog.LevelWarn || e.Attrs != "relay=wss://nos.lol req.ms=900" {
+		fail("unexpected entry: %+v", e)
+	}
+	if all := logging.Recent(0); len(all) != 61 || all[0].Message != "filler" {
+		fail("expected the 60 fillers and the warning oldest first, got %d entries", len(all))
+	}
+	fmt.Println("✓ Entries below the level dropped, attributes and groups flattened, oldest first")
+
+	fmt.Println("\n=== All Tests Passed! ===")
+}
+
+func fail(format string, args ...interface{}) {
+	fmt.Printf("❌ "+format+"\n", args...)
+	os.Exit(1)
+}
@@ -0,0 +1,80 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/plebone/nostrfeedz-cli/internal/db"
+)
+
+// Verifies bulk read toggles: marking a set of items read or unread, and
+// marking whole feeds read without touching other feeds.
+func main() {
+	fmt.Print("=== Mark Read Test ===\n\n")
+
+	dir, _ := os.MkdirTemp("", "markread")
+	defer os.RemoveAll(dir)
+	database, err := db.New(filepath.Join(dir, "feeds.db"))
+	if err != nil {
+		fail("db: %v", err)
+	}
+	defer database.Close()
+
+	now := time.Now()
+	items := map[string][]string{"f1": {"a", "b", "c", "d"}, "f2": {"x", "y"}, "f3": {"z"}}
+	for feedID, ids := range items {
+		database.CreateFeed(&db.Feed{ID: feedID, Type: "rss", URL: "https://example.com/" + feedID, Title: feedID, CreatedAt: now})
+		for _, id := range ids {
+			database.CreateFeedItem(&db.FeedItem{ID: id, FeedID: feedID, GUID: id, Title: "Item " + id, PublishedAt: now, CreatedAt: now})
+		}
+	}
+
+	fmt.Println("1. Marking a set of items...")
+	if err := database.MarkItemsRead([]string{"a", "b", "c"}, true); err != nil {
+		fail("mark read: %v", err)
+	}
+	if err := database.MarkItemsRead([]string{"a"}, false); err != nil {
+		fail("mark unread: %v", err)
+	}
+	if err := database.MarkItemsRead(nil, true); err != nil {
+		fail("empty set: %v", err)
+	}
+	expectUnread(database, map[string]int{"f1": 2, "f2": 2, "f3": 1})
+	fmt.Println("✓ Items marked read and back to unread in one transaction")
+
+	fmt.Println("\n2. Marking whole feeds read...")
+	count, err := database.MarkFeedsRead([]string{"f1", "f2"})
+	if err != nil || count != 4 {
+		fail("expected 4 items changed, got %d (err %v)", count, err)
+	}
+	expectUnread(database, map[string]int{"f3": 1})
+	if count, err := database.MarkFeedsRead([]string{"f1"}); err != nil || count != 0 {
+		fail("already read feed changed %d items (err %v)", count, err)
+	}
+	if count, err := database.MarkFeedsRead(nil); err != nil || count != 0 {
+		fail("no feeds changed %d items (err %v)", count, err)
+	}
+	fmt.Println("✓ Only unread items of the chosen feeds counted, other feeds untouched")
+
+	fmt.Println("\n=== All Tests Passed! ===")
+}
+
+// expectUnread checks the unread count of every feed, missing feeds meaning 0
+func expectUnread(database *db.DB, want map[string]int) {
+	counts, err := database.GetUnreadCounts()
+	if err != nil {
+		fail("unread counts: %v", err)
+	}
+	for _, feedID := range []string{"f1", "f2", "f3"} {
+		if counts[feedID] != want[feedID] {
+			fail("feed %s has %d unread, want %d", feedID, counts[feedID], want[feedID])
+		}
+	}
+}
+
+func fail(format string, args ...interface{}) {
+	fmt.Printf("❌ "+format+"\n", args...)
+	os.Exit(1)
+}
@@ -0,0 +1,209 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/plebone/nostrfeedz-cli/internal/media"
+	"github.com/plebone/nostrfeedz-cli/internal/testplayer"
+)
+
+// logEnv names the file the fake programs log their starts to
+const logEnv = "TEST_MEDIA_LOG"
+
+// Verifies the media launcher: program detection, command templates,
+// reaping replaced programs, and reusing an mpv window over IPC. The fake
+// viewer and mpv are this program itself, linked under their names.
+func main() {
+	switch filepath.Base(os.Args[0]) {
+	case "viewer":
+		fakeViewer()
+		return
+	case "mpv":
+		fakeMPV()
+		return
+	}
+
+	fmt.Print("=== Media Launcher Test ===\n\n")
+
+	dir, _ := os.MkdirTemp("", "media")
+	defer os.RemoveAll(dir)
+	self, err := os.Executable()
+	if err != nil {
+		fail("executable: %v", err)
+	}
+	for _, name := range []string{"viewer", "mpv"} {
+		if err := os.Symlink(self, filepath.Join(dir, name)); err != nil {
+			fail("link %s: %v", name, err)
+		}
+	}
+	logPath := filepath.Join(dir, "starts.log")
+	os.Setenv(logEnv, logPath)
+	socket := filepath.Join(dir, "video.sock")
+
+	fmt.Println("1. Detecting programs...")
+	launcher := media.NewLauncher(
+		[]string{"nostrfeedz-missing-viewer {url}", filepath.Join(dir, "viewer") + " --geometry 800x600 {url}"},
+		[]string{filepath.Join(dir, "mpv") + " --geometry=800x600 {url}", filepath.Join(dir, "viewer")},
+		socket)
+	viewers, players := launcher.Detect()
+	if !slices.Equal(viewers, []string{"viewer"}) || !slices.Equal(players, []string{"mpv", "viewer"}) {
+		fail("detected viewers %v, players %v", viewers, players)
+	}
+	if err := media.NewLauncher([]string{"nostrfeedz-missing-viewer"}, nil, socket).OpenImage("a.png"); !errors.Is(err, media.ErrNoProgram) {
+		fail("expected ErrNoProgram, got %v", err)
+	}
+	fmt.Println("   ✓ Missing programs skipped, order kept")
+
+	fmt.Println("\n2. Replacing the image viewer...")
+	if err := launcher.OpenImage("/tmp/a.png"); err != nil {
+		fail("open image: %v", err)
+	}
+	first := waitForStarts(logPath, 1)[0]
+	if first.args != "--geometry 800x600 /tmp/a.png" {
+		fail("template not expanded: %q", first.args)
+	}
+	if err := launcher.OpenImage("/tmp/b.png"); err != nil {
+		fail("open second image: %v", err)
+	}
+	second := waitForStarts(logPath, 2)[1]
+	if !reaped(first.pid) {
+		fail("first viewer (pid %d) still exists", first.pid)
+	}
+	fmt.Println("   ✓ Previous viewer killed and reaped, no zombie left")
+
+	fmt.Println("\n3. Reusing the mpv window...")
+	if err := launcher.OpenVideo("https://example.com/one.mp4"); err != nil {
+		fail("open video: %v", err)
+	}
+	mpv := waitForStarts(logPath, 3)[2]
+	if mpv.name != "mpv" || !strings.HasPrefix(mpv.args, "--input-ipc-server="+socket+" ") {
+		fail("mpv started without its IPC socket: %+v", mpv)
+	}
+	waitFor(func() bool { _, err := os.Stat(socket); return err == nil }, "the mpv socket")
+	if err := launcher.OpenVideo("https://example.com/two.mp4"); err != nil {
+		fail("next video: %v", err)
+	}
+	starts := waitForStarts(logPath, 4)
+	if loaded := starts[3]; loaded.pid != mpv.pid || loaded.args != "loadfile https://example.com/two.mp4" {
+		fail("next video not loaded in the same mpv: %+v", loaded)
+	}
+	fmt.Println("   ✓ Next video loaded over IPC instead of a new window")
+
+	fmt.Println("\n4. Restarting a closed player...")
+	syscall.Kill(mpv.pid, syscall.SIGTERM) // The window was closed
+	waitFor(func() bool { return reaped(mpv.pid) }, "mpv to exit")
+	if err := launcher.OpenVideo("https://example.com/three.mp4"); err != nil {
+		fail("video after close: %v", err)
+	}
+	restarted := waitForStarts(logPath, 5)[4]
+	if restarted.name != "mpv" || restarted.pid == mpv.pid || !strings.HasSuffix(restarted.args, "three.mp4") {
+		fail("player not restarted: %+v", restarted)
+	}
+	fmt.Println("   ✓ A new player started once the old one was gone")
+
+	fmt.Println("\n5. Closing...")
+	launcher.Close()
+	for _, pid := range []int{second.pid, restarted.pid} {
+		if !reaped(pid) {
+			fail("pid %d left behind", pid)
+		}
+	}
+	fmt.Println("   ✓ Viewer and player stopped and reaped")
+
+	fmt.Println("\n=== All Tests Passed! ===")
+}
+
+type start struct {
+	name string
+	pid  int
+	args string
+}
+
+// logStart records a start of a fake program
+func logStart(args string) {
+	f, err := os.OpenFile(os.Getenv(logEnv), os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		os.Exit(2)
+	}
+	fmt.Fprintf(f, "%s\t%d\t%s\n", filepath.Base(os.Args[0]), os.Getpid(), args)
+	f.Close()
+}
+
+// fakeViewer logs its arguments and waits to be killed
+func fakeViewer() {
+	logStart(strings.Join(os.Args[1:], " "))
+	time.Sleep(time.Minute)
+}
+
+// fakeMPV listens on the IPC socket it was given, logging the files it is
+// told to load, until it quits or is killed
+func fakeMPV() {
+	logStart(strings.Join(os.Args[1:], " "))
+	var socket string
+	for _, arg := range os.Args[1:] {
+		if s, ok := strings.CutPrefix(arg, "--input-ipc-server="); ok {
+			socket = s
+		}
+	}
+	server, err := testplayer.Listen(socket, 60)
+	if err != nil {
+		os.Exit(2)
+	}
+	server.OnLoad = func(url string) { logStart("loadfile " + url) }
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, syscall.SIGTERM)
+	select {
+	case <-server.Quit():
+	case <-signals:
+	case <-time.After(time.Minute):
+	}
+	server.Close()
+}
+
+// waitForStarts waits until n starts were logged
+func waitForStarts(path string, n int) []start {
+	var starts []start
+	waitFor(func() bool {
+		data, _ := os.ReadFile(path)
+		starts = starts[:0]
+		for _, line := range strings.Split(strings.TrimSpace(string(data)), "\n") {
+			fields := strings.SplitN(line, "\t", 3)
+			if len(fields) != 3 {
+				continue
+			}
+			pid, _ := strconv.Atoi(fields[1])
+			starts = append(starts, start{name: fields[0], pid: pid, args: fields[2]})
+		}
+		return len(starts) >= n
+	}, fmt.Sprintf("%d program starts", n))
+	return starts
+}
+
+// reaped reports whether a process is gone entirely; a zombie still exists
+func reaped(pid int) bool {
+	return syscall.Kill(pid, 0) == syscall.ESRCH
+}
+
+func waitFor(cond func() bool, what string) {
+	deadline := time.Now().Add(5 * time.Second)
+	for !cond() {
+		if time.Now().After(deadline) {
+			fail("timed out waiting for %s", what)
+		}
+		time.Sleep(20 * time.Millisecond)
+	}
+}
+
+func fail(format string, args ...interface{}) {
+	fmt.Printf("❌ "+format+"\n", args...)
+	os.Exit(1)
+}
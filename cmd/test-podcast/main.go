@@ -0,0 +1,206 @@
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"slices"
+	"time"
+
+	"github.com/plebone/nostrfeedz-cli/internal/db"
+	"github.com/plebone/nostrfeedz-cli/internal/feed"
+	"github.com/plebone/nostrfeedz-cli/internal/player"
+	"github.com/plebone/nostrfeedz-cli/internal/testplayer"
+)
+
+const podcastFeed = `<?xml version="1.0"?>
+<rss version="2.0" xmlns:itunes="http://www.itunes.com/dtds/podcast-1.0.dtd">
+<channel>
+  <title>Test Cast</title>
+  <item>
+    <title>Episode 1</title>
+    <guid>ep1</guid>
+    <itunes:duration>1:02:03</itunes:duration>
+    <enclosure url="https://example.com/ep1.mp3" type="audio/mpeg" length="12345678"/>
+    <enclosure url="https://example.com/ep1.jpg" type="image/jpeg" length="2048"/>
+  </item>
+  <item>
+    <title>Episode 2</title>
+    <guid>ep2</guid>
+    <itunes:duration>45:30</itunes:duration>
+    <enclosure url="https://example.com/ep2.ogg" type="x" length="oops"/>
+  </item>
+  <item>
+    <title>Episode 3</title>
+    <guid>ep3</guid>
+    <itunes:duration>90</itunes:duration>
+    <enclosure url="https://example.com/ep3.m4a" type="audio/mp4" length="100"/>
+  </item>
+  <item>
+    <title>Just a post</title>
+    <guid>post</guid>
+  </item>
+</channel>
+</rss>`
+
+// Verifies storing podcast enclosures, the episode queue with remembered
+// positions, and driving playback over mpv's IPC protocol against a fake
+// mpv socket.
+func main() {
+	fmt.Print("=== Podcast Test ===\n\n")
+
+	dir, _ := os.MkdirTemp("", "podcast")
+	defer os.RemoveAll(dir)
+	database, err := db.New(filepath.Join(dir, "feeds.db"))
+	if err != nil {
+		fail("db: %v", err)
+	}
+	defer database.Close()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/rss+xml")
+		fmt.Fprint(w, podcastFeed)
+	}))
+	defer server.Close()
+
+	fmt.Println("1. Fetching a podcast feed...")
+	database.CreateFeed(&db.Feed{ID: "f1", Type: "rss", URL: server.URL, Title: "Test Cast", CreatedAt: time.Now()})
+	items, err := feed.NewFetcher(nil).FetchRSSArticles(server.URL, "f1")
+	if err != nil {
+		fail("fetch: %v", err) // A panic on the short type would not get here
+	}
+	ids := make(map[string]string)
+	for _, item := range items {
+		if err := database.CreateFeedItem(item); err != nil {
+			fail("store %s: %v", item.GUID, err)
+		}
+		ids[item.GUID] = item.ID
+	}
+	encs, err := database.GetEnclosures(ids["ep1"])
+	if err != nil || len(encs) != 2 {
+		fail("expected both enclosures of episode 1, got %+v (err %v)", encs, err)
+	}
+	if encs[0].Type != "audio/mpeg" || encs[0].Length != 12345678 || encs[0].Duration != time.Hour+2*time.Minute+3*time.Second {
+		fail("audio enclosure not stored as sent: %+v", encs[0])
+	}
+	if items[0].Thumbnail != "https://example.com/ep1.jpg" {
+		fail("image enclosure not used as thumbnail: %q", items[0].Thumbnail)
+	}
+	if encs, _ := database.GetEnclosures(ids["ep2"]); len(encs) != 1 || encs[0].Length != 0 || encs[0].Duration != 45*time.Minute+30*time.Second {
+		fail("episode 2 enclosure wrong: %+v", encs)
+	}
+	if ep, err := database.GetEpisode(ids["post"]); err != nil || ep != nil {
+		fail("a post without enclosures has an episode: %+v (err %v)", ep, err)
+	}
+	fmt.Println("   ✓ Every enclosure stored with type, length and duration; short types don't panic")
+
+	fmt.Println("\n2. Refetching keeps positions...")
+	if err := database.SaveEpisodePosition(ids["ep1"], "https://example.com/ep1.mp3", 20*time.Minute, 0); err != nil {
+		fail("save position: %v", err)
+	}
+	again, _ := feed.NewFetcher(nil).FetchRSSArticles(server.URL, "f1")
+	for _, item := range again {
+		database.CreateFeedItem(item) // New IDs, same GUIDs
+	}
+	ep, err := database.GetEpisode(ids["ep1"])
+	if err != nil || ep == nil || ep.Position != 20*time.Minute || ep.Duration != time.Hour+2*time.Minute+3*time.Second {
+		fail("position or duration lost: %+v (err %v)", ep, err)
+	}
+	if encs, _ := database.GetEnclosures(ids["ep1"]); len(encs) != 2 {
+		fail("refetch duplicated enclosures: %+v", encs)
+	}
+	fmt.Println("   ✓ Enclosures attached to the known items, positions kept")
+
+	fmt.Println("\n3. Queueing episodes...")
+	for _, guid := range []string{"ep3", "ep1", "ep2", "ep3"} {
+		ep, _ := database.GetEpisode(ids[guid])
+		if ep == nil {
+			fail("%s has no episode", guid)
+		}
+		if err := database.EnqueueEpisode(ids[guid], ep.URL); err != nil {
+			fail("enqueue %s: %v", guid, err)
+		}
+	}
+	database.DequeueEpisode(ids["ep1"])
+	database.EnqueueEpisode(ids["ep1"], "https://example.com/ep1.mp3")
+	queue, err := database.GetPodcastQueue()
+	if err != nil {
+		fail("queue: %v", err)
+	}
+	var titles []string
+	for _, ep := range queue {
+		titles = append(titles, ep.Title)
+	}
+	if !slices.Equal(titles, []string{"Episode 3", "Episode 2", "Episode 1"}) {
+		fail("unexpected queue order %v", titles)
+	}
+	if queue[2].Position != 20*time.Minute || queue[2].FeedTitle != "Test Cast" {
+		fail("queued episode lacks its position or feed: %+v", queue[2])
+	}
+	fmt.Println("   ✓ Queued once each, in order, with remembered positions")
+
+	fmt.Println("\n4. Controlling mpv over IPC...")
+	mpv, err := testplayer.Start(600)
+	if err != nil {
+		fail("fake mpv: %v", err)
+	}
+	defer mpv.Close()
+	p, err := player.Dial(mpv.Socket)
+	if err != nil {
+		fail("dial: %v", err)
+	}
+	if err := p.Seek(90 * time.Second); err != nil {
+		fail("seek: %v", err)
+	}
+	p.Seek(-30 * time.Second)
+	if err := p.SetSpeed(1.5); err != nil {
+		fail("speed: %v", err)
+	}
+	if err := p.TogglePause(); err != nil {
+		fail("pause: %v", err)
+	}
+	status, err := p.Status()
+	if err != nil {
+		fail("status: %v", err)
+	}
+	if status.Position != time.Minute || status.Duration != 10*time.Minute || status.Speed != 1.5 || !status.Paused {
+		fail("unexpected status %+v", status)
+	}
+	fmt.Printf("   ✓ Seek, speed and pause applied: %+v\n", status)
+
+	mpv.Finish()
+	select {
+	case <-p.Done():
+	case <-time.After(5 * time.Second):
+		fail("player not done after the file ended")
+	}
+	if !p.Finished() {
+		fail("end of file not noticed")
+	}
+	if _, err := p.Status(); err == nil {
+		fail("commands still succeed after mpv quit")
+	}
+	fmt.Println("   ✓ End of file noticed, so the queue can move on")
+
+	p, err = player.Dial(mpv.Socket)
+	if err != nil {
+		fail("redial: %v", err)
+	}
+	p.Stop()
+	if p.Finished() {
+		fail("a stopped episode counts as finished")
+	}
+	if cmds := mpv.Commands(); cmds[len(cmds)-1] != "quit" {
+		fail("stop did not quit mpv: %v", cmds)
+	}
+	fmt.Println("   ✓ Stopping quits mpv without finishing the episode")
+
+	fmt.Println("\n=== All Tests Passed! ===")
+}
+
+func fail(format string, args ...interface{}) {
+	fmt.Printf("❌ "+format+"\n", args...)
+	os.Exit(1)
+}
@@ -0,0 +1,88 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/plebone/nostrfeedz-cli/internal/db"
+)
+
+// Verifies saving reading progress and the "Continue Reading" filter
+// against a temporary database.
+func main() {
+	fmt.Print("=== Reading Progress Test ===\n\n")
+
+	dir, _ := os.MkdirTemp("", "progress")
+	defer os.RemoveAll(dir)
+	database, err := db.New(filepath.Join(dir, "feeds.db"))
+	if err != nil {
+		fail("db: %v", err)
+	}
+	defer database.Close()
+
+	now := time.Now()
+	database.CreateFeed(&db.Feed{ID: "f1", Type: "rss", URL: "https://example.com/feed", Title: "Example", CreatedAt: now})
+	for _, id := range []string{"a", "b", "c", "d"} {
+		database.CreateFeedItem(&db.FeedItem{ID: id, FeedID: "f1", GUID: id, Title: "Item " + id, PublishedAt: now, CreatedAt: now})
+	}
+
+	fmt.Println("1. Saving and loading progress...")
+	if p, err := database.GetReadingProgress("a"); err != nil || p != nil {
+		fail("expected no progress for an unopened item: %+v (err %v)", p, err)
+	}
+	for _, p := range []db.ReadingProgress{
+		{ItemID: "a", ScrollOffset: 40, Percent: 55, OpenedAt: now.Add(-time.Hour)},
+		{ItemID: "b", ScrollOffset: 10, Percent: 20, OpenedAt: now},
+		{ItemID: "c", ScrollOffset: 90, Percent: 100, OpenedAt: now}, // Finished
+		{ItemID: "d", ScrollOffset: 0, Percent: 30, OpenedAt: now},   // Opened, never scrolled
+	} {
+		if err := database.SaveReadingProgress(&p); err != nil {
+			fail("save failed: %v", err)
+		}
+	}
+	p, err := database.GetReadingProgress("a")
+	if err != nil || p == nil || p.ScrollOffset != 40 || p.Percent != 55 || !p.InProgress() {
+		fail("progress did not round-trip: %+v (err %v)", p, err)
+	}
+	all, err := database.GetReadingProgressFor([]string{"a", "b", "c", "d", "missing"})
+	if err != nil || len(all) != 4 || all["b"].Percent != 20 {
+		fail("unexpected progress map: %+v (err %v)", all, err)
+	}
+	opened := now.Add(-2 * time.Hour)
+	database.CreateFeedItem(&db.FeedItem{ID: "e", FeedID: "f1", GUID: "e", Title: "Item e", PublishedAt: now, CreatedAt: now})
+	for _, id := range []string{"a", "e"} {
+		if err := database.RecordOpened(id, opened); err != nil {
+			fail("record open failed: %v", err)
+		}
+	}
+	if p, _ := database.GetReadingProgress("a"); p == nil || p.ScrollOffset != 40 || p.Percent != 55 || !p.OpenedAt.Equal(opened.Truncate(time.Second)) {
+		fail("opening moved the saved position: %+v", p)
+	}
+	if p, _ := database.GetReadingProgress("e"); p == nil || p.InProgress() || !p.OpenedAt.Equal(opened.Truncate(time.Second)) {
+		fail("open before rendering not recorded: %+v", p)
+	}
+	fmt.Println("✓ Offset, percentage and open time stored per item, opens recorded before rendering")
+
+	fmt.Println("\n2. Listing partially read items...")
+	filter, err := db.ParseItemFilter("is:reading")
+	if err != nil || !filter.InProgress {
+		fail("is:reading not parsed: %+v (err %v)", filter, err)
+	}
+	items, err := database.QueryFeedItems(filter)
+	if err != nil {
+		fail("query failed: %v", err)
+	}
+	if len(items) != 2 || items[0].ID != "b" || items[1].ID != "a" {
+		fail("expected b then a, got %v", items)
+	}
+	fmt.Println("✓ Finished and unscrolled items left out, most recently opened first")
+
+	fmt.Println("\n=== All Tests Passed! ===")
+}
+
+func fail(format string, args ...interface{}) {
+	fmt.Printf("❌ "+format+"\n", args...)
+	os.Exit(1)
+}
@@ -0,0 +1,185 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/plebone/nostrfeedz-cli/internal/db"
+	"github.com/plebone/nostrfeedz-cli/internal/feed"
+)
+
+// expected is what a fixture's article must look like, stored next to the
+// saved page as <name>.json
+type expected struct {
+	URL      string   `json:"url"`
+	Title    string   `json:"title"`
+	Byline   string   `json:"byline"`
+	Contains []string `json:"contains"`
+	Excludes []string `json:"excludes"`
+}
+
+// Verifies readability extraction against saved pages in
+// internal/feed/testdata/readability (or the directory given as the first
+// argument), then the per-feed full content storage.
+func main() {
+	fmt.Print("=== Full Article Extraction Test ===\n\n")
+
+	dir := filepath.Join("internal", "feed", "testdata", "readability")
+	if len(os.Args) > 1 {
+		dir = os.Args[1]
+	}
+	pages, err := filepath.Glob(filepath.Join(dir, "*.html"))
+	if err != nil || len(pages) == 0 {
+		fail("no fixtures in %s (run from the repository root)", dir)
+	}
+
+	fmt.Println("1. Extracting saved pages...")
+	for _, page := range pages {
+		checkFixture(page)
+	}
+
+	fmt.Println("\n2. Storing full content next to the summary...")
+	checkStorage(pages[0])
+
+	fmt.Println("\n✅ Full article extraction works")
+}
+
+func checkFixture(page string) {
+	name := strings.TrimSuffix(filepath.Base(page), ".html")
+	data, err := os.ReadFile(strings.TrimSuffix(page, ".html") + ".json")
+	if err != nil {
+		fail("%s: %v", name, err)
+	}
+	var want expected
+	if err := json.Unmarshal(data, &want); err != nil {
+		fail("%s: bad expectations: %v", name, err)
+	}
+
+	f, err := os.Open(page)
+	if err != nil {
+		fail("%s: %v", name, err)
+	}
+	defer f.Close()
+	article, err := feed.ExtractArticle(f, want.URL)
+	if err != nil {
+		fail("%s: extraction failed: %v", name, err)
+	}
+
+	if article.Title != want.Title {
+		fail("%s: title %q, want %q", name, article.Title, want.Title)
+	}
+	if article.Byline != want.Byline {
+		fail("%s: byline %q, want %q", name, article.Byline, want.Byline)
+	}
+	for _, s := range want.Contains {
+		if !strings.Contains(article.Content, s) {
+			fail("%s: content is missing %q:\n%s", name, s, article.Content)
+		}
+	}
+	for _, s := range want.Excludes {
+		if strings.Contains(article.Content, s) {
+			fail("%s: content should not contain %q:\n%s", name, s, article.Content)
+		}
+	}
+	fmt.Printf("   ✓ %s: %q, %d bytes\n", name, article.Title, len(article.Content))
+}
+
+func checkStorage(page string) {
+	html, err := os.ReadFile(page)
+	if err != nil {
+		fail("%v", err)
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/missing" {
+			http.NotFound(w, r)
+			return
+		}
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		w.Write(html)
+	}))
+	defer server.Close()
+
+	tmp, err := os.MkdirTemp("", "nostrfeedz-readability")
+	if err != nil {
+		fail("%v", err)
+	}
+	defer os.RemoveAll(tmp)
+	database, err := db.New(filepath.Join(tmp, "test.db"))
+	if err != nil {
+		fail("db: %v", err)
+	}
+	defer database.Close()
+
+	now := time.Now()
+	if err := database.CreateFeed(&db.Feed{ID: "f1", Type: "rss", URL: server.URL + "/feed", Title: "Blog", CreatedAt: now}); err != nil {
+		fail("create feed: %v", err)
+	}
+	if err := database.SetFeedFullContent("f1", true); err != nil {
+		fail("enable full content: %v", err)
+	}
+	feeds, err := database.GetFeeds()
+	if err != nil || len(feeds) != 1 || !feeds[0].FullContent {
+		fail("feed setting not stored: %+v, %v", feeds, err)
+	}
+	fmt.Println("   ✓ Per-feed setting stored")
+
+	summary := "<p>A short summary…</p>"
+	for _, item := range []db.FeedItem{
+		{ID: "i1", FeedID: "f1", GUID: "g1", Title: "Post", Content: summary, URL: server.URL + "/post", PublishedAt: now, CreatedAt: now},
+		{ID: "i2", FeedID: "f1", GUID: "g2", Title: "Gone", Content: summary, URL: server.URL + "/missing", PublishedAt: now.Add(-time.Hour), CreatedAt: now},
+	} {
+		if err := database.CreateFeedItem(&item); err != nil {
+			fail("create item: %v", err)
+		}
+	}
+
+	pending, err := database.GetItemsWithoutFullContent("f1", 10)
+	if err != nil || len(pending) != 2 {
+		fail("expected 2 items to extract, got %d (%v)", len(pending), err)
+	}
+	for _, item := range pending {
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		article, err := feed.FetchArticle(ctx, item.URL)
+		cancel()
+		content := ""
+		if err == nil {
+			content = article.Content
+		} else if item.ID != "i2" {
+			fail("fetch %s: %v", item.URL, err)
+		}
+		if err := database.SetItemFullContent(item.ID, content); err != nil {
+			fail("store: %v", err)
+		}
+	}
+
+	full, err := database.GetItemFullContent("i1")
+	if err != nil || !strings.HasPrefix(full, "<div>") || len(full) < 500 {
+		fail("full content not stored: %q, %v", full, err)
+	}
+	item, err := database.GetFeedItem("i1")
+	if err != nil || item.Content != summary {
+		fail("summary not kept: %+v, %v", item, err)
+	}
+	fmt.Printf("   ✓ Extracted %d bytes, summary kept\n", len(full))
+
+	pending, err = database.GetItemsWithoutFullContent("f1", 10)
+	if err != nil || len(pending) != 0 {
+		fail("failed pages should not be retried, got %d pending (%v)", len(pending), err)
+	}
+	if full, _ := database.GetItemFullContent("i2"); full != "" {
+		fail("failed page has content %q", full)
+	}
+	fmt.Println("   ✓ Failed pages are not retried on the next fetch")
+}
+
+func fail(format string, args ...interface{}) {
+	fmt.Printf("❌ "+format+"\n", args...)
+	os.Exit(1)
+}
@@ -10,7 +10,7 @@ import (
 func main() {
 width := 80
 
-fmt.Println("\n=== NostrFeedz CLI Render Test ===\n")
+fmt.Print("\n=== NostrFeedz CLI Render Test ===\n\n")
 
 fmt.Println("Test 1: Plain text centered")
 fmt.Println(centerText("This text should be centered", width))
@@ -0,0 +1,92 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/plebone/nostrfeedz-cli/internal/feed"
+)
+
+// Benchmarks rendering the large article in
+// internal/feed/testdata/render/large.html (or the file given as the first
+// argument) with and without the render cache, then checks the cache keeps
+// to its memory bound.
+func main() {
+	fmt.Print("=== Render Cache Test ===\n\n")
+
+	path := filepath.Join("internal", "feed", "testdata", "render", "large.html")
+	if len(os.Args) > 1 {
+		path = os.Args[1]
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		fail("%v (run from the repository root)", err)
+	}
+	content := string(data)
+	renderer, err := feed.NewRenderer(100, "dark")
+	if err != nil {
+		fail("renderer: %v", err)
+	}
+
+	fmt.Println("1. Rendering the fixture...")
+	rendered, err := renderer.RenderContent(content, feed.IsHTML(content))
+	if err != nil {
+		fail("render: %v", err)
+	}
+	lines := strings.Split(rendered, "\n")
+	fmt.Printf("   ✓ %d KB of HTML, %d lines\n", len(content)/1024, len(lines))
+
+	fmt.Println("\n2. Benchmarking...")
+	uncached := testing.Benchmark(func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			renderer.RenderContent(content, true)
+		}
+	})
+	renders := feed.NewRenderCache(feed.DefaultRenderCacheBytes)
+	key := feed.RenderKey{ItemID: "large", Width: 100, Theme: "dark"}
+	renders.Put(key, lines)
+	cached := testing.Benchmark(func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			if _, ok := renders.Get(key); !ok {
+				b.Fatal("not cached")
+			}
+		}
+	})
+	fmt.Printf("   glamour:      %s\n", uncached)
+	fmt.Printf("   render cache: %s\n", cached)
+	if cached.NsPerOp() >= uncached.NsPerOp() {
+		fail("the cache is no faster than rendering")
+	}
+	fmt.Println("   ✓ Cached frames skip glamour")
+
+	fmt.Println("\n3. Keeping to the memory bound...")
+	size := renders.Size()
+	bounded := feed.NewRenderCache(size * 5 / 2)
+	for i := 0; i < 5; i++ {
+		bounded.Put(feed.RenderKey{ItemID: fmt.Sprint(i), Width: 100}, lines)
+		// Using the first keeps it over the ones put after it
+		bounded.Get(feed.RenderKey{ItemID: "0", Width: 100})
+	}
+	if bounded.Size() > size*5/2 {
+		fail("cache holds %d bytes, bound is %d", bounded.Size(), size*5/2)
+	}
+	for id, want := range map[string]bool{"0": true, "1": false, "3": false, "4": true} {
+		if _, ok := bounded.Get(feed.RenderKey{ItemID: id, Width: 100}); ok != want {
+			fail("article %s: cached = %v, want %v", id, ok, want)
+		}
+	}
+	if _, ok := bounded.Get(feed.RenderKey{ItemID: "4", Width: 80}); ok {
+		fail("a different width shared the rendering")
+	}
+	fmt.Println("   ✓ Least recently used renderings dropped first")
+
+	fmt.Println("\n✅ Render cache works")
+}
+
+func fail(format string, args ...interface{}) {
+	fmt.Printf("❌ "+format+"\n", args...)
+	os.Exit(1)
+}
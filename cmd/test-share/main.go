@@ -0,0 +1,80 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/nbd-wtf/go-nostr"
+	"github.com/nbd-wtf/go-nostr/nip19"
+	nostrclient "github.com/plebone/nostrfeedz-cli/internal/nostr"
+	"github.com/plebone/nostrfeedz-cli/internal/testrelay"
+)
+
+// Verifies share notes for RSS and NIP-23 articles and the per-relay
+// publish results, using one local relay and one unreachable relay.
+func main() {
+	fmt.Print("=== Share to Nostr Test ===\n\n")
+
+	fmt.Println("1. Building a note for an RSS item...")
+	note, err := nostrclient.NewShareNote("Worth a read", "Some Post", "https://example.com/post")
+	if err != nil {
+		fail("build failed: %v", err)
+	}
+	if note.Kind != 1 || note.Content != "Worth a read\n\nSome Post\nhttps://example.com/post" || len(note.Tags) != 0 {
+		fail("unexpected note: %q %v", note.Content, note.Tags)
+	}
+	fmt.Println("✓ Title and URL with comment")
+
+	fmt.Println("\n2. Building a quote repost for a NIP-23 article...")
+	author, _ := nostr.GetPublicKey(nostr.GeneratePrivateKey())
+	naddr, _ := nip19.EncodeEntity(author, 30023, "my-article", nil)
+	note, err = nostrclient.NewShareNote("", "My Article", "nostr:"+naddr)
+	if err != nil {
+		fail("build failed: %v", err)
+	}
+	want := "30023:" + author + ":my-article"
+	if note.Content != "nostr:"+naddr || note.Tags.GetFirst([]string{"q"}) == nil || (*note.Tags.GetFirst([]string{"q"}))[1] != want {
+		fail("unexpected quote: %q %v", note.Content, note.Tags)
+	}
+	fmt.Println("✓ naddr reference with q and p tags")
+
+	if _, err := nostrclient.NewShareNote("", "", "nostr:npub1bogus"); err == nil {
+		fail("expected an error for an invalid nostr link")
+	}
+
+	fmt.Println("\n3. Publishing to a live and a dead relay...")
+	relay := testrelay.Start(false)
+	defer relay.Close()
+	dead := "ws://127.0.0.1:1"
+
+	client := nostrclient.NewClient([]string{relay.URL})
+	if err := client.SetPrivateKeySigner(nostr.GeneratePrivateKey()); err != nil {
+		fail("failed to set signer: %v", err)
+	}
+	results, err := client.PublishEventTo(note, []string{relay.URL, dead})
+	if err != nil {
+		fail("publish failed: %v", err)
+	}
+	if len(results) != 2 || results[0].Err != nil || results[1].Err == nil {
+		fail("unexpected results: %+v", results)
+	}
+	for _, r := range results {
+		status := "ok"
+		if r.Err != nil {
+			status = strings.SplitN(r.Err.Error(), "\n", 2)[0]
+		}
+		fmt.Printf("   %s: %s\n", r.Relay, status)
+	}
+	if len(relay.Events()) != 1 || relay.Events()[0].PubKey != client.GetPublicKey() {
+		fail("relay did not store the signed note")
+	}
+	fmt.Println("✓ Signed, stored on the live relay, failure reported for the dead one")
+
+	fmt.Println("\n=== All Tests Passed! ===")
+}
+
+func fail(format string, args ...interface{}) {
+	fmt.Printf("❌ "+format+"\n", args...)
+	os.Exit(1)
+}
@@ -9,7 +9,7 @@ nostrclient "github.com/plebone/nostrfeedz-cli/internal/nostr"
 )
 
 func main() {
-fmt.Println("=== Pleb_Signer Connection Debug ===\n")
+fmt.Print("=== Pleb_Signer Connection Debug ===\n\n")
 
 // Create Pleb_Signer client
 fmt.Println("1. Connecting to Pleb_Signer...")
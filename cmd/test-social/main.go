@@ -0,0 +1,127 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/nbd-wtf/go-nostr"
+	"github.com/nbd-wtf/go-nostr/nip19"
+	"github.com/plebone/nostrfeedz-cli/internal/db"
+	nostrclient "github.com/plebone/nostrfeedz-cli/internal/nostr"
+	"github.com/plebone/nostrfeedz-cli/internal/testrelay"
+)
+
+// Verifies reactions, replies and zap totals for a NIP-23 article on a
+// local relay, plus liking, replying and the database cache.
+func main() {
+	fmt.Print("=== Article Social Context Test ===\n\n")
+
+	relay := testrelay.Start(false)
+	defer relay.Close()
+
+	authorKey := nostr.GeneratePrivateKey()
+	article := sign(authorKey, &nostr.Event{Kind: 30023, Tags: nostr.Tags{{"d", "hello"}, {"title", "Hello"}}, Content: "# Hello"})
+	relay.Publish(article)
+	coordinate := fmt.Sprintf("30023:%s:hello", article.PubKey)
+
+	fmt.Println("1. Seeding reactions, a reply and zap receipts...")
+	for _, content := range []string{"+", "🤙", "-"} {
+		relay.Publish(sign(nostr.GeneratePrivateKey(), &nostr.Event{Kind: 7, Tags: nostr.Tags{{"a", coordinate}}, Content: content}))
+	}
+	// Found by both the a and e filters, counted once
+	relay.Publish(sign(nostr.GeneratePrivateKey(), &nostr.Event{Kind: 1,
+		Tags: nostr.Tags{{"a", coordinate}, {"e", article.ID}}, Content: "Great post"}))
+	relay.Publish(sign(nostr.GeneratePrivateKey(), &nostr.Event{Kind: 9735,
+		Tags: nostr.Tags{{"a", coordinate}, {"bolt11", "lnbc210n1pjexample"}}}))
+	relay.Publish(sign(nostr.GeneratePrivateKey(), &nostr.Event{Kind: 9735,
+		Tags: nostr.Tags{{"e", article.ID}, {"bolt11", "lnbc10u1pjexample"}}}))
+
+	for invoice, want := range map[string]int64{"lnbc210n1x": 21_000, "lnbc10u1x": 1_000_000, "lnbc1m1x": 100_000_000, "lntb5p1x": 0} {
+		if got, err := nostrclient.Bolt11Amount(invoice); err != nil || got != want {
+			fail("Bolt11Amount(%s) = %d, %v (want %d)", invoice, got, err, want)
+		}
+	}
+
+	naddr, _ := nip19.EncodeEntity(article.PubKey, 30023, "hello", nil)
+	ref, ok := nostrclient.ArticleRefFor("nostr:"+naddr, article.ID)
+	if !ok || ref.Coordinate != coordinate || ref.Author != article.PubKey {
+		fail("bad article ref: %+v", ref)
+	}
+	if _, ok := nostrclient.ArticleRefFor("https://example.com", "x"); ok {
+		fail("web links are not Nostr articles")
+	}
+
+	client := nostrclient.NewClient([]string{relay.URL})
+	if err := client.SetPrivateKeySigner(nostr.GeneratePrivateKey()); err != nil {
+		fail("failed to set signer: %v", err)
+	}
+
+	social, err := client.FetchSocial(ref)
+	if err != nil {
+		fail("fetch failed: %v", err)
+	}
+	if social.Reactions != 2 || len(social.Replies) != 1 || social.ZapCount != 2 || social.ZapSats != 1021 || social.Liked {
+		fail("unexpected social context: %+v", social)
+	}
+	fmt.Printf("✓ %d reactions, %d reply, %d zaps for %d sats\n",
+		social.Reactions, len(social.Replies), social.ZapCount, social.ZapSats)
+
+	fmt.Println("\n2. Liking and replying...")
+	if err := client.React(ref); err != nil {
+		fail("react failed: %v", err)
+	}
+	reply, err := nostrclient.NewReplyNote(ref, "Thanks!")
+	if err != nil {
+		fail("reply failed: %v", err)
+	}
+	if _, err := client.PublishEventTo(reply, []string{relay.URL}); err != nil {
+		fail("publish failed: %v", err)
+	}
+	time.Sleep(200 * time.Millisecond)
+
+	social, err = client.FetchSocial(ref)
+	if err != nil || social.Reactions != 3 || !social.Liked || len(social.Replies) != 2 {
+		fail("like or reply missing: %+v (err %v)", social, err)
+	}
+	fmt.Println("✓ Like and reply show up in the next fetch")
+
+	fmt.Println("\n3. Caching in the database...")
+	dir, _ := os.MkdirTemp("", "social")
+	defer os.RemoveAll(dir)
+	database, err := db.New(filepath.Join(dir, "feeds.db"))
+	if err != nil {
+		fail("db: %v", err)
+	}
+	defer database.Close()
+	database.CreateFeed(&db.Feed{ID: "f1", Type: "nostr", URL: "nostr:npub", Title: "Author", CreatedAt: time.Now()})
+	database.CreateFeedItem(&db.FeedItem{ID: "i1", FeedID: "f1", GUID: article.ID, Title: "Hello",
+		URL: "nostr:" + naddr, PublishedAt: time.Now(), CreatedAt: time.Now()})
+
+	social.ItemID = "i1"
+	if err := database.SaveArticleSocial(social); err != nil {
+		fail("save failed: %v", err)
+	}
+	cached, err := database.GetArticleSocial("i1")
+	if err != nil || cached == nil || cached.Reactions != 3 || !cached.Liked || len(cached.Replies) != 2 || cached.ZapSats != 1021 {
+		fail("cache did not round-trip: %+v (err %v)", cached, err)
+	}
+	if missing, _ := database.GetArticleSocial("nope"); missing != nil {
+		fail("expected no cache for unknown item")
+	}
+	fmt.Println("✓ Counts and replies cached")
+
+	fmt.Println("\n=== All Tests Passed! ===")
+}
+
+func sign(key string, event *nostr.Event) *nostr.Event {
+	event.CreatedAt = nostr.Now()
+	event.Sign(key)
+	return event
+}
+
+func fail(format string, args ...interface{}) {
+	fmt.Printf("❌ "+format+"\n", args...)
+	os.Exit(1)
+}
@@ -0,0 +1,146 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/plebone/nostrfeedz-cli/internal/feed"
+)
+
+// Verifies video URL parsing across platforms, and finding the videos of
+// an article, with table-driven cases.
+func main() {
+	fmt.Print("=== Video URL Test ===\n\n")
+
+	fmt.Println("1. Parsing video URLs...")
+	cases := []struct {
+		raw      string
+		platform feed.VideoPlatform // "" when not a video
+		id       string
+		playlist string
+		start    time.Duration
+		url      string
+	}{
+		// YouTube
+		{"https://www.youtube.com/watch?v=dQw4w9WgXcQ", feed.YouTube, "dQw4w9WgXcQ", "", 0,
+			"https://www.youtube.com/watch?v=dQw4w9WgXcQ"},
+		{"https://youtube.com/watch?feature=share&v=dQw4w9WgXcQ&t=1m30s", feed.YouTube, "dQw4w9WgXcQ", "", 90 * time.Second,
+			"https://www.youtube.com/watch?v=dQw4w9WgXcQ&t=90s"},
+		{"https://m.youtube.com/watch?v=dQw4w9WgXcQ&list=PLx0sYbCqOb8TBPRdmBHs5Iftvv9TPboYG&index=2",
+			feed.YouTube, "dQw4w9WgXcQ", "PLx0sYbCqOb8TBPRdmBHs5Iftvv9TPboYG", 0,
+			"https://www.youtube.com/watch?v=dQw4w9WgXcQ&list=PLx0sYbCqOb8TBPRdmBHs5Iftvv9TPboYG"},
+		{"https://youtu.be/dQw4w9WgXcQ?t=42", feed.YouTube, "dQw4w9WgXcQ", "", 42 * time.Second,
+			"https://www.youtube.com/watch?v=dQw4w9WgXcQ&t=42s"},
+		{"https://www.youtube.com/shorts/abcDEF12345?feature=share", feed.YouTube, "abcDEF12345", "", 0,
+			"https://www.youtube.com/watch?v=abcDEF12345"},
+		{"https://www.youtube-nocookie.com/embed/dQw4w9WgXcQ?start=3600", feed.YouTube, "dQw4w9WgXcQ", "", time.Hour,
+			"https://www.youtube.com/watch?v=dQw4w9WgXcQ&t=3600s"},
+		{"https://www.youtube.com/live/dQw4w9WgXcQ", feed.YouTube, "dQw4w9WgXcQ", "", 0,
+			"https://www.youtube.com/watch?v=dQw4w9WgXcQ"},
+		{"https://www.youtube.com/playlist?list=PLx0sYbCqOb8TBPRdmBHs5Iftvv9TPboYG", feed.YouTube, "", "PLx0sYbCqOb8TBPRdmBHs5Iftvv9TPboYG", 0,
+			"https://www.youtube.com/playlist?list=PLx0sYbCqOb8TBPRdmBHs5Iftvv9TPboYG"},
+		{"https://www.youtube.com/watch?v=short", "", "", "", 0, ""},
+		{"https://www.youtube.com/@channel", "", "", "", 0, ""},
+		{"https://youtube.com/embed/", "", "", "", 0, ""},
+
+		// Vimeo
+		{"https://vimeo.com/76979871", feed.Vimeo, "76979871", "", 0, "https://vimeo.com/76979871"},
+		{"https://player.vimeo.com/video/76979871?h=abc#t=1m2s", feed.Vimeo, "76979871", "", 62 * time.Second,
+			"https://vimeo.com/76979871#t=62s"},
+		{"https://vimeo.com/channels/staffpicks/76979871", feed.Vimeo, "76979871", "", 0, "https://vimeo.com/76979871"},
+		{"https://vimeo.com/about", "", "", "", 0, ""},
+
+		// PeerTube, on any instance
+		{"https://framatube.org/w/9c9de5e8-0a1e-484a-b099-e80766180a6d", feed.PeerTube, "9c9de5e8-0a1e-484a-b099-e80766180a6d", "", 0,
+			"https://framatube.org/w/9c9de5e8-0a1e-484a-b099-e80766180a6d"},
+		{"https://peertube.example/videos/watch/kkGMgK9ZtnKfYAgnEtQxbv?start=1m", feed.PeerTube, "kkGMgK9ZtnKfYAgnEtQxbv", "", time.Minute,
+			"https://peertube.example/w/kkGMgK9ZtnKfYAgnEtQxbv?start=60s"},
+		{"https://peertube.example/videos/embed/kkGMgK9ZtnKfYAgnEtQxbv", feed.PeerTube, "kkGMgK9ZtnKfYAgnEtQxbv", "", 0,
+			"https://peertube.example/w/kkGMgK9ZtnKfYAgnEtQxbv"},
+		{"https://blog.example/w/about", "", "", "", 0, ""},
+		{"https://blog.example/w/my-first-post-about-things", "", "", "", 0, ""},
+		{"https://wiki.example/w/Main_Page_of_the_wiki_x", "", "", "", 0, ""},
+		{"https://blog.example/videos/watch/0123456789abcdefghijkl", "", "", "", 0, ""},
+
+		// Odysee
+		{"https://odysee.com/@Lunduke:e/linux-news:7", feed.Odysee, "@Lunduke:e/linux-news:7", "", 0,
+			"https://odysee.com/@Lunduke:e/linux-news:7"},
+		{"https://odysee.com/$/embed/linux-news/7f3a", feed.Odysee, "linux-news:7f3a", "", 0,
+			"https://odysee.com/linux-news:7f3a"},
+		{"https://odysee.com/@Lunduke:e", "", "", "", 0, ""},
+
+		// Rumble
+		{"https://rumble.com/v4abc12-some-title.html?mref=x", feed.Rumble, "v4abc12", "", 0,
+			"https://rumble.com/v4abc12-some-title.html"},
+		{"https://rumble.com/embed/v2xyz9/?pub=4", feed.Rumble, "v2xyz9", "", 0, "https://rumble.com/embed/v2xyz9/"},
+		{"https://rumble.com/videos", "", "", "", 0, ""},
+
+		// Direct files and streams
+		{"https://cdn.example/clip.mp4", feed.Direct, "", "", 0, "https://cdn.example/clip.mp4"},
+		{"https://cdn.example/clip.WEBM?token=1", feed.Direct, "", "", 0, "https://cdn.example/clip.WEBM?token=1"},
+		{"https://live.example/stream/index.m3u8", feed.Direct, "", "", 0, "https://live.example/stream/index.m3u8"},
+		{"https://cdn.example/photo.jpg", "", "", "", 0, ""},
+
+		// Not links
+		{"ftp://cdn.example/clip.mp4", "", "", "", 0, ""},
+		{"dQw4w9WgXcQ", "", "", "", 0, ""},
+		{"", "", "", "", 0, ""},
+	}
+	failed := 0
+	for _, c := range cases {
+		v, ok := feed.ParseVideoURL(c.raw)
+		switch {
+		case c.platform == "" && ok:
+			fmt.Printf("   ✗ %s: parsed as %+v, want no video\n", c.raw, *v)
+			failed++
+		case c.platform == "":
+		case !ok:
+			fmt.Printf("   ✗ %s: not recognized\n", c.raw)
+			failed++
+		case v.Platform != c.platform || v.ID != c.id || v.Playlist != c.playlist || v.Start != c.start || v.URL != c.url:
+			fmt.Printf("   ✗ %s:\n       got  %+v\n       want {Platform:%s ID:%s Playlist:%s Start:%s URL:%s}\n",
+				c.raw, *v, c.platform, c.id, c.playlist, c.start, c.url)
+			failed++
+		}
+	}
+	if failed > 0 {
+		fail("%d of %d cases failed", failed, len(cases))
+	}
+	fmt.Printf("   ✓ %d cases\n", len(cases))
+
+	fmt.Println("\n2. Finding the videos of an article...")
+	renderer, err := feed.NewRenderer(80, "dark")
+	if err != nil {
+		fail("renderer: %v", err)
+	}
+	content := `<p>Watch <a href="https://youtu.be/dQw4w9WgXcQ?t=10">this</a>.</p>
+<iframe title="Big &amp; Small" width="560" src="https://www.youtube.com/embed/abcDEF12345"></iframe>
+<iframe src="https://player.vimeo.com/video/76979871" title="On Vimeo"></iframe>
+<p>Same again: https://www.youtube.com/watch?v=abcDEF12345, and a file (https://cdn.example/clip.mp4).</p>
+<img src="https://cdn.example/photo.jpg">`
+	media := renderer.ExtractMedia(content, "https://www.youtube.com/watch?v=zzzzzzzzzzz&feature=rss")
+	want := []feed.VideoInfo{
+		{URL: "https://www.youtube.com/watch?v=zzzzzzzzzzz", Title: "Video from feed"},
+		{URL: "https://www.youtube.com/watch?v=abcDEF12345", Title: "Big & Small"},
+		{URL: "https://vimeo.com/76979871", Title: "On Vimeo"},
+		{URL: "https://www.youtube.com/watch?v=dQw4w9WgXcQ&t=10s"},
+		{URL: "https://cdn.example/clip.mp4"},
+	}
+	if len(media.Videos) != len(want) {
+		fail("found %d videos, want %d: %+v", len(media.Videos), len(want), media.Videos)
+	}
+	for i, v := range want {
+		if media.Videos[i] != v {
+			fail("video %d is %+v, want %+v", i, media.Videos[i], v)
+		}
+	}
+	fmt.Println("   ✓ Article video first, embeds with titles, links once each")
+
+	fmt.Println("\n=== All Tests Passed! ===")
+}
+
+func fail(format string, args ...interface{}) {
+	fmt.Printf("❌ "+format+"\n", args...)
+	os.Exit(1)
+}
@@ -0,0 +1,219 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/plebone/nostrfeedz-cli/internal/db"
+	"github.com/plebone/nostrfeedz-cli/internal/feed"
+)
+
+const channelID = "UCabcdefghijklmnopqrstuv"
+
+// channelFeed is a YouTube channel feed as YouTube serves it; views is
+// filled in per request
+const channelFeed = `<?xml version="1.0" encoding="UTF-8"?>
+<feed xmlns:yt="http://www.youtube.com/xml/schemas/2015" xmlns:media="http://search.yahoo.com/mrss/" xmlns="http://www.w3.org/2005/Atom">
+ <title>Test Channel</title>
+ <author><name>Test Channel</name></author>
+ <entry>
+  <id>yt:video:dQw4w9WgXcQ</id>
+  <yt:videoId>dQw4w9WgXcQ</yt:videoId>
+  <yt:channelId>` + channelID + `</yt:channelId>
+  <title>Building a Relay</title>
+  <link rel="alternate" href="https://www.youtube.com/watch?v=dQw4w9WgXcQ"/>
+  <author><name>Test Channel</name></author>
+  <published>2026-10-01T12:00:00+00:00</published>
+  <media:group>
+   <media:title>Building a Relay</media:title>
+   <media:content url="https://www.youtube.com/v/dQw4w9WgXcQ?version=3" type="application/x-shockwave-flash" width="640" height="390"/>
+   <media:thumbnail url="https://i2.ytimg.com/vi/dQw4w9WgXcQ/hqdefault.jpg" width="480" height="360"/>
+   <media:description>Part one.
+Links below.</media:description>
+   <media:community>
+    <media:starRating count="120" average="5.00" min="1" max="5"/>
+    <media:statistics views="%d"/>
+   </media:community>
+  </media:group>
+ </entry>
+</feed>`
+
+// newsFeed has Media RSS pictures, which do not make its posts videos
+const newsFeed = `<?xml version="1.0"?>
+<rss version="2.0" xmlns:media="http://search.yahoo.com/mrss/">
+<channel>
+  <title>News</title>
+  <item>
+    <title>A Story</title>
+    <link>https://news.example/story</link>
+    <description>The story.</description>
+    <media:thumbnail url="https://news.example/story.jpg"/>
+  </item>
+</channel>
+</rss>`
+
+// Verifies recognizing YouTube channel, handle and playlist links,
+// resolving them to their Atom feed, and storing the media:group details
+// of videos. youtube.com is served by a local server.
+func main() {
+	fmt.Print("=== YouTube Feed Test ===\n\n")
+
+	dir, _ := os.MkdirTemp("", "youtube")
+	defer os.RemoveAll(dir)
+	database, err := db.New(filepath.Join(dir, "feeds.db"))
+	if err != nil {
+		fail("db: %v", err)
+	}
+	defer database.Close()
+
+	views := 1234
+	pageFetches := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.URL.Path == "/feeds/videos.xml" && r.URL.Query().Get("channel_id") == channelID:
+			w.Header().Set("Content-Type", "application/atom+xml")
+			fmt.Fprintf(w, channelFeed, views)
+		case r.URL.Path == "/@testchannel":
+			pageFetches++
+			fmt.Fprintf(w, `<html><head><link rel="canonical" href="https://www.youtube.com/channel/%s"></head>
+<body><script>{"channelId":"UCzzzzzzzzzzzzzzzzzzzzzz"}</script></body></html>`, channelID)
+		case r.URL.Path == "/news":
+			fmt.Fprint(w, newsFeed)
+		case r.URL.Path == "/c/legacy":
+			fmt.Fprintf(w, `<html><script>{"externalId":"%s"}</script></html>`, channelID)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+	target, _ := url.Parse(server.URL)
+	http.DefaultTransport = rewriteTransport{target: target, next: http.DefaultTransport}
+
+	fmt.Println("1. Recognizing YouTube feeds...")
+	feedURL := "https://www.youtube.com/feeds/videos.xml?channel_id=" + channelID
+	cases := []struct {
+		link     string
+		isFeed   bool
+		resolved string // "" when a page must be fetched
+	}{
+		{"https://www.youtube.com/channel/" + channelID, true, feedURL},
+		{"https://youtube.com/channel/" + channelID + "/videos", true, feedURL},
+		{feedURL, true, feedURL},
+		{"https://www.youtube.com/playlist?list=PLx0sYbCqOb8TBPRdmBHs5Iftvv9TPboYG", true,
+			"https://www.youtube.com/feeds/videos.xml?playlist_id=PLx0sYbCqOb8TBPRdmBHs5Iftvv9TPboYG"},
+		{"https://www.youtube.com/feeds/videos.xml?playlist_id=PLx0sYbCqOb8TBPRdmBHs5Iftvv9TPboYG", true,
+			"https://www.youtube.com/feeds/videos.xml?playlist_id=PLx0sYbCqOb8TBPRdmBHs5Iftvv9TPboYG"},
+		{"https://www.youtube.com/@testchannel", true, ""},
+		{"https://m.youtube.com/@testchannel/videos", true, ""},
+		{"https://www.youtube.com/c/legacy", true, ""},
+		{"https://www.youtube.com/user/legacy", true, ""},
+		{"https://www.youtube.com/watch?v=dQw4w9WgXcQ", false, ""},
+		{"https://www.youtube.com/@", false, ""},
+		{"https://www.youtube.com/channel/short", false, ""},
+		{"https://example.com/@testchannel", false, ""},
+		{"https://example.com/feed.xml", false, ""},
+	}
+	for _, c := range cases {
+		if got := feed.IsYouTubeFeed(c.link); got != c.isFeed {
+			fail("IsYouTubeFeed(%s) = %v, want %v", c.link, got, c.isFeed)
+		}
+		if c.resolved == "" {
+			continue
+		}
+		if got, err := feed.ResolveYouTubeFeed(context.Background(), c.link); err != nil || got != c.resolved {
+			fail("ResolveYouTubeFeed(%s) = %q (err %v), want %q", c.link, got, err, c.resolved)
+		}
+	}
+	if pageFetches != 0 {
+		fail("channel IDs and playlists fetched a page")
+	}
+	fmt.Printf("   ✓ %d links: channels, handles, legacy names and playlists; videos and other sites are not feeds\n", len(cases))
+
+	fmt.Println("\n2. Resolving handles from the channel page...")
+	for _, link := range []string{"https://www.youtube.com/@testchannel/videos", "https://www.youtube.com/c/legacy"} {
+		got, err := feed.ResolveYouTubeFeed(context.Background(), link)
+		if err != nil || got != feedURL {
+			fail("%s resolved to %q (err %v), want the page's own channel", link, got, err)
+		}
+	}
+	if _, err := feed.ResolveYouTubeFeed(context.Background(), "https://www.youtube.com/@missing"); err == nil {
+		fail("a missing channel resolved")
+	}
+	fmt.Println("   ✓ The page's own channel found, not others it mentions")
+
+	fmt.Println("\n3. Fetching a channel's videos...")
+	database.CreateFeed(&db.Feed{ID: "yt", Type: "youtube", URL: "https://www.youtube.com/@testchannel", Title: "Test Channel", CreatedAt: time.Now()})
+	fetcher := feed.NewFetcher(nil)
+	pageFetches = 0
+	items, err := fetcher.FetchYouTubeVideos("https://www.youtube.com/@testchannel", "yt")
+	if err != nil || len(items) != 1 {
+		fail("fetch: %d items (err %v)", len(items), err)
+	}
+	video := items[0]
+	if video.VideoID != "dQw4w9WgXcQ" || video.Thumbnail != "https://i2.ytimg.com/vi/dQw4w9WgXcQ/hqdefault.jpg" {
+		fail("video ID or thumbnail missing: %q %q", video.VideoID, video.Thumbnail)
+	}
+	if video.Video == nil || video.Video.Views != 1234 || video.Video.Description != "Part one.\nLinks below." {
+		fail("media:group not parsed: %+v", video.Video)
+	}
+	if video.Content != "Part one.  \nLinks below." {
+		fail("description not used as content: %q", video.Content)
+	}
+	if err := database.CreateFeedItem(video); err != nil {
+		fail("store: %v", err)
+	}
+	news, err := fetcher.FetchRSSArticles(server.URL+"/news", "news")
+	if err != nil || len(news) != 1 || news[0].Video != nil || news[0].Content != "The story." {
+		fail("a news post with a Media RSS picture became a video: %+v (err %v)", news, err)
+	}
+	fmt.Println("   ✓ Thumbnail, description, views and video ID read from media:group; news pictures ignored")
+
+	fmt.Println("\n4. Refetching updates views...")
+	views = 5678
+	again, err := fetcher.FetchYouTubeVideos("https://www.youtube.com/@testchannel", "yt")
+	if err != nil || len(again) != 1 {
+		fail("refetch: %v", err)
+	}
+	if pageFetches != 1 {
+		fail("channel page fetched %d times, want once", pageFetches)
+	}
+	database.CreateFeedItem(again[0]) // New ID, same GUID
+	meta, err := database.GetVideoMeta(video.ID)
+	if err != nil || meta == nil || meta.Views != 5678 || !strings.HasSuffix(meta.Thumbnail, "hqdefault.jpg") {
+		fail("stored details not refreshed: %+v (err %v)", meta, err)
+	}
+	if meta, err := database.GetVideoMeta("missing"); err != nil || meta != nil {
+		fail("details for an item that is not a video: %+v (err %v)", meta, err)
+	}
+	fmt.Println("   ✓ View count refreshed on the stored video, handle resolved once")
+
+	fmt.Println("\n=== All Tests Passed! ===")
+}
+
+// rewriteTransport sends requests for youtube.com to the test server
+type rewriteTransport struct {
+	target *url.URL
+	next   http.RoundTripper
+}
+
+func (t rewriteTransport) RoundTrip(r *http.Request) (*http.Response, error) {
+	if strings.HasSuffix(r.URL.Hostname(), "youtube.com") {
+		r = r.Clone(r.Context())
+		r.URL.Scheme = t.target.Scheme
+		r.URL.Host = t.target.Host
+		r.Host = t.target.Host
+	}
+	return t.next.RoundTrip(r)
+}
+
+func fail(format string, args ...interface{}) {
+	fmt.Printf("❌ "+format+"\n", args...)
+	os.Exit(1)
+}
@@ -0,0 +1,109 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/nbd-wtf/go-nostr"
+	nostrclient "github.com/plebone/nostrfeedz-cli/internal/nostr"
+	"github.com/plebone/nostrfeedz-cli/internal/testrelay"
+	"github.com/plebone/nostrfeedz-cli/internal/testwallet"
+)
+
+// Verifies zapping a NIP-23 article end to end: profile lookup, LNURL-pay
+// with a NIP-57 zap request, and payment over NWC, against a local relay,
+// lightning address and wallet service.
+func main() {
+	fmt.Print("=== Zap via Nostr Wallet Connect Test ===\n\n")
+
+	relay := testrelay.Start(false)
+	defer relay.Close()
+	lnurl := testwallet.StartLNURL("alice")
+	defer lnurl.Close()
+	wallet := testwallet.StartWallet(relay)
+
+	fmt.Println("1. Parsing the wallet connect URI...")
+	wc, err := nostrclient.ParseWalletConnect(wallet.URI)
+	if err != nil || len(wc.Relays) != 1 || wc.Relays[0] != relay.URL {
+		fail("parse failed: %+v (err %v)", wc, err)
+	}
+	for _, bad := range []string{"https://example.com", "nostr+walletconnect://abc?relay=wss://r", "nostr+walletconnect://" + wc.WalletPubkey + "?secret=" + wc.Secret} {
+		if _, err := nostrclient.ParseWalletConnect(bad); err == nil {
+			fail("expected an error for %s", bad)
+		}
+	}
+	fmt.Println("✓ Wallet pubkey, relay and secret read, bad URIs rejected")
+
+	fmt.Println("\n2. Seeding the author's profile and article...")
+	authorKey := nostr.GeneratePrivateKey()
+	profile, _ := json.Marshal(map[string]string{"name": "alice", "lud16": lnurl.Address})
+	relay.Publish(sign(authorKey, &nostr.Event{Kind: 0, Content: string(profile)}))
+	article := sign(authorKey, &nostr.Event{Kind: 30023, Tags: nostr.Tags{{"d", "hello"}}, Content: "# Hello"})
+	relay.Publish(article)
+	ref := nostrclient.ArticleRef{EventID: article.ID, Coordinate: "30023:" + article.PubKey + ":hello", Author: article.PubKey}
+
+	client := nostrclient.NewClient([]string{relay.URL})
+	if err := client.SetPrivateKeySigner(nostr.GeneratePrivateKey()); err != nil {
+		fail("failed to set signer: %v", err)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+	defer cancel()
+	if lud16, err := client.FetchLightningAddress(ctx, ref.Author); err != nil || lud16 != lnurl.Address {
+		fail("lightning address lookup failed: %q (err %v)", lud16, err)
+	}
+	fmt.Printf("✓ Found %s\n", lnurl.Address)
+
+	fmt.Println("\n3. Zapping 21 sats...")
+	preimage, err := client.Zap(ctx, wc, ref, 21, "Great post")
+	if err != nil {
+		fail("zap failed: %v", err)
+	}
+	if preimage == "" || len(wallet.Paid()) != 1 {
+		fail("wallet did not pay: preimage %q, paid %v", preimage, wallet.Paid())
+	}
+	if amount, _ := nostrclient.Bolt11Amount(wallet.Paid()[0]); amount != 21_000 {
+		fail("paid invoice is for %d msats", amount)
+	}
+	requests := lnurl.ZapRequests()
+	if len(requests) != 1 {
+		fail("expected one zap request, got %d", len(requests))
+	}
+	zr := requests[0]
+	if zr.PubKey != client.GetPublicKey() || zr.Content != "Great post" ||
+		zr.Tags.GetFirst([]string{"p", ref.Author}) == nil ||
+		zr.Tags.GetFirst([]string{"e", ref.EventID}) == nil ||
+		zr.Tags.GetFirst([]string{"a", ref.Coordinate}) == nil {
+		fail("unexpected zap request: %v", zr)
+	}
+	fmt.Printf("✓ Zap request signed by the reader, invoice paid (preimage %s...)\n", preimage[:8])
+
+	fmt.Println("\n4. Reporting wallet errors...")
+	wallet.Fail = "insufficient balance"
+	if _, err := client.Zap(ctx, wc, ref, 21, ""); err == nil {
+		fail("expected the wallet error")
+	} else {
+		fmt.Printf("✓ %v\n", err)
+	}
+	wallet.Fail = ""
+	if _, err := client.Zap(ctx, wc, ref, 500_000, ""); err == nil {
+		fail("expected an error above the LNURL maximum")
+	} else {
+		fmt.Printf("✓ %v\n", err)
+	}
+
+	fmt.Println("\n=== All Tests Passed! ===")
+}
+
+func sign(key string, event *nostr.Event) *nostr.Event {
+	event.CreatedAt = nostr.Now()
+	event.Sign(key)
+	return event
+}
+
+func fail(format string, args ...interface{}) {
+	fmt.Printf("❌ "+format+"\n", args...)
+	os.Exit(1)
+}
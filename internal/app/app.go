@@ -2,17 +2,24 @@ package app
 
 import (
 	"fmt"
+	"log/slog"
 	"os"
 	"path/filepath"
 	"strings"
+	"sync"
+	"time"
 
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
+	"github.com/plebone/nostrfeedz-cli/internal/archive"
 	"github.com/plebone/nostrfeedz-cli/internal/cache"
 	"github.com/plebone/nostrfeedz-cli/internal/config"
 	"github.com/plebone/nostrfeedz-cli/internal/db"
 	"github.com/plebone/nostrfeedz-cli/internal/feed"
+	"github.com/plebone/nostrfeedz-cli/internal/keymap"
+	"github.com/plebone/nostrfeedz-cli/internal/media"
 	"github.com/plebone/nostrfeedz-cli/internal/nostr"
+	"github.com/plebone/nostrfeedz-cli/internal/player"
 	"github.com/plebone/nostrfeedz-cli/pkg/styles"
 )
 
@@ -23,6 +30,12 @@ const (
 	FeedsView
 	ArticlesView
 	ReaderView
+	RelaysView
+	LogsView
+	ComposeView
+	LinksView
+	GalleryView
+	PodcastView
 )
 
 type ViewMode int
@@ -31,6 +44,7 @@ const (
 	ViewModeFeeds ViewMode = iota
 	ViewModeTags
 	ViewModeCategories
+	ViewModeSmart
 )
 
 type AuthState int
@@ -51,7 +65,9 @@ type Model struct {
 	nostr    *nostr.Client
 	fetcher  *feed.Fetcher
 	renderer *feed.Renderer
+	images   *feed.ImageRenderer // Draws images; kept across renderer rebuilds
 	imgCache *cache.ImageCache
+	media    *media.Launcher // External image viewer and video player
 	
 	currentView View
 	viewMode    ViewMode
@@ -71,6 +87,7 @@ type Model struct {
 	currentFeed     *db.Feed
 	currentTag      *db.Tag
 	currentCategory *db.Category
+	currentSmart    *smartView
 	currentArticle  *db.FeedItem
 	currentMedia    *feed.MediaLinks
 	
@@ -90,25 +107,115 @@ type Model struct {
 	err             error
 	statusMessage   string
 	loading         bool
-	imageViewerPID  int // Track image viewer process
-	videoPlayerPID  int // Track video player process
+	
+	// Relay panel
+	relayStatuses    map[string]nostr.RelayStatus
+	selectedRelayIdx int
+	relayInput       string
+	relayInputActive bool
+	relayChecking    bool
+	authQueue        []nostr.AuthRequest // Relays waiting for an AUTH decision, oldest first
+	
+	// Log viewer
+	logScrollOffset int        // Entries scrolled back from the newest
+	logMinLevel     slog.Level // Hide entries below this level
+	logTicking      bool       // A refresh tick is in flight
+	
+	// Key bindings
+	keys         *keymap.Keymap
+	keyConflicts []keymap.Conflict
+	showHelp     bool
+	
+	themes *styles.Catalog
+	
+	// Mark-read behavior
+	markReadMode  markReadMode
+	markReadDelay time.Duration
+	readerLines   int // Rendered length of the open article, 0 until rendered
+	
+	// Rendered articles, and the open one's rendering
+	renderCache    *feed.RenderCache
+	rendered       *renderedArticle // nil while rendering
+	renderPending  *feed.RenderKey  // Rendering in the background
+	renderSpinning bool
+	spinnerFrame   int
+	
+	// Smart views
+	smartViews         []smartView
+	smartCounts        map[string]int // View ID to unread count
+	selectedSmartIdx   int
+	filterInput        string
+	filterInputStage   filterInputStage
+	pendingFilterQuery string // Query typed before naming a new filter
+	
+	bookmarkMu sync.Mutex // Serializes bookmark list read-modify-write
+	
+	compose *compose // Note being written to share or reply to an article
+	
+	// Reactions, replies and zaps of the open Nostr article
+	social        *db.ArticleSocial
+	socialLoading bool
+	threadOpen    bool // Show replies instead of the article
+	
+	// Zap amount prompt in the reader
+	zapInputActive bool
+	zapInput       string
+	
+	readingProgress map[string]db.ReadingProgress // Progress of the listed articles
+	
+	// Link picker and the articles followed links were opened from
+	links           []feed.Link
+	selectedLinkIdx int
+	readerStack     []readerState
+	
+	// Image gallery of the open article
+	galleryThumbs []string // Thumbnail paths by image index, "" if it failed
+	galleryData   string   // Rendered page of the grid
+	
+	showSummary bool // Show the feed's summary instead of the extracted article
+	
+	// Podcast queue and the episode playing in mpv
+	podcastQueue       []db.Episode
+	selectedEpisodeIdx int
+	player             *player.Player // nil when nothing plays
+	playerStarting     bool
+	playing            *db.Episode
+	playerStatus       player.Status
+	positionSaved      time.Time
+	
+	archiver     *archive.Archiver // nil without an image cache
+	exportPrompt *pendingExport    // Waiting for the export format
+	selected     map[string]bool   // Articles selected for export, by ID
 }
 
 func New(cfg *config.Config, database *db.DB) *Model {
-	fetcher := feed.NewFetcher(cfg.Nostr.Relays)
-	renderer, _ := feed.NewRenderer(80) // Default width, will update on window resize
+	fetcher := feed.NewFetcher(cfg.Nostr.ReadRelays())
+	
+	themes := loadTheme(cfg)
+	renderer, _ := feed.NewRenderer(80, styles.Current().GlamourStyle()) // Default width, will update on window resize
 	
 	// Create image cache directory
-	homeDir, _ := os.UserHomeDir()
-	cacheDir := filepath.Join(homeDir, ".config", "nostrfeedz", "cache", "images")
-	imgCache, _ := cache.NewImageCache(cacheDir, database)
+	imgCache, _ := cache.NewImageCache(config.GetImageCacheDir(), database)
+	
+	var archiver *archive.Archiver
+	if imgCache != nil {
+		imgCache.SetLimits(int64(cfg.Cache.MaxSizeMB)*1024*1024, time.Duration(cfg.Cache.ExpireDays)*24*time.Hour)
+		archiver = archive.New(database, imgCache, cfg.Archive.Starred)
+	}
+	
+	images := loadImageRenderer(cfg, imgCache)
+	keys, keyConflicts := loadKeymap(cfg)
+	markReadMode, markReadDelay := parseMarkRead(cfg.Reading.MarkReadBehavior)
 	
 	return &Model{
 		cfg:              cfg,
 		db:               database,
 		fetcher:          fetcher,
 		renderer:         renderer,
+		images:           images,
 		imgCache:         imgCache,
+		media:            loadMediaLauncher(cfg),
+		archiver:         archiver,
 		currentView:      AuthView,
 		viewMode:         ViewModeFeeds,
 		authState:        AuthPrompt,
@@ -118,6 +225,15 @@ func New(cfg *config.Config, database *db.DB) *Model {
 		tags:             []db.Tag{},
 		categories:       []db.Category{},
 		articles:         []db.FeedItem{},
+		relayStatuses:    make(map[string]nostr.RelayStatus),
+		logMinLevel:      slog.LevelDebug,
+		keys:             keys,
+		keyConflicts:     keyConflicts,
+		themes:           themes,
+		markReadMode:     markReadMode,
+		markReadDelay:    markReadDelay,
+		readingProgress:  make(map[string]db.ReadingProgress),
+		renderCache:      feed.NewRenderCache(feed.DefaultRenderCacheBytes),
 	}
 }
 
@@ -128,54 +244,181 @@ func (m *Model) Init() tea.Cmd {
 	
 	// Check if already authenticated
 	if m.cfg.Nostr.NPUB != "" {
-		return m.initNostrClient()
+		return tea.Batch(m.initNostrClient(), m.syncArchive(), m.cleanupImageCache())
+	}
+	return tea.Batch(m.syncArchive(), m.cleanupImageCache())
+}
+
+// loadImageRenderer sets up image drawing with the configured protocol,
+// falling back to detecting it when the setting is invalid
+func loadImageRenderer(cfg *config.Config, imgCache *cache.ImageCache) *feed.ImageRenderer {
+	var source feed.ImageSource
+	if imgCache != nil {
+		source = imgCache
+	}
+	images, err := feed.NewImageRenderer(source, cfg.Display.ImageProtocol)
+	if err != nil {
+		slog.Warn("invalid image protocol, detecting the terminal's", "err", err)
+		images, _ = feed.NewImageRenderer(source, "auto")
+	}
+	return images
+}
+
+// loadMediaLauncher sets up the external image viewer and video player,
+// falling back to the defaults for lists left empty
+func loadMediaLauncher(cfg *config.Config) *media.Launcher {
+	viewers, players := cfg.Media.ImageViewers, cfg.Media.VideoPlayers
+	if len(viewers) == 0 {
+		viewers = config.DefaultImageViewers
+	}
+	if len(players) == 0 {
+		players = config.DefaultVideoPlayers
+	}
+	socket := filepath.Join(os.TempDir(), fmt.Sprintf("nostrfeedz-video-%d.sock", os.Getpid()))
+	return media.NewLauncher(viewers, players, socket)
+}
+
+// cleanupImageCache evicts expired images and enforces the cache size
+// limit in the background
+func (m *Model) cleanupImageCache() tea.Cmd {
+	if m.imgCache == nil {
+		return nil
+	}
+	return func() tea.Msg {
+		if err := m.imgCache.Cleanup(); err != nil {
+			slog.Warn("image cache cleanup failed", "err", err)
+		}
+		return nil
 	}
-	return nil
 }
 
 func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	model, cmd := m.update(msg)
+	// Whatever changed, the reader may now need an article rendered
+	if render := m.renderArticle(); render != nil {
+		return model, tea.Batch(cmd, render)
+	}
+	return model, cmd
+}
+
+func (m *Model) update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	switch msg := msg.(type) {
 	case tea.KeyMsg:
-		switch msg.String() {
-		case "ctrl+c", "q":
-			if m.currentView == AuthView && m.authState != AuthPrompt {
-				// Allow quitting during auth
-				return m, tea.Quit
-			}
-			if m.currentView != AuthView {
-				return m, tea.Quit
-			}
+		// A relay is waiting for an AUTH decision, unless typing into a
+		// text field
+		if len(m.authQueue) > 0 && !m.textInputActive() && m.answerAuthRequest(msg.String()) {
+			return m, nil
+		}
+		
+		if m.showHelp {
+			// Any key closes the help overlay
+			m.showHelp = false
+			return m, nil
 		}
 		
-		// Handle auth view input
+		// An export is waiting for its format
+		if m.exportPrompt != nil {
+			return m, m.answerExportPrompt(msg.String())
+		}
+		
+		if msg.String() == "ctrl+c" {
+			m.saveReadingPosition()
+			m.media.Close()
+			return m, tea.Sequence(m.stopPlayback(), tea.Quit)
+		}
+		
+		// Handle auth view input (free text, so no keymap)
 		if m.currentView == AuthView {
+			if m.authState == AuthPrompt && m.keys.Action(keymap.Global, msg.String()) == keymap.Quit {
+				return m, tea.Quit
+			}
 			return m.updateAuth(msg)
 		}
 		
+		// Global actions, unless typing into a text field
+		if !m.textInputActive() {
+			switch m.action(msg.String()) {
+			case keymap.Quit:
+				m.saveReadingPosition()
+				m.media.Close()
+				return m, tea.Sequence(m.stopPlayback(), tea.Quit)
+			case keymap.Help:
+				m.showHelp = true
+				return m, nil
+			case keymap.CycleTheme:
+				m.switchTheme(m.themes.Next(styles.Current().Name))
+				return m, tea.ClearScreen
+			}
+		}
+		
 		// Handle other views
 		switch m.currentView {
 		case FeedsView:
+			if m.filterInputStage != filterInputNone {
+				return m.updateFilterInput(msg)
+			}
 			return m.updateFeeds(msg)
 		case ArticlesView:
 			return m.updateArticles(msg)
 		case ReaderView:
 			return m.updateReader(msg)
+		case RelaysView:
+			return m.updateRelays(msg)
+		case LogsView:
+			return m.updateLogs(msg)
+		case ComposeView:
+			return m.updateCompose(msg)
+		case LinksView:
+			return m.updateLinks(msg)
+		case GalleryView:
+			return m.updateGallery(msg)
+		case PodcastView:
+			return m.updatePodcasts(msg)
 		}
 		
 	case tea.WindowSizeMsg:
 		m.width = msg.Width
 		m.height = msg.Height
 		// Recreate renderer with new width
-		if renderer, err := feed.NewRenderer(msg.Width); err == nil {
+		if renderer, err := feed.NewRenderer(msg.Width, styles.Current().GlamourStyle()); err == nil {
 			m.renderer = renderer
 		}
+		if m.currentView == ReaderView {
+			m.loadRendered()
+		}
+		if m.currentView == GalleryView && m.inlineImageData == "" {
+			return m, m.drawGallery()
+		}
 		return m, nil
 		
 	case authSuccessMsg:
 		m.authState = AuthSuccess
 		m.currentView = FeedsView
 		m.statusMessage = "Successfully authenticated! Syncing from Nostr..."
-		return m, tea.Batch(m.loadFeeds(), m.loadTags(), m.loadCategories(), m.syncFromNostr())
+		if len(m.keyConflicts) > 0 {
+			m.statusMessage += fmt.Sprintf(" (%d key conflicts, press ? for details)", len(m.keyConflicts))
+		}
+		// Let the fetcher answer relay AUTH challenges too
+		m.fetcher.SetAuthHandler(m.nostr.HandleAuth)
+		return m, tea.Batch(m.loadFeeds(), m.loadTags(), m.loadCategories(), m.syncFromNostr(),
+			m.waitForAuthRequest(), m.waitForAuthResult())
+		
+	case authRequestMsg:
+		req := nostr.AuthRequest(msg)
+		m.authQueue = append(m.authQueue, req)
+		return m, tea.Batch(m.waitForAuthRequest(), waitForAuthDone(req))
+		
+	case authDoneMsg:
+		m.dropAuthRequest(msg.Reply)
+		return m, nil
+		
+	case authResultMsg:
+		if msg.Err != nil {
+			m.statusMessage = fmt.Sprintf("Auth failed for %s: %s", msg.Relay, msg.Err)
+		} else {
+			m.statusMessage = "Authenticated to " + msg.Relay
+		}
+		return m, m.waitForAuthResult()
 		
 	case authErrorMsg:
 		m.authState = AuthError
@@ -192,6 +435,20 @@ func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	case categoriesLoadedMsg:
 		m.categories = msg
 		
+	case smartViewsLoadedMsg:
+		m.smartViews = msg.views
+		m.smartCounts = msg.counts
+		if m.selectedSmartIdx >= len(m.smartViews) {
+			m.selectedSmartIdx = 0
+		}
+		
+	case savedFilterMsg:
+		if msg.err != nil {
+			m.statusMessage = fmt.Sprintf("Failed to save filter: %s", msg.err)
+			return m, nil
+		}
+		return m, m.loadSmartViews()
+		
 	case unreadCountsLoadedMsg:
 		m.unreadCounts = msg
 		
@@ -226,6 +483,7 @@ func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	case articlesLoadedMsg:
 		m.articles = msg
 		m.loading = false
+		m.loadReadingProgress()
 		if len(msg) > 0 {
 			m.statusMessage = fmt.Sprintf("Loaded %d articles", len(msg))
 		}
@@ -234,17 +492,36 @@ func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		
 	case articlesFetchedMsg:
 		if msg.err != nil {
+			slog.Error("failed to fetch articles", "feed", msg.feedID, "err", msg.err)
 			m.statusMessage = fmt.Sprintf("Error fetching articles: %s", msg.err)
 			m.loading = false
 		} else {
 			// Reload articles from database (includes newly fetched)
 			if m.currentFeed != nil && m.currentFeed.ID == msg.feedID {
-				return m, m.loadArticlesForFeed(msg.feedID)
+				return m, tea.Batch(m.loadArticlesForFeed(msg.feedID), m.extractFullContent(msg.feedID), m.syncArchive())
 			}
+			return m, tea.Batch(m.extractFullContent(msg.feedID), m.syncArchive())
+		}
+		
+	case archiveSyncedMsg:
+		return m, m.archiveSynced(msg)
+		
+	case exportedMsg:
+		m.exported(msg)
+		
+	case fullContentMsg:
+		return m, m.fullContentFetched(msg)
+		
+	case fullContentExtractedMsg:
+		if msg.err != nil {
+			slog.Warn("full article extraction failed", "feed", msg.feedID, "err", msg.err)
+		} else if msg.count > 0 && m.currentView != ReaderView {
+			m.statusMessage = fmt.Sprintf("Extracted %d full articles", msg.count)
 		}
 		
 	case syncCompleteMsg:
 		if msg.error != nil {
+			slog.Error("sync failed", "err", msg.error)
 			m.statusMessage = fmt.Sprintf("Sync failed: %s", msg.error)
 		} else {
 			statusParts := []string{}
@@ -263,8 +540,8 @@ func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			} else {
 				m.statusMessage = "Synced from Nostr! (No new data)"
 			}
-			// Reload all data after sync
-			return m, tea.Batch(m.loadFeeds(), m.loadTags(), m.loadCategories())
+			// Reload all data after sync; synced stars may change the archive
+			return m, tea.Batch(m.loadFeeds(), m.loadTags(), m.loadCategories(), m.loadSmartViews(), m.syncArchive())
 		}
 		
 	case inlineImageMsg:
@@ -273,10 +550,122 @@ func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		} else {
 			// Store the inline image data to display
 			m.inlineImageData = msg.imageData
-			m.statusMessage = "Image displayed inline (ESC to close)"
+			m.statusMessage = fmt.Sprintf("Image displayed inline (%s to close)", m.keys.Keys(m.keyContext(), keymap.CloseImage))
+		}
+		
+	case relayStatusMsg:
+		m.relayChecking = false
+		failed := 0
+		for _, status := range msg {
+			m.relayStatuses[status.URL] = status
+			if !status.Connected || status.LastError != "" {
+				failed++
+			}
 		}
+		if failed > 0 {
+			m.statusMessage = fmt.Sprintf("Checked %d relays, %d with problems", len(msg), failed)
+		} else {
+			m.statusMessage = fmt.Sprintf("Checked %d relays, all healthy", len(msg))
+		}
+		
+	case notePublishedMsg:
+		return m, m.notePublished(msg)
+		
+	case socialLoadedMsg:
+		m.socialLoaded(msg)
+		
+	case likedMsg:
+		m.liked(msg)
+		
+	case zappedMsg:
+		m.zapped(msg)
+		
+	case linkOpenedMsg:
+		return m, m.linkOpened(msg)
+		
+	case videoOpenedMsg:
+		if msg.err != nil {
+			m.statusMessage = fmt.Sprintf("Failed to play video: %s", msg.err)
+		} else {
+			m.statusMessage = msg.status
+		}
+		return m, nil
+		
+	case galleryLoadedMsg:
+		return m, m.galleryLoaded(msg)
+		
+	case galleryImageOpenedMsg:
+		m.galleryImageOpened(msg)
+		return m, nil
+		
+	case podcastQueueMsg:
+		return m, m.podcastQueueLoaded(msg)
+		
+	case episodeQueuedMsg:
+		return m, m.episodeQueued(msg)
+		
+	case playerStartedMsg:
+		return m, m.playerStarted(msg)
+		
+	case playerTickMsg:
+		return m, m.playerTicked(msg)
+		
+	case playerCommandMsg:
+		m.playerCommanded(msg)
+		return m, nil
+		
+	case playerDoneMsg:
+		return m, m.playerDone(msg)
+		
+	case articleRenderedMsg:
+		m.articleRendered(msg)
+		
+	case renderSpinnerMsg:
+		return m, m.spinRender()
+		
+	case feedSubscribedMsg:
+		if msg.err != nil {
+			m.statusMessage = fmt.Sprintf("Failed to subscribe: %s", msg.err)
+		} else {
+			m.statusMessage = "Subscribed to " + msg.title
+		}
+		if msg.title != "" {
+			return m, m.loadFeeds()
+		}
+		
+	case bookmarkPublishedMsg:
+		if msg.err != nil {
+			slog.Warn("failed to publish bookmarks", "err", msg.err)
+			m.statusMessage = fmt.Sprintf("Star saved locally, bookmark publish failed: %s", msg.err)
+		}
+		
+	case relayListPublishedMsg:
+		if msg.err != nil {
+			m.statusMessage = fmt.Sprintf("Failed to publish relay list: %s", msg.err)
+		} else {
+			m.statusMessage = "Published relay list (kind 10002)"
+		}
+		
+	case markReadTimerMsg:
+		m.markReadTimerFired(msg)
+		
+	case itemsMarkedMsg:
+		if msg.err != nil {
+			slog.Warn("failed to mark items read", "err", msg.err)
+			m.statusMessage = fmt.Sprintf("Failed to mark read: %s", msg.err)
+		} else {
+			m.statusMessage = fmt.Sprintf("Marked %d articles read", msg.count)
+		}
+		return m, tea.Batch(m.loadUnreadCounts(), m.loadSmartViews())
+		
+	case logTickMsg:
+		if m.currentView == LogsView {
+			return m, waitForLogTick()
+		}
+		m.logTicking = false
 		
 	case errMsg:
+		slog.Error("unhandled error", "err", error(msg))
 		m.err = msg
 	}
 	
@@ -284,17 +673,42 @@ func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 }
 
 func (m *Model) View() string {
+	var view string
 	switch m.currentView {
 	case AuthView:
 		return m.renderAuth()
 	case FeedsView:
-		return m.renderFeeds()
+		view = m.renderFeeds()
 	case ArticlesView:
-		return m.renderArticles()
+		view = m.renderArticles()
 	case ReaderView:
-		return m.renderReader()
+		view = m.renderReader()
+	case RelaysView:
+		view = m.renderRelays()
+	case LogsView:
+		view = m.renderLogs()
+	case ComposeView:
+		view = m.renderCompose()
+	case LinksView:
+		view = m.renderLinks()
+	case GalleryView:
+		view = m.renderGallery()
+	case PodcastView:
+		view = m.renderPodcasts()
 	}
-	return ""
+	
+	if m.showHelp {
+		view = lipgloss.Place(m.width, m.height-1, lipgloss.Center, lipgloss.Center, m.renderHelp())
+	}
+	
+	if playing := m.nowPlaying(); playing != "" {
+		view += "\n" + playing
+	}
+	
+	if len(m.authQueue) > 0 {
+		view += "\n" + m.renderAuthRequest()
+	}
+	return view
 }
 
 func (m *Model) renderAuth() string {
@@ -407,6 +821,8 @@ func (m *Model) renderFeeds() string {
 		viewModeStr = "🏷️  Tags"
 	case ViewModeCategories:
 		viewModeStr = "📂 Categories"
+	case ViewModeSmart:
+		viewModeStr = "🔎 Views"
 	}
 	
 	title := styles.HeaderStyle.Render(viewModeStr)
@@ -414,7 +830,7 @@ func (m *Model) renderFeeds() string {
 	s.WriteString("\n")
 	
 	// View mode toggle hint
-	s.WriteString(styles.MutedStyle.Render("Press Tab to switch views: Feeds • Tags • Categories"))
+	s.WriteString(styles.MutedStyle.Render("Press Tab to switch views: Feeds • Tags • Categories • Views"))
 	s.WriteString("\n\n")
 	
 	// Render content based on view mode
@@ -432,11 +848,23 @@ func (m *Model) renderFeeds() string {
 				if unreadCount > 0 {
 					displayText = fmt.Sprintf("%s (%d)", feed.Title, unreadCount)
 				}
+				if feed.FullContent {
+					displayText += " 📄"
+				}
+				if feed.Archive {
+					displayText += " 💾"
+				}
+				
+				// Category color marker
+				marker := " "
+				if cat := m.feedCategory(feed); cat != nil && cat.Color != "" {
+					marker = styles.CategoryStyle(cat.Color).Render("●")
+				}
 				
 				if i == m.selectedFeedIdx {
-					s.WriteString(styles.SelectedStyle.Render("▸ " + displayText))
+					s.WriteString(marker + styles.SelectedStyle.Render("▸ " + displayText))
 				} else {
-					s.WriteString(styles.FeedItemStyle.Render("  " + displayText))
+					s.WriteString(marker + styles.FeedItemStyle.Render("  " + displayText))
 				}
 				s.WriteString("\n")
 			}
@@ -476,22 +904,28 @@ func (m *Model) renderFeeds() string {
 				if i == m.selectedCategoryIdx {
 					s.WriteString(styles.SelectedStyle.Render("▸ " + displayName))
 				} else {
-					s.WriteString(styles.FeedItemStyle.Render("  " + displayName))
+					s.WriteString(styles.CategoryStyle(cat.Color).PaddingLeft(2).Render("  " + displayName))
 				}
 				s.WriteString("\n")
 			}
 		}
+		
+	case ViewModeSmart:
+		m.renderSmartViews(&s)
 	}
 	
 	s.WriteString("\n\n")
 	
 	// Status bar
 	statusBar := styles.StatusBarStyle.Render(
-		styles.RenderKeyValue("q", "quit") + " • " +
-		styles.RenderKeyValue("tab", "switch view") + " • " +
-		styles.RenderKeyValue("↑↓", "navigate") + " • " +
-		styles.RenderKeyValue("enter", "open") + " • " +
-		styles.RenderKeyValue("s", "sync"))
+		m.hint(keymap.Quit, "quit") + " • " +
+		m.hint(keymap.SwitchView, "switch view") + " • " +
+		m.hint(keymap.Down, "navigate") + " • " +
+		m.hint(keymap.Open, "open") + " • " +
+		m.hint(keymap.Sync, "sync") + " • " +
+		m.hint(keymap.ShowRelays, "relays") + " • " +
+		m.hint(keymap.ShowLogs, "log") + " • " +
+		m.hint(keymap.Help, "help"))
 	s.WriteString(statusBar)
 	
 	if m.statusMessage != "" {
@@ -501,20 +935,25 @@ func (m *Model) renderFeeds() string {
 	return s.String()
 }
 
-func (m *Model) renderArticles() string {
-	var s strings.Builder
-	
-	// Title with feed name
-	feedName := "Articles"
+// articleSourceName names the feed, tag, category or view being listed
+func (m *Model) articleSourceName() string {
 	if m.currentFeed != nil {
-		feedName = m.currentFeed.Title
+		return m.currentFeed.Title
 	} else if m.currentTag != nil {
-		feedName = "Tag: " + m.currentTag.Name
+		return "Tag: " + m.currentTag.Name
 	} else if m.currentCategory != nil {
-		feedName = "Category: " + m.currentCategory.Name
+		return "Category: " + m.currentCategory.Name
+	} else if m.currentSmart != nil {
+		return "View: " + m.currentSmart.Name
 	}
+	return "Articles"
+}
+
+func (m *Model) renderArticles() string {
+	var s strings.Builder
 	
-	title := styles.HeaderStyle.Render("📖 " + feedName)
+	// Title with feed name
+	title := styles.HeaderStyle.Render("📖 " + m.articleSourceName())
 	s.WriteString(title)
 	s.WriteString("\n\n")
 	
@@ -548,6 +987,9 @@ func (m *Model) renderArticles() string {
 			if article.IsRead {
 				readIndicator = "✓ "
 			}
+			if article.IsFavorite {
+				readIndicator = "★ "
+			}
 			
 			dateStr := article.PublishedAt.Format("Jan 02")
 			title := article.Title
@@ -556,6 +998,12 @@ func (m *Model) renderArticles() string {
 			}
 			
 			line := fmt.Sprintf("%s%s - %s", readIndicator, dateStr, title)
+			if m.selected[article.ID] {
+				line = "◉ " + line
+			}
+			if p, ok := m.readingProgress[article.ID]; ok && p.InProgress() {
+				line += fmt.Sprintf(" · %d%%", p.Percent)
+			}
 			
 			if i == m.selectedArticleIdx {
 				s.WriteString(styles.SelectedStyle.Render("▸ " + line))
@@ -574,10 +1022,16 @@ func (m *Model) renderArticles() string {
 	
 	// Status bar
 	statusBar := styles.StatusBarStyle.Render(
-		styles.RenderKeyValue("esc", "back") + " • " +
-		styles.RenderKeyValue("↑↓", "navigate") + " • " +
-		styles.RenderKeyValue("enter", "read") + " • " +
-		styles.RenderKeyValue("r", "refresh"))
+		m.hint(keymap.Back, "back") + " • " +
+		m.hint(keymap.Down, "navigate") + " • " +
+		m.hint(keymap.Open, "read") + " • " +
+		m.hint(keymap.Refresh, "refresh") + " • " +
+		m.hint(keymap.MarkToggle, "read/unread") + " • " +
+		m.hint(keymap.MarkAll, "all read") + " • " +
+		m.hint(keymap.ToggleStar, "star") + " • " +
+		m.hint(keymap.Select, "select") + " • " +
+		m.hint(keymap.Export, "export") + " • " +
+		m.hint(keymap.Help, "help"))
 	s.WriteString(statusBar)
 	
 	if m.statusMessage != "" {
@@ -603,18 +1057,25 @@ func (m *Model) renderReader() string {
 	meta := fmt.Sprintf("By %s • %s", 
 		m.currentArticle.Author,
 		m.currentArticle.PublishedAt.Format("January 2, 2006"))
+	if m.currentArticle.PublishedAt.IsZero() {
+		meta = "By " + m.currentArticle.Author // Followed links have no date
+	}
 	s.WriteString(styles.MutedStyle.Render(meta))
-	s.WriteString("\n\n")
+	s.WriteString("\n")
+	if social := m.renderSocial(); social != "" {
+		s.WriteString(social)
+		s.WriteString("\n")
+	}
+	s.WriteString("\n")
 	
-	// Render content
-	isHTML := strings.Contains(m.currentArticle.Content, "<html") || 
-	          strings.Contains(m.currentArticle.Content, "<div")
+	// Rendered in the background, see renderArticle
+	content := m.articleContent()
 	
 	// If we have inline image data (from 'i' key), show it instead
 	if m.inlineImageData != "" {
 		s.WriteString(lipgloss.NewStyle().
 			Bold(true).
-			Foreground(lipgloss.Color("#00FF00")).
+			Foreground(styles.SuccessColor).
 			Render("🖼️  IMAGE VIEWER"))
 		s.WriteString("\n")
 		s.WriteString(strings.Repeat("─", m.width))
@@ -624,9 +1085,9 @@ func (m *Model) renderReader() string {
 		s.WriteString(strings.Repeat("─", m.width))
 		s.WriteString("\n")
 		s.WriteString(styles.StatusBarStyle.Render(
-			styles.RenderKeyValue("i", "close image") + " • " +
-			styles.RenderKeyValue("ESC", "back to articles") + " • " +
-			styles.RenderKeyValue("I", "external viewer")))
+			m.hint(keymap.CloseImage, "close image") + " • " +
+			m.hint(keymap.Back, "back to articles") + " • " +
+			m.hint(keymap.ExternalImage, "external viewer")))
 		s.WriteString("\n")
 		if m.statusMessage != "" {
 			s.WriteString("\n")
@@ -636,15 +1097,23 @@ func (m *Model) renderReader() string {
 	}
 	
 	// Use simple rendering (no inline images to avoid freezing)
-	rendered, err := m.renderer.RenderContent(m.currentArticle.Content, isHTML)
+	var lines []string
+	var err error
+	if m.threadOpen {
+		lines = strings.Split(m.renderThread(), "\n")
+	} else if m.rendered != nil {
+		lines, err = m.rendered.lines, m.rendered.err
+	}
 	if err != nil {
 		s.WriteString(styles.ErrorStyle.Render(fmt.Sprintf("Error rendering: %v", err)))
 		s.WriteString("\n\n")
-		s.WriteString(m.currentArticle.Content) // Fallback to raw
+		s.WriteString(content) // Fallback to raw
+	} else if lines == nil {
+		s.WriteString(m.renderSpinner())
+		s.WriteString("\n")
 	} else {
 		// Apply scroll offset
-		lines := strings.Split(rendered, "\n")
-		visibleLines := m.height - 8 // Leave room for header/footer
+		visibleLines := m.readerVisibleLines()
 		
 		start := m.articleScrollOffset
 		if start >= len(lines) {
@@ -673,21 +1142,41 @@ func (m *Model) renderReader() string {
 	s.WriteString("\n")
 	
 	// Status bar
-	statusBarKeys := styles.RenderKeyValue("esc", "back") + " • " +
-		styles.RenderKeyValue("↑↓", "scroll") + " • " +
-		styles.RenderKeyValue("space", "page down")
+	statusBarKeys := m.hint(keymap.Back, "back") + " • " +
+		m.hint(keymap.Down, "scroll") + " • " +
+		m.hint(keymap.PageDown, "page down")
 	
 	if m.currentMedia != nil && len(m.currentMedia.Images) > 1 {
-		statusBarKeys += " • " + styles.RenderKeyValue("←→", "image")
+		statusBarKeys += " • " + styles.RenderKeyValue(m.navKeys(keymap.PrevImage, keymap.NextImage), "image")
 	}
 	
-	statusBarKeys += " • " + styles.RenderKeyValue("i", "view") + " • " +
-		styles.RenderKeyValue("o", "browser") + " • " +
-		styles.RenderKeyValue("v", "video")
+	statusBarKeys += " • " + m.hint(keymap.ViewImage, "view") + " • " +
+		m.hint(keymap.OpenBrowser, "browser") + " • " +
+		m.hint(keymap.ShowLinks, "links") + " • " +
+		m.hint(keymap.PlayVideo, "video") + " • " +
+		m.hint(keymap.Share, "share") + " • "
+	if label := m.fullArticleHint(); label != "" {
+		statusBarKeys += m.hint(keymap.FullArticle, label) + " • "
+	}
+	if _, ok := m.articleRef(); ok {
+		statusBarKeys += m.hint(keymap.Like, "like") + " • " +
+			m.hint(keymap.Reply, "reply") + " • " +
+			m.hint(keymap.Thread, "thread") + " • " +
+			m.hint(keymap.Zap, "zap") + " • "
+	}
+	statusBarKeys += m.hint(keymap.Help, "help")
 	
+	if m.zapInputActive {
+		s.WriteString(m.renderZapPrompt())
+		s.WriteString("\n")
+	}
 	statusBar := styles.StatusBarStyle.Render(statusBarKeys)
 	s.WriteString(statusBar)
 	
+	if m.statusMessage != "" {
+		s.WriteString("\n" + styles.SuccessStyle.Render(m.statusMessage))
+	}
+	
 	return s.String()
 }
 
@@ -702,3 +1191,14 @@ type syncCompleteMsg struct {
 	error             error
 }
 type errMsg error
+
+// textInputActive reports whether keys are being typed into a text field
+func (m *Model) textInputActive() bool {
+	return m.relayInputActive || m.filterInputStage != filterInputNone || m.zapInputActive ||
+		(m.compose != nil && m.compose.stage == composeEditing)
+}
+
+// isHTMLContent guesses whether article content is HTML rather than Markdown
+func isHTMLContent(content string) bool {
+	return feed.IsHTML(content)
+}
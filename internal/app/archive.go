@@ -0,0 +1,81 @@
+package app
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/plebone/nostrfeedz-cli/internal/archive"
+	"github.com/plebone/nostrfeedz-cli/internal/db"
+)
+
+type archiveSyncedMsg struct {
+	result archive.Result
+	err    error
+}
+
+// syncArchive archives starred items and items of archived feeds in the
+// background
+func (m *Model) syncArchive() tea.Cmd {
+	if m.archiver == nil {
+		return nil
+	}
+	return func() tea.Msg {
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Minute)
+		defer cancel()
+		result, err := m.archiver.Sync(ctx)
+		return archiveSyncedMsg{result: result, err: err}
+	}
+}
+
+// archiveSynced reports a finished archive sync and continues with the
+// next batch if there is one
+func (m *Model) archiveSynced(msg archiveSyncedMsg) tea.Cmd {
+	if msg.err != nil {
+		slog.Warn("archive sync failed", "err", msg.err)
+		return nil
+	}
+	r := msg.result
+	if r.Archived > 0 || r.Removed > 0 {
+		slog.Info("archive synced", "archived", r.Archived, "removed", r.Removed, "failed", r.Failed)
+	}
+	if r.Archived > 0 && m.currentView != ReaderView {
+		m.statusMessage = fmt.Sprintf("Archived %d articles for offline reading", r.Archived)
+	}
+	if r.More {
+		return m.syncArchive()
+	}
+	return nil
+}
+
+// toggleFeedArchive turns archiving of all of a feed's items on or off
+func (m *Model) toggleFeedArchive(f *db.Feed) tea.Cmd {
+	if err := m.db.SetFeedArchive(f.ID, !f.Archive); err != nil {
+		slog.Warn("failed to update feed", "feed", f.ID, "err", err)
+		m.statusMessage = fmt.Sprintf("Failed to update feed: %s", err)
+		return nil
+	}
+	f.Archive = !f.Archive
+	if f.Archive {
+		m.statusMessage = fmt.Sprintf("Archiving all articles of %s for offline reading", f.Title)
+	} else {
+		m.statusMessage = fmt.Sprintf("No longer archiving %s", f.Title)
+	}
+	return m.syncArchive()
+}
+
+// startArchiveExport asks which format to export the archive in
+func (m *Model) startArchiveExport() {
+	if m.archiver == nil {
+		m.statusMessage = "The archive is unavailable (no image cache)"
+		return
+	}
+	m.startExport(pendingExport{
+		what:  "the archive",
+		name:  "nostrfeedz-archive",
+		title: "NostrFeedz archive, " + time.Now().Format("January 2, 2006"),
+		load:  m.archiver.Articles,
+	})
+}
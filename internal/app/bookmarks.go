@@ -0,0 +1,161 @@
+package app
+
+import (
+	"fmt"
+	"log/slog"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/nbd-wtf/go-nostr"
+	"github.com/plebone/nostrfeedz-cli/internal/db"
+	nostrClient "github.com/plebone/nostrfeedz-cli/internal/nostr"
+)
+
+type bookmarkPublishedMsg struct {
+	starred bool
+	err     error
+}
+
+// bookmarkRefs returns the NIP-51 references for an item: for Nostr
+// articles the event ID and, for naddr links, the coordinate that survives
+// edits; the link for everything else
+func bookmarkRefs(item *db.FeedItem) []nostr.Tag {
+	switch {
+	case strings.HasPrefix(item.URL, "nostr:"):
+		refs := []nostr.Tag{nostrClient.EventBookmark(item.GUID)}
+		if ref, ok := nostrClient.ArticleRefFor(item.URL, item.GUID); ok && ref.Coordinate != "" {
+			refs = append(refs, nostrClient.ArticleBookmark(ref.Coordinate))
+		}
+		return refs
+	case item.URL != "":
+		return []nostr.Tag{nostrClient.URLBookmark(item.URL)}
+	}
+	return nil
+}
+
+// bookmarkURLs returns the links of the items a list bookmarks, RSS pages
+// and Nostr articles by coordinate
+func bookmarkURLs(list *nostrClient.BookmarkList) []string {
+	return append(list.URLs(), list.ArticleLinks()...)
+}
+
+// toggleItemStar stars or unstars an item and updates the bookmark list
+func (m *Model) toggleItemStar(item *db.FeedItem) tea.Cmd {
+	if err := m.db.ToggleFavorite(item.ID); err != nil {
+		slog.Warn("failed to update star", "item", item.ID, "err", err)
+		m.statusMessage = fmt.Sprintf("Failed to update star: %s", err)
+		return nil
+	}
+	item.IsFavorite = !item.IsFavorite
+	for i := range m.articles {
+		if m.articles[i].ID == item.ID && &m.articles[i] != item {
+			m.articles[i].IsFavorite = item.IsFavorite
+		}
+	}
+	if item.IsFavorite {
+		m.statusMessage = "Starred"
+	} else {
+		m.statusMessage = "Unstarred"
+	}
+
+	// Offline, the change stays pending until the next sync
+	if m.nostr == nil {
+		return m.syncArchive()
+	}
+	return tea.Batch(m.publishBookmark(item.IsFavorite), m.syncArchive())
+}
+
+// publishBookmark publishes the pending stars, starting from the latest
+// published list so bookmarks made on other devices are kept
+func (m *Model) publishBookmark(starred bool) tea.Cmd {
+	return func() tea.Msg {
+		m.bookmarkMu.Lock()
+		defer m.bookmarkMu.Unlock()
+
+		list, err := m.fetchBookmarks()
+		if err != nil {
+			return bookmarkPublishedMsg{starred, err}
+		}
+		return bookmarkPublishedMsg{starred, m.publishPendingStars(list, false)}
+	}
+}
+
+// publishPendingStars applies the stars and unstars not published yet to
+// list and publishes it if anything changed, or if changed is already set.
+// The changes stay pending when publishing fails. Call with bookmarkMu held.
+func (m *Model) publishPendingStars(list *nostrClient.BookmarkList, changed bool) error {
+	pending, err := m.db.GetPendingStars()
+	if err != nil {
+		return err
+	}
+	for i := range pending {
+		for _, ref := range bookmarkRefs(&pending[i]) {
+			if pending[i].IsFavorite {
+				changed = list.Add(ref) || changed
+			} else {
+				changed = list.Remove(ref) || changed
+			}
+		}
+	}
+	if changed {
+		if err := m.nostr.PublishBookmarks(list); err != nil {
+			return err
+		}
+	}
+	for _, item := range pending {
+		if err := m.db.ClearPendingStar(item.ID, item.IsFavorite); err != nil {
+			slog.Warn("failed to clear pending star", "item", item.ID, "err", err)
+		}
+	}
+	return nil
+}
+
+// fetchBookmarks returns the configured bookmark list, or an empty one if
+// it has never been published
+func (m *Model) fetchBookmarks() (*nostrClient.BookmarkList, error) {
+	list, err := m.nostr.FetchBookmarks(m.nostr.GetPublicKey(), m.cfg.Nostr.BookmarkSet)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch bookmarks: %w", err)
+	}
+	if list == nil {
+		list = &nostrClient.BookmarkList{Identifier: m.cfg.Nostr.BookmarkSet}
+	}
+	return list, nil
+}
+
+// syncBookmarks merges local stars with the published bookmark list: stars
+// and unstars not published yet, including ones made offline, are applied
+// to the list first, then local stars are made to match it. When no list
+// exists yet, local stars are published to start one.
+func (m *Model) syncBookmarks() error {
+	m.bookmarkMu.Lock()
+	defer m.bookmarkMu.Unlock()
+
+	set := m.cfg.Nostr.BookmarkSet
+	list, err := m.nostr.FetchBookmarks(m.nostr.GetPublicKey(), set)
+	if err != nil {
+		return fmt.Errorf("failed to fetch bookmarks: %w", err)
+	}
+	fresh := false
+	if list == nil {
+		starred, err := m.db.QueryFeedItems(db.ItemFilter{Starred: true, Limit: -1})
+		if err != nil {
+			return err
+		}
+		list = &nostrClient.BookmarkList{Identifier: set}
+		for i := range starred {
+			for _, ref := range bookmarkRefs(&starred[i]) {
+				list.Add(ref)
+			}
+		}
+		fresh = len(list.Tags) > 0
+		if fresh {
+			slog.Info("publishing local stars as bookmarks", "count", len(list.Tags), "set", list.Identifier)
+		}
+	}
+
+	if err := m.publishPendingStars(list, fresh); err != nil {
+		return fmt.Errorf("failed to publish pending stars: %w", err)
+	}
+	return m.db.SetFavorites(list.EventIDs(), bookmarkURLs(list))
+}
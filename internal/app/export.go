@@ -0,0 +1,182 @@
+package app
+
+import (
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/plebone/nostrfeedz-cli/internal/config"
+	"github.com/plebone/nostrfeedz-cli/internal/db"
+	"github.com/plebone/nostrfeedz-cli/internal/export"
+)
+
+// pendingExport is an export waiting for the user to pick a format
+type pendingExport struct {
+	what  string // Shown in the prompt, e.g. "3 articles"
+	name  string // File name, before the date and extension
+	title string // Title of the digest or book
+	load  func() ([]export.Article, error)
+}
+
+type exportedMsg struct {
+	paths []string
+	count int
+	err   error
+}
+
+// startExport asks which format to export in
+func (m *Model) startExport(p pendingExport) {
+	m.exportPrompt = &p
+	m.statusMessage = fmt.Sprintf("Export %s as (m)arkdown, (h)tml, (e)pub or (t)ext? (esc to cancel)", p.what)
+}
+
+// answerExportPrompt picks the export format, or cancels on any other key
+func (m *Model) answerExportPrompt(key string) tea.Cmd {
+	p := m.exportPrompt
+	m.exportPrompt = nil
+	formats := map[string]export.Format{"m": export.Markdown, "h": export.HTML, "e": export.EPUB, "t": export.Text}
+	format, ok := formats[key]
+	if !ok {
+		m.statusMessage = ""
+		return nil
+	}
+
+	m.statusMessage = fmt.Sprintf("Exporting %s...", p.what)
+	dir := config.GetExportDir(m.cfg)
+	var images export.Images
+	if m.imgCache != nil {
+		images = m.imgCache
+	}
+	return func() tea.Msg {
+		articles, err := p.load()
+		if err != nil {
+			return exportedMsg{err: err}
+		}
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			return exportedMsg{err: err}
+		}
+		// Markdown is a directory of files, the rest a single file
+		path := filepath.Join(dir, fmt.Sprintf("%s-%s%s", p.name, time.Now().Format("2006-01-02"), format.Ext()))
+		paths, err := export.Write(format, path, p.title, articles, images)
+		if err != nil {
+			return exportedMsg{err: err}
+		}
+		return exportedMsg{paths: paths, count: len(articles)}
+	}
+}
+
+func (m *Model) exported(msg exportedMsg) {
+	if msg.err != nil {
+		slog.Warn("export failed", "err", msg.err)
+		m.statusMessage = fmt.Sprintf("Export failed: %s", msg.err)
+		return
+	}
+	slog.Info("exported articles", "files", len(msg.paths), "articles", msg.count)
+	where := msg.paths[0]
+	if len(msg.paths) > 1 {
+		where = filepath.Dir(where)
+	}
+	m.statusMessage = fmt.Sprintf("Exported %d articles to %s", msg.count, where)
+}
+
+// toggleSelected selects or deselects the article under the cursor for
+// export and moves on to the next one
+func (m *Model) toggleSelected() {
+	if m.selectedArticleIdx >= len(m.articles) {
+		return
+	}
+	id := m.articles[m.selectedArticleIdx].ID
+	if m.selected == nil {
+		m.selected = make(map[string]bool)
+	}
+	if m.selected[id] {
+		delete(m.selected, id)
+	} else {
+		m.selected[id] = true
+	}
+	if m.selectedArticleIdx < len(m.articles)-1 {
+		m.selectedArticleIdx++
+	}
+	m.statusMessage = fmt.Sprintf("%d selected", len(m.selected))
+}
+
+// exportArticles exports the selected articles, or every listed one when
+// nothing is selected
+func (m *Model) exportArticles() {
+	var items []db.FeedItem
+	for _, item := range m.articles {
+		if len(m.selected) == 0 || m.selected[item.ID] {
+			items = append(items, item)
+		}
+	}
+	if len(items) == 0 {
+		m.statusMessage = "No articles to export"
+		return
+	}
+
+	source := m.articleSourceName()
+	what := fmt.Sprintf("%d articles", len(items))
+	if len(items) == 1 {
+		what = "1 article"
+	}
+	m.startExport(pendingExport{
+		what:  what,
+		name:  "nostrfeedz-" + slug(source),
+		title: source,
+		load:  m.exportLoader(items),
+	})
+}
+
+// exportCurrentArticle exports the article open in the reader
+func (m *Model) exportCurrentArticle() {
+	if m.currentArticle == nil {
+		return
+	}
+	item := *m.currentArticle
+	m.startExport(pendingExport{
+		what:  "this article",
+		name:  "nostrfeedz-" + slug(item.Title),
+		title: item.Title,
+		load:  m.exportLoader([]db.FeedItem{item}),
+	})
+}
+
+// exportLoader loads items as export articles when the export runs
+func (m *Model) exportLoader(items []db.FeedItem) func() ([]export.Article, error) {
+	return func() ([]export.Article, error) {
+		return export.Load(m.db, items)
+	}
+}
+
+// slug makes a name safe to use in a file name
+func slug(name string) string {
+	var b []rune
+	dash := false
+	for _, r := range name {
+		switch {
+		case r >= 'a' && r <= 'z', r >= '0' && r <= '9':
+			b = append(b, r)
+			dash = false
+		case r >= 'A' && r <= 'Z':
+			b = append(b, r+'a'-'A')
+			dash = false
+		case !dash && len(b) > 0:
+			b = append(b, '-')
+			dash = true
+		}
+	}
+	if len(b) > 40 {
+		b = b[:40]
+	}
+	s := string(b)
+	for len(s) > 0 && s[len(s)-1] == '-' {
+		s = s[:len(s)-1]
+	}
+	if s == "" {
+		return "articles"
+	}
+	return s
+}
@@ -0,0 +1,197 @@
+package app
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/plebone/nostrfeedz-cli/internal/db"
+	"github.com/plebone/nostrfeedz-cli/internal/feed"
+	"github.com/plebone/nostrfeedz-cli/internal/keymap"
+)
+
+// extractBatch caps how many pages one fetch extracts, so turning the
+// setting on for a large feed does not download its whole history
+const extractBatch = 10
+
+type fullContentMsg struct {
+	itemID  string
+	content string
+	err     error
+}
+
+type fullContentExtractedMsg struct {
+	feedID string
+	count  int
+	err    error
+}
+
+// articleContent is what the reader shows: the extracted article when
+// there is one, unless the feed's summary was asked for
+func (m *Model) articleContent() string {
+	if m.currentArticle == nil {
+		return ""
+	}
+	if m.currentArticle.FullContent != "" && !m.showSummary {
+		return m.currentArticle.FullContent
+	}
+	return m.currentArticle.Content
+}
+
+// loadFullContent fills in the open article's extracted content
+func (m *Model) loadFullContent() {
+	m.showSummary = false
+	item := m.currentArticle
+	if item == nil || item.ID == "" || item.FullContent != "" {
+		return
+	}
+	content, err := m.db.GetItemFullContent(item.ID)
+	if err != nil {
+		slog.Warn("failed to load full article", "item", item.ID, "err", err)
+		return
+	}
+	item.FullContent = content
+}
+
+// fullArticleHint labels the reader's full article key, or returns "" when
+// the open article has no page to extract
+func (m *Model) fullArticleHint() string {
+	item := m.currentArticle
+	if item == nil || item.ID == "" || item.URL == "" || m.threadOpen {
+		return ""
+	}
+	if _, ok := m.articleRef(); ok {
+		return "" // Nostr articles are already complete
+	}
+	if item.FullContent != "" && !m.showSummary {
+		return "summary"
+	}
+	return "full article"
+}
+
+// toggleFullArticle switches between the summary and the extracted article,
+// fetching the article the first time
+func (m *Model) toggleFullArticle() tea.Cmd {
+	if m.fullArticleHint() == "" {
+		return nil
+	}
+	item := m.currentArticle
+	if item.FullContent != "" {
+		m.showSummary = !m.showSummary
+		m.articleContentChanged()
+		if m.showSummary {
+			m.statusMessage = "Showing the feed's summary"
+		} else {
+			m.statusMessage = "Showing the full article"
+		}
+		return tea.ClearScreen
+	}
+
+	m.statusMessage = "Fetching the full article..."
+	itemID, pageURL := item.ID, item.URL
+	return func() tea.Msg {
+		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+		defer cancel()
+		article, err := feed.FetchArticle(ctx, pageURL)
+		if err != nil {
+			return fullContentMsg{itemID: itemID, err: err}
+		}
+		if err := m.db.SetItemFullContent(itemID, article.Content); err != nil {
+			return fullContentMsg{itemID: itemID, err: err}
+		}
+		return fullContentMsg{itemID: itemID, content: article.Content}
+	}
+}
+
+// fullContentFetched shows an article extracted on demand
+func (m *Model) fullContentFetched(msg fullContentMsg) tea.Cmd {
+	if msg.err != nil {
+		slog.Warn("failed to extract full article", "item", msg.itemID, "err", msg.err)
+		m.statusMessage = fmt.Sprintf("Failed to fetch the full article: %s", msg.err)
+		return nil
+	}
+	for i := range m.articles {
+		if m.articles[i].ID == msg.itemID {
+			m.articles[i].FullContent = msg.content
+		}
+	}
+	if m.currentArticle == nil || m.currentArticle.ID != msg.itemID {
+		return nil
+	}
+	m.currentArticle.FullContent = msg.content
+	m.showSummary = false
+	m.articleContentChanged()
+	m.statusMessage = fmt.Sprintf("Showing the full article (%s for the summary)", m.keys.Keys(keymap.Reader, keymap.FullArticle))
+	return tea.ClearScreen
+}
+
+// articleContentChanged refreshes what the reader derives from the content
+func (m *Model) articleContentChanged() {
+	m.articleScrollOffset = 0
+	m.selectedImageIdx = 0
+	m.selectedVideoIdx = 0
+	m.currentMedia = m.extractMedia()
+	m.loadRendered()
+}
+
+// toggleFeedFullContent turns full article extraction on or off for a feed
+func (m *Model) toggleFeedFullContent(f *db.Feed) {
+	if f.Type != "rss" {
+		m.statusMessage = "Full articles are only extracted for RSS feeds"
+		return
+	}
+	if err := m.db.SetFeedFullContent(f.ID, !f.FullContent); err != nil {
+		slog.Warn("failed to update feed", "feed", f.ID, "err", err)
+		m.statusMessage = fmt.Sprintf("Failed to update feed: %s", err)
+		return
+	}
+	f.FullContent = !f.FullContent
+	if f.FullContent {
+		m.statusMessage = fmt.Sprintf("Full articles will be extracted for %s when it is fetched", f.Title)
+	} else {
+		m.statusMessage = fmt.Sprintf("Showing feed content only for %s", f.Title)
+	}
+}
+
+// extractFullContent extracts the articles of a feed's new items in the
+// background when the feed has full article extraction turned on
+func (m *Model) extractFullContent(feedID string) tea.Cmd {
+	var target *db.Feed
+	for i := range m.feeds {
+		if m.feeds[i].ID == feedID {
+			target = &m.feeds[i]
+		}
+	}
+	if target == nil || !target.FullContent || target.Type != "rss" {
+		return nil
+	}
+
+	return func() tea.Msg {
+		items, err := m.db.GetItemsWithoutFullContent(feedID, extractBatch)
+		if err != nil {
+			return fullContentExtractedMsg{feedID: feedID, err: err}
+		}
+
+		count := 0
+		for _, item := range items {
+			ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+			article, err := feed.FetchArticle(ctx, item.URL)
+			cancel()
+			content := ""
+			if err != nil {
+				slog.Debug("full article extraction failed", "url", item.URL, "err", err)
+			} else {
+				content = article.Content
+				count++
+			}
+			// Failures are stored empty so they are not retried every fetch;
+			// the reader can still try again on demand
+			if err := m.db.SetItemFullContent(item.ID, content); err != nil {
+				return fullContentExtractedMsg{feedID: feedID, count: count, err: err}
+			}
+		}
+		return fullContentExtractedMsg{feedID: feedID, count: count}
+	}
+}
@@ -0,0 +1,228 @@
+package app
+
+import (
+	"fmt"
+	"log/slog"
+	"strings"
+	"sync"
+
+	"github.com/blacktop/go-termimg"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/plebone/nostrfeedz-cli/internal/cache"
+	"github.com/plebone/nostrfeedz-cli/internal/feed"
+	"github.com/plebone/nostrfeedz-cli/internal/keymap"
+	"github.com/plebone/nostrfeedz-cli/pkg/styles"
+)
+
+type galleryLoadedMsg struct {
+	media  *feed.MediaLinks // Whose images the thumbnails are
+	thumbs []string
+}
+
+type galleryImageOpenedMsg struct {
+	err error // From downloading or opening the image
+}
+
+// openGallery shows the open article's images as a grid of thumbnails
+func (m *Model) openGallery() tea.Cmd {
+	if m.currentMedia == nil || len(m.currentMedia.Images) == 0 {
+		m.statusMessage = "No images found in article"
+		return nil
+	}
+	if m.imgCache == nil {
+		m.statusMessage = "Image cache unavailable"
+		return nil
+	}
+	m.currentView = GalleryView
+	m.galleryThumbs = nil
+	m.galleryData = ""
+	m.inlineImageData = ""
+	m.statusMessage = fmt.Sprintf("Loading %d images...", len(m.currentMedia.Images))
+	return tea.Batch(tea.ClearScreen, m.loadGallery(m.currentMedia))
+}
+
+// loadGallery downloads an article's images and makes their thumbnails,
+// a few at a time
+func (m *Model) loadGallery(media *feed.MediaLinks) tea.Cmd {
+	return func() tea.Msg {
+		thumbs := make([]string, len(media.Images))
+		slots := make(chan struct{}, cache.DownloadWorkers)
+		var wg sync.WaitGroup
+		for i, url := range media.Images {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				slots <- struct{}{}
+				defer func() { <-slots }()
+				path, err := m.imgCache.Thumbnail(url, cache.ThumbnailWidth, cache.ThumbnailHeight)
+				if err != nil {
+					slog.Debug("failed to make thumbnail", "url", url, "err", err)
+					return
+				}
+				thumbs[i] = path
+			}()
+		}
+		wg.Wait()
+		return galleryLoadedMsg{media: media, thumbs: thumbs}
+	}
+}
+
+// galleryLoaded shows the thumbnails if the gallery is still open
+func (m *Model) galleryLoaded(msg galleryLoadedMsg) tea.Cmd {
+	if m.currentView != GalleryView || msg.media != m.currentMedia {
+		return nil
+	}
+	m.galleryThumbs = msg.thumbs
+	failed := 0
+	for _, path := range msg.thumbs {
+		if path == "" {
+			failed++
+		}
+	}
+	m.statusMessage = ""
+	if failed > 0 {
+		m.statusMessage = fmt.Sprintf("%d of %d images could not be loaded", failed, len(msg.thumbs))
+	}
+	return m.drawGallery()
+}
+
+// galleryLayout returns the number of columns of the grid and how many
+// thumbnails fit on screen
+func (m *Model) galleryLayout() (columns, perPage int) {
+	columns = max((m.width-2)/feed.GalleryCellWidth, 1)
+	rows := max((m.height-8)/feed.GalleryCellHeight, 1)
+	return columns, columns * rows
+}
+
+// drawGallery renders the page of the grid holding the selected image
+func (m *Model) drawGallery() tea.Cmd {
+	if len(m.galleryThumbs) == 0 {
+		return nil
+	}
+	columns, perPage := m.galleryLayout()
+	first := m.selectedImageIdx / perPage * perPage
+	last := min(first+perPage, len(m.galleryThumbs))
+
+	termimg.ClearAll() // Clear the previous grid
+	data, err := m.images.RenderGallery(m.galleryThumbs[first:last], m.selectedImageIdx-first, columns, styles.AccentColor)
+	if err != nil {
+		m.galleryData = ""
+		m.statusMessage = fmt.Sprintf("Failed to display images: %s", err)
+		return tea.ClearScreen
+	}
+	m.galleryData = data
+	return tea.ClearScreen
+}
+
+func (m *Model) updateGallery(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	images := m.currentMedia.Images
+	action := m.action(msg.String())
+
+	// A full size image is showing: only closing it or opening it
+	// externally make sense
+	if m.inlineImageData != "" {
+		switch action {
+		case keymap.CloseImage, keymap.Back:
+			termimg.ClearAll() // Clear terminal images
+			m.inlineImageData = ""
+			m.statusMessage = ""
+			return m, m.drawGallery()
+		case keymap.ExternalImage:
+			return m, m.openGalleryImage()
+		}
+		return m, nil
+	}
+
+	columns, _ := m.galleryLayout()
+	selected := m.selectedImageIdx
+	switch action {
+	case keymap.Up:
+		if selected-columns >= 0 {
+			selected -= columns
+		}
+	case keymap.Down:
+		if selected+columns < len(images) {
+			selected += columns
+		}
+	case keymap.PrevImage:
+		if selected > 0 {
+			selected--
+		}
+	case keymap.NextImage:
+		if selected < len(images)-1 {
+			selected++
+		}
+	case keymap.Open:
+		m.statusMessage = "Loading image..."
+		return m, m.showInlineImage(images[selected])
+	case keymap.ExternalImage:
+		return m, m.openGalleryImage()
+	case keymap.Back:
+		termimg.ClearAll() // Clear terminal images
+		m.currentView = ReaderView
+		m.galleryThumbs = nil
+		m.galleryData = ""
+		m.statusMessage = ""
+		return m, tea.ClearScreen
+	}
+
+	if selected != m.selectedImageIdx {
+		m.selectedImageIdx = selected
+		return m, m.drawGallery()
+	}
+	return m, nil
+}
+
+// openGalleryImage downloads the selected image, if not cached yet, and
+// opens it in the external viewer
+func (m *Model) openGalleryImage() tea.Cmd {
+	imageURL := m.currentMedia.Images[m.selectedImageIdx]
+	m.statusMessage = "Loading image..."
+	return func() tea.Msg {
+		path, err := m.imgCache.Download(imageURL)
+		if err != nil {
+			return galleryImageOpenedMsg{fmt.Errorf("failed to load image: %w", err)}
+		}
+		return galleryImageOpenedMsg{m.openImage(path)}
+	}
+}
+
+// galleryImageOpened reports whether the external viewer was opened
+func (m *Model) galleryImageOpened(msg galleryImageOpenedMsg) {
+	if msg.err != nil {
+		m.statusMessage = fmt.Sprintf("Failed to open image: %s", msg.err)
+		return
+	}
+	m.statusMessage = "Opened image in external viewer"
+}
+
+func (m *Model) renderGallery() string {
+	var s strings.Builder
+	images := m.currentMedia.Images
+	s.WriteString(styles.HeaderStyle.Render(fmt.Sprintf("🖼️  Images in %s", m.currentArticle.Title)))
+	s.WriteString("\n")
+	s.WriteString(styles.MutedStyle.Render(fmt.Sprintf("[%d/%d] %s", m.selectedImageIdx+1, len(images), images[m.selectedImageIdx])))
+	s.WriteString("\n\n")
+
+	if m.inlineImageData != "" {
+		s.WriteString(m.inlineImageData)
+		s.WriteString("\n\n")
+		s.WriteString(styles.StatusBarStyle.Render(
+			m.hint(keymap.CloseImage, "back to grid") + " • " +
+				m.hint(keymap.ExternalImage, "external viewer")))
+	} else {
+		if m.galleryData != "" {
+			s.WriteString(m.galleryData)
+			s.WriteString("\n\n")
+		}
+		s.WriteString(styles.StatusBarStyle.Render(
+			styles.RenderKeyValue(m.navKeys(keymap.PrevImage, keymap.NextImage)+" "+m.navKeys(keymap.Up, keymap.Down), "select") + " • " +
+				m.hint(keymap.Open, "full size") + " • " +
+				m.hint(keymap.ExternalImage, "external viewer") + " • " +
+				m.hint(keymap.Back, "back")))
+	}
+	if m.statusMessage != "" {
+		s.WriteString("\n" + styles.MutedStyle.Render(m.statusMessage))
+	}
+	return s.String()
+}
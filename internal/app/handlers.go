@@ -4,7 +4,7 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
-	"os"
+	"log/slog"
 	"os/exec"
 	"time"
 	
@@ -15,6 +15,7 @@ import (
 	"github.com/nbd-wtf/go-nostr/nip19"
 	"github.com/plebone/nostrfeedz-cli/internal/config"
 	"github.com/plebone/nostrfeedz-cli/internal/db"
+	"github.com/plebone/nostrfeedz-cli/internal/keymap"
 	nostrClient "github.com/plebone/nostrfeedz-cli/internal/nostr"
 )
 
@@ -93,13 +94,14 @@ func (m *Model) updateAuth(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 }
 
 func (m *Model) updateFeeds(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
-	switch msg.String() {
-	case "tab":
+	switch m.action(msg.String()) {
+	case keymap.SwitchView:
 		// Toggle between view modes
-		m.viewMode = (m.viewMode + 1) % 3
+		m.viewMode = (m.viewMode + 1) % 4
 		m.selectedFeedIdx = 0
 		m.selectedTagIdx = 0
 		m.selectedCategoryIdx = 0
+		m.selectedSmartIdx = 0
 		
 		// Load data for new view mode
 		switch m.viewMode {
@@ -109,9 +111,11 @@ func (m *Model) updateFeeds(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 			return m, m.loadTags()
 		case ViewModeCategories:
 			return m, m.loadCategories()
+		case ViewModeSmart:
+			return m, m.loadSmartViews()
 		}
 		
-	case "up", "k":
+	case keymap.Up:
 		switch m.viewMode {
 		case ViewModeFeeds:
 			if m.selectedFeedIdx > 0 {
@@ -125,9 +129,13 @@ func (m *Model) updateFeeds(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 			if m.selectedCategoryIdx > 0 {
 				m.selectedCategoryIdx--
 			}
+		case ViewModeSmart:
+			if m.selectedSmartIdx > 0 {
+				m.selectedSmartIdx--
+			}
 		}
 		
-	case "down", "j":
+	case keymap.Down:
 		switch m.viewMode {
 		case ViewModeFeeds:
 			if m.selectedFeedIdx < len(m.feeds)-1 {
@@ -141,12 +149,17 @@ func (m *Model) updateFeeds(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 			if m.selectedCategoryIdx < len(m.categories)-1 {
 				m.selectedCategoryIdx++
 			}
+		case ViewModeSmart:
+			if m.selectedSmartIdx < len(m.smartViews)-1 {
+				m.selectedSmartIdx++
+			}
 		}
 		
-	case "enter":
+	case keymap.Open:
 		switch m.viewMode {
 		case ViewModeFeeds:
 			if m.selectedFeedIdx < len(m.feeds) {
+				m.clearArticleSource()
 				m.currentFeed = &m.feeds[m.selectedFeedIdx]
 				m.currentView = ArticlesView
 				m.selectedArticleIdx = 0
@@ -160,6 +173,7 @@ func (m *Model) updateFeeds(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 			}
 		case ViewModeTags:
 			if m.selectedTagIdx < len(m.tags) {
+				m.clearArticleSource()
 				m.currentTag = &m.tags[m.selectedTagIdx]
 				m.currentView = ArticlesView
 				// Load feeds for this tag and show articles
@@ -167,62 +181,149 @@ func (m *Model) updateFeeds(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 			}
 		case ViewModeCategories:
 			if m.selectedCategoryIdx < len(m.categories) {
+				m.clearArticleSource()
 				m.currentCategory = &m.categories[m.selectedCategoryIdx]
 				m.currentView = ArticlesView
 				// Load feeds for this category and show articles
 				return m, m.loadFeedsForCategory(m.currentCategory.ID)
 			}
+		case ViewModeSmart:
+			return m, m.openSmartView()
 		}
 		
-	case "s":
+	case keymap.Sync:
 		// Manual sync
 		m.statusMessage = "Syncing from Nostr..."
 		return m, m.syncFromNostr()
+		
+	case keymap.MarkAll:
+		m.statusMessage = "Marking all read..."
+		return m, m.markSelectionRead()
+		
+	case keymap.NewFilter:
+		if m.viewMode == ViewModeSmart {
+			m.filterInputStage = filterInputQuery
+			m.filterInput = ""
+		}
+		
+	case keymap.DeleteFilter:
+		if m.viewMode == ViewModeSmart {
+			return m, m.deleteSelectedFilter()
+		}
+		
+	case keymap.FullContent:
+		if m.viewMode == ViewModeFeeds && m.selectedFeedIdx < len(m.feeds) {
+			m.toggleFeedFullContent(&m.feeds[m.selectedFeedIdx])
+		}
+		
+	case keymap.ArchiveFeed:
+		if m.viewMode == ViewModeFeeds && m.selectedFeedIdx < len(m.feeds) {
+			return m, m.toggleFeedArchive(&m.feeds[m.selectedFeedIdx])
+		}
+		
+	case keymap.Export:
+		m.startArchiveExport()
+		
+	case keymap.ShowPodcasts:
+		return m, m.openPodcasts()
+		
+	case keymap.ShowRelays:
+		// Relay management panel
+		m.currentView = RelaysView
+		m.selectedRelayIdx = 0
+		m.statusMessage = "Checking relays..."
+		return m, m.checkRelays(m.cfg.Nostr.Relays)
+		
+	case keymap.ShowLogs:
+		// Recent log entries
+		m.currentView = LogsView
+		m.logScrollOffset = 0
+		if m.logTicking {
+			return m, nil
+		}
+		m.logTicking = true
+		return m, waitForLogTick()
 	}
 	return m, nil
 }
 
 func (m *Model) updateArticles(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
-	switch msg.String() {
-	case "esc":
+	switch m.action(msg.String()) {
+	case keymap.Back:
 		m.currentView = FeedsView
 		m.articles = []db.FeedItem{} // Clear articles
 		m.selectedArticleIdx = 0
+		m.selected = nil
 		// Reload unread counts when going back to feeds
+		if m.viewMode == ViewModeSmart {
+			return m, tea.Batch(m.loadUnreadCounts(), m.loadSmartViews())
+		}
 		return m, m.loadUnreadCounts()
 		
-	case "up", "k":
+	case keymap.Up:
 		if m.selectedArticleIdx > 0 {
 			m.selectedArticleIdx--
 		}
 		
-	case "down", "j":
+	case keymap.Down:
 		if m.selectedArticleIdx < len(m.articles)-1 {
 			m.selectedArticleIdx++
 		}
 		
-	case "enter":
+	case keymap.Open:
 		if m.selectedArticleIdx < len(m.articles) {
 			m.currentArticle = &m.articles[m.selectedArticleIdx]
 			m.currentView = ReaderView
 			m.articleScrollOffset = 0
 			m.selectedImageIdx = 0 // Reset to first image
 			m.selectedVideoIdx = 0 // Reset to first video
-			
-			// Mark as read
-			m.db.MarkItemRead(m.currentArticle.ID, true)
-			m.currentArticle.IsRead = true
+			m.loadFullContent()
+			m.loadVideoMeta()
 			
 			// Extract media from content and article URL
-			m.currentMedia = m.renderer.ExtractMedia(m.currentArticle.Content, m.currentArticle.URL)
+			m.currentMedia = m.extractMedia()
 			
 			// Preload images in background
 			if m.currentMedia != nil && len(m.currentMedia.Images) > 0 {
-				m.imgCache.PreloadArticleImages(m.currentMedia.Images)
+				m.imgCache.PreloadArticleImages(m.currentArticle.ID, m.currentMedia.Images)
 			}
+			
+			m.readerStack = nil
+			m.loadRendered()
+			m.statusMessage = ""
+			m.resumeReadingPosition()
+			return m, tea.Batch(m.articleOpened(), m.loadSocial())
+		}
+		
+	case keymap.MarkToggle:
+		if m.selectedArticleIdx < len(m.articles) {
+			m.toggleItemRead(&m.articles[m.selectedArticleIdx])
+		}
+		
+	case keymap.MarkAbove:
+		m.markAboveRead()
+		
+	case keymap.ToggleStar:
+		if m.selectedArticleIdx < len(m.articles) {
+			return m, m.toggleItemStar(&m.articles[m.selectedArticleIdx])
 		}
 		
-	case "r":
+	case keymap.MarkAll:
+		m.statusMessage = "Marking all read..."
+		return m, m.markArticlesRead()
+		
+	case keymap.Select:
+		m.toggleSelected()
+		
+	case keymap.Export:
+		m.exportArticles()
+		
+	case keymap.Enqueue:
+		if m.selectedArticleIdx < len(m.articles) {
+			return m, m.enqueueEpisode(&m.articles[m.selectedArticleIdx])
+		}
+		
+	case keymap.Refresh:
 		// Refresh - fetch articles again
 		if m.currentFeed != nil {
 			m.loading = true
@@ -234,20 +335,28 @@ func (m *Model) updateArticles(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 }
 
 func (m *Model) updateReader(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
-	switch msg.String() {
-	case "esc":
+	if m.zapInputActive {
+		return m.updateZapInput(msg)
+	}
+	
+	switch m.action(msg.String()) {
+	case keymap.Back:
 		// ESC always goes back to articles (closes image if open)
 		if m.inlineImageData != "" {
 			termimg.ClearAll() // Clear terminal images
 			m.inlineImageData = ""
 		}
+		m.saveReadingPosition()
+		if m.popReader() {
+			return m, tea.ClearScreen
+		}
 		m.currentView = ArticlesView
 		m.currentArticle = nil
 		m.articleScrollOffset = 0
 		return m, tea.ClearScreen
 		
-	case "q":
-		// q just closes image if showing, otherwise does nothing
+	case keymap.CloseImage:
+		// Closes the image if showing, otherwise does nothing
 		if m.inlineImageData != "" {
 			termimg.ClearAll() // Clear terminal images
 			m.inlineImageData = ""
@@ -255,28 +364,74 @@ func (m *Model) updateReader(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 			return m, tea.ClearScreen
 		}
 		
-	case "up", "k":
+	case keymap.Up:
 		// Don't scroll if showing image
 		if m.inlineImageData == "" && m.articleScrollOffset > 0 {
 			m.articleScrollOffset--
 		}
 		
-	case "down", "j":
+	case keymap.Down:
 		// Don't scroll if showing image
 		if m.inlineImageData == "" {
 			m.articleScrollOffset++
+			m.checkScrollEnd()
 		}
 		
-	case "pageup":
+	case keymap.PageUp:
 		m.articleScrollOffset -= m.height - 10
 		if m.articleScrollOffset < 0 {
 			m.articleScrollOffset = 0
 		}
 		
-	case "pagedown", " ":
+	case keymap.PageDown:
 		m.articleScrollOffset += m.height - 10
+		m.checkScrollEnd()
+		
+	case keymap.MarkToggle:
+		// Followed links are not stored, so they have no ID
+		if m.currentArticle != nil && m.currentArticle.ID != "" {
+			m.toggleItemRead(m.currentArticle)
+		}
+		
+	case keymap.ToggleStar:
+		if m.currentArticle != nil && m.currentArticle.ID != "" {
+			return m, m.toggleItemStar(m.currentArticle)
+		}
+		
+	case keymap.Like:
+		return m, m.like()
+		
+	case keymap.Reply:
+		m.startReply()
+		return m, tea.ClearScreen
+		
+	case keymap.Thread:
+		m.toggleThread()
+		
+	case keymap.Zap:
+		m.startZap()
+		
+	case keymap.ShowLinks:
+		m.openLinkPicker()
+		return m, tea.ClearScreen
+		
+	case keymap.ShowGallery:
+		return m, m.openGallery()
 		
-	case "o":
+	case keymap.FullArticle:
+		return m, m.toggleFullArticle()
+		
+	case keymap.Export:
+		m.exportCurrentArticle()
+		
+	case keymap.Enqueue:
+		return m, m.enqueueEpisode(m.currentArticle)
+		
+	case keymap.Share:
+		m.startCompose()
+		return m, tea.ClearScreen
+		
+	case keymap.OpenBrowser:
 		// Open in browser
 		if m.currentArticle != nil && m.currentArticle.URL != "" {
 			// Try to open with xdg-open
@@ -284,7 +439,7 @@ func (m *Model) updateReader(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 			m.statusMessage = "Opened in browser"
 		}
 		
-	case "i":
+	case keymap.ViewImage:
 		// Open image in external viewer
 		if m.currentMedia == nil {
 			m.statusMessage = "No media found in article"
@@ -310,17 +465,20 @@ func (m *Model) updateReader(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 			}
 		}
 		
-		m.statusMessage = fmt.Sprintf("Opening image: %s", cachePath)
-		m.openImage(cachePath)
+		if err := m.openImage(cachePath); err != nil {
+			m.statusMessage = fmt.Sprintf("Failed to open image: %s", err)
+			return m, nil
+		}
 		
 		if len(m.currentMedia.Images) > 1 {
-			m.statusMessage = fmt.Sprintf("Viewing image %d of %d (← → to navigate, q to close)", 
-				m.selectedImageIdx+1, len(m.currentMedia.Images))
+			m.statusMessage = fmt.Sprintf("Viewing image %d of %d (%s to navigate, %s to close)", 
+				m.selectedImageIdx+1, len(m.currentMedia.Images), m.navKeys(keymap.PrevImage, keymap.NextImage),
+				m.keys.Keys(keymap.Reader, keymap.CloseImage))
 		} else {
-			m.statusMessage = "Viewing image (press 'q' to close)"
+			m.statusMessage = fmt.Sprintf("Viewing image (press '%s' to close)", m.keys.Keys(keymap.Reader, keymap.CloseImage))
 		}
 		
-	case "left", "h":
+	case keymap.PrevImage:
 		// Previous image
 		if m.currentMedia != nil && len(m.currentMedia.Images) > 1 {
 			if m.selectedImageIdx > 0 {
@@ -339,12 +497,15 @@ func (m *Model) updateReader(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 					return m, nil
 				}
 			}
-			m.openImage(cachePath)
-			m.statusMessage = fmt.Sprintf("Viewing image %d of %d (← → to navigate)", 
-				m.selectedImageIdx+1, len(m.currentMedia.Images))
+			if err := m.openImage(cachePath); err != nil {
+				m.statusMessage = fmt.Sprintf("Failed to open image: %s", err)
+				return m, nil
+			}
+			m.statusMessage = fmt.Sprintf("Viewing image %d of %d (%s to navigate)", 
+				m.selectedImageIdx+1, len(m.currentMedia.Images), m.navKeys(keymap.PrevImage, keymap.NextImage))
 		}
 		
-	case "right", "l":
+	case keymap.NextImage:
 		// Next image
 		if m.currentMedia != nil && len(m.currentMedia.Images) > 1 {
 			if m.selectedImageIdx < len(m.currentMedia.Images)-1 {
@@ -363,19 +524,25 @@ func (m *Model) updateReader(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 					return m, nil
 				}
 			}
-			m.openImage(cachePath)
-			m.statusMessage = fmt.Sprintf("Viewing image %d of %d (← → to navigate)", 
-				m.selectedImageIdx+1, len(m.currentMedia.Images))
+			if err := m.openImage(cachePath); err != nil {
+				m.statusMessage = fmt.Sprintf("Failed to open image: %s", err)
+				return m, nil
+			}
+			m.statusMessage = fmt.Sprintf("Viewing image %d of %d (%s to navigate)", 
+				m.selectedImageIdx+1, len(m.currentMedia.Images), m.navKeys(keymap.PrevImage, keymap.NextImage))
 		}
 		
-	case "I":
+	case keymap.ExternalImage:
 		// Force external image viewer
 		if m.currentMedia != nil && len(m.currentMedia.Images) > 0 {
-			m.openImage(m.currentMedia.Images[0])
+			if err := m.openImage(m.currentMedia.Images[0]); err != nil {
+				m.statusMessage = fmt.Sprintf("Failed to open image: %s", err)
+				return m, nil
+			}
 			m.statusMessage = "Opened image in external viewer"
 		}
 		
-	case "v":
+	case keymap.PlayVideo:
 		// Open video in external player
 		if m.currentMedia == nil {
 			m.statusMessage = "No media found in article"
@@ -387,17 +554,14 @@ func (m *Model) updateReader(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		}
 		
 		videoURL := m.currentMedia.Videos[m.selectedVideoIdx].URL
-		m.statusMessage = fmt.Sprintf("Opening video...")
-		m.openVideo(videoURL)
-		
+		status := "Playing video"
 		if len(m.currentMedia.Videos) > 1 {
-			m.statusMessage = fmt.Sprintf("Playing video %d of %d (Shift+← Shift+→ to navigate)", 
-				m.selectedVideoIdx+1, len(m.currentMedia.Videos))
-		} else {
-			m.statusMessage = "Playing video"
+			status = fmt.Sprintf("Playing video %d of %d (%s to navigate)", 
+				m.selectedVideoIdx+1, len(m.currentMedia.Videos), m.navKeys(keymap.PrevVideo, keymap.NextVideo))
 		}
+		return m, m.openVideo(videoURL, status)
 		
-	case "shift+left", "H":
+	case keymap.PrevVideo:
 		// Previous video
 		if m.currentMedia != nil && len(m.currentMedia.Videos) > 1 {
 			if m.selectedVideoIdx > 0 {
@@ -407,12 +571,11 @@ func (m *Model) updateReader(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 			}
 			
 			videoURL := m.currentMedia.Videos[m.selectedVideoIdx].URL
-			m.openVideo(videoURL)
-			m.statusMessage = fmt.Sprintf("Playing video %d of %d (Shift+← Shift+→ to navigate)", 
-				m.selectedVideoIdx+1, len(m.currentMedia.Videos))
+			return m, m.openVideo(videoURL, fmt.Sprintf("Playing video %d of %d (%s to navigate)", 
+				m.selectedVideoIdx+1, len(m.currentMedia.Videos), m.navKeys(keymap.PrevVideo, keymap.NextVideo)))
 		}
 		
-	case "shift+right", "L":
+	case keymap.NextVideo:
 		// Next video
 		if m.currentMedia != nil && len(m.currentMedia.Videos) > 1 {
 			if m.selectedVideoIdx < len(m.currentMedia.Videos)-1 {
@@ -422,17 +585,24 @@ func (m *Model) updateReader(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 			}
 			
 			videoURL := m.currentMedia.Videos[m.selectedVideoIdx].URL
-			m.openVideo(videoURL)
-			m.statusMessage = fmt.Sprintf("Playing video %d of %d (Shift+← Shift+→ to navigate)", 
-				m.selectedVideoIdx+1, len(m.currentMedia.Videos))
+			return m, m.openVideo(videoURL, fmt.Sprintf("Playing video %d of %d (%s to navigate)", 
+				m.selectedVideoIdx+1, len(m.currentMedia.Videos), m.navKeys(keymap.PrevVideo, keymap.NextVideo)))
 		}
 	}
 	return m, nil
 }
 
+// newNostrClient creates a client using the configured read and write relays
+func (m *Model) newNostrClient() *nostrClient.Client {
+	client := nostrClient.NewClient(m.cfg.Nostr.Relays)
+	client.SetRelays(m.cfg.Nostr.ReadRelays(), m.cfg.Nostr.WriteRelays())
+	client.SetAuthPolicies(m.authPolicies())
+	return client
+}
+
 func (m *Model) initNostrClient() tea.Cmd {
 	return func() tea.Msg {
-		client := nostrClient.NewClient(m.cfg.Nostr.Relays)
+		client := m.newNostrClient()
 		
 		// Try Pleb_Signer first
 		if m.cfg.Nostr.PlebSigner.Enabled {
@@ -466,7 +636,7 @@ func (m *Model) initNostrClient() tea.Cmd {
 
 func (m *Model) connectPlebSigner() tea.Cmd {
 	return func() tea.Msg {
-		client := nostrClient.NewClient(m.cfg.Nostr.Relays)
+		client := m.newNostrClient()
 		
 		if err := client.SetPlebSigner(); err != nil {
 			return authErrorMsg("Failed to connect: " + err.Error())
@@ -491,7 +661,7 @@ func (m *Model) connectPlebSigner() tea.Cmd {
 
 func (m *Model) connectRemoteSigner(bunkerURL string) tea.Cmd {
 	return func() tea.Msg {
-		client := nostrClient.NewClient(m.cfg.Nostr.Relays)
+		client := m.newNostrClient()
 		
 		if err := client.SetRemoteSigner(bunkerURL, ""); err != nil {
 			return authErrorMsg("Failed to connect: " + err.Error())
@@ -516,7 +686,7 @@ func (m *Model) connectRemoteSigner(bunkerURL string) tea.Cmd {
 
 func (m *Model) connectPrivateKey(nsec string) tea.Cmd {
 	return func() tea.Msg {
-		client := nostrClient.NewClient(m.cfg.Nostr.Relays)
+		client := m.newNostrClient()
 		
 		if err := client.SetPrivateKeySigner(nsec); err != nil {
 			return authErrorMsg("Invalid private key: " + err.Error())
@@ -638,9 +808,8 @@ func (m *Model) syncFromNostr() tea.Cmd {
 			return syncCompleteMsg{0, 0, 0, nil}
 		}
 		
-		// Debug: Log what we received
-		fmt.Fprintf(os.Stderr, "DEBUG: Sync received - RSS: %d, Nostr: %d, Tags: %d feeds, Categories: %d feeds\n",
-			len(subs.RSS), len(subs.Nostr), len(subs.Tags), len(subs.Categories))
+		slog.Debug("sync received subscriptions",
+			"rss", len(subs.RSS), "nostr", len(subs.Nostr), "tagged_feeds", len(subs.Tags), "categorized_feeds", len(subs.Categories))
 
 		feedsAdded := 0
 
@@ -657,7 +826,7 @@ func (m *Model) syncFromNostr() tea.Cmd {
 				ID:          fmt.Sprintf("feed_%d", time.Now().UnixNano()),
 				Title:       url, // Temporary - will be updated below
 				URL:         url,
-				Type:        "rss",
+				Type:        rssFeedType(url),
 				Description: "",
 				CategoryID:  "synced",
 				CreatedAt:   time.Now(),
@@ -754,14 +923,14 @@ func (m *Model) syncFromNostr() tea.Cmd {
 							Icon:  catInfo.Icon,
 						}
 						if err := m.db.CreateCategory(category); err != nil {
-							fmt.Printf("Warning: failed to create category %s: %v\n", catInfo.Name, err)
+							slog.Warn("failed to create category", "category", catInfo.Name, "err", err)
 							continue
 						}
 					}
 					// Update feed's category
 					feed.CategoryID = category.ID
 					if err := m.db.UpdateFeed(feed); err != nil {
-						fmt.Printf("Warning: failed to update feed category: %v\n", err)
+						slog.Warn("failed to update feed category", "feed", feed.URL, "err", err)
 					} else {
 						categoriesImported++
 					}
@@ -774,7 +943,7 @@ func (m *Model) syncFromNostr() tea.Cmd {
 		if err != nil {
 			// Don't fail the whole sync if read status fails
 			// Just log and continue
-			fmt.Printf("Warning: failed to fetch read status: %v\n", err)
+			slog.Warn("failed to fetch read status", "err", err)
 		} else if readStatus != nil && len(readStatus.ItemGuids) > 0 {
 			// Mark items as read in local DB
 			for _, guid := range readStatus.ItemGuids {
@@ -786,16 +955,26 @@ func (m *Model) syncFromNostr() tea.Cmd {
 			}
 		}
 
+		// 6. Starred items follow the NIP-51 bookmark list
+		if err := m.syncBookmarks(); err != nil {
+			slog.Warn("failed to sync bookmarks", "err", err)
+		}
+
 		return syncCompleteMsg{feedsAdded, tagsImported, categoriesImported, nil}
 	}
 }
 
 // updateRSSFeedMetadata fetches RSS feed metadata and updates the feed title
 func (m *Model) updateRSSFeedMetadata(feed *db.Feed) {
+feedURL, err := resolveFeedURL(feed)
+if err != nil {
+slog.Warn("failed to resolve feed", "feed", feed.URL, "err", err)
+return
+}
 parser := gofeed.NewParser()
-rssFeed, err := parser.ParseURL(feed.URL)
+rssFeed, err := parser.ParseURL(feedURL)
 if err != nil {
-fmt.Printf("Warning: Failed to fetch RSS metadata for %s: %v\n", feed.URL, err)
+slog.Warn("failed to fetch RSS metadata", "feed", feed.URL, "err", err)
 return
 }
 
@@ -807,7 +986,7 @@ feed.Description = rssFeed.Description
 
 // Save to database
 if err := m.db.UpdateFeed(feed); err != nil {
-fmt.Printf("Warning: Failed to update feed metadata: %v\n", err)
+slog.Warn("failed to update feed metadata", "feed", feed.URL, "err", err)
 }
 }
 
@@ -856,7 +1035,7 @@ feed.Description = profile.About
 
 // Save to database
 if err := m.db.UpdateFeed(feed); err != nil {
-fmt.Printf("Warning: Failed to update Nostr feed metadata: %v\n", err)
+slog.Warn("failed to update Nostr feed metadata", "feed", feed.NPUB, "err", err)
 }
 }
 
@@ -873,18 +1052,27 @@ type inlineImageMsg struct {
 	imageData string
 	err       error
 }
-// fetchArticles fetches articles for a feed (RSS or Nostr)
+
+type videoOpenedMsg struct {
+	status string // Shown once the video plays
+	err    error
+}
+
+// fetchArticles fetches articles for a feed (RSS, YouTube or Nostr)
 func (m *Model) fetchArticles(feed *db.Feed) tea.Cmd {
 return func() tea.Msg {
 var articles []*db.FeedItem
 var err error
 
 // Fetch based on feed type
-if feed.Type == "rss" {
+switch fetchType(feed) {
+case "rss":
 articles, err = m.fetcher.FetchRSSArticles(feed.URL, feed.ID)
-} else if feed.Type == "nostr" {
+case "youtube":
+articles, err = m.fetcher.FetchYouTubeVideos(feed.URL, feed.ID)
+case "nostr":
 articles, err = m.fetcher.FetchNostrArticles(feed.NPUB, feed.ID)
-} else {
+default:
 return articlesFetchedMsg{feed.ID, nil, fmt.Errorf("unknown feed type: %s", feed.Type)}
 }
 
@@ -901,7 +1089,7 @@ for _, article := range articles {
 		// Preload images for this article in background
 		media := m.renderer.ExtractMedia(article.Content, article.URL)
 		if media != nil && len(media.Images) > 0 {
-			m.imgCache.PreloadArticleImages(media.Images)
+			m.imgCache.PreloadArticleImages(article.ID, media.Images)
 		}
 	}
 }
@@ -940,96 +1128,28 @@ func openInBrowser(url string) {
 exec.Command("xdg-open", url).Start()
 }
 
-func (m *Model) openImage(url string) {
-// Kill previous image viewer if still running
-if m.imageViewerPID > 0 {
-// Try to kill the previous viewer
-if process, err := os.FindProcess(m.imageViewerPID); err == nil {
-process.Kill()
-}
-m.imageViewerPID = 0
-}
-
-viewers := []struct {
-cmd  string
-args []string
-}{
-{"sxiv", []string{"-b", "-g", "800x600", url}},
-{"feh", []string{"--scale-down", "--auto-zoom", "--borderless", "--geometry", "800x600", url}},
-{"imv-wayland", []string{url}},
-{"imv-x11", []string{url}},
-{"eog", []string{url}},
-{"eom", []string{url}},
-{"xdg-open", []string{url}},
-}
-
-for _, viewer := range viewers {
-cmd := exec.Command(viewer.cmd, viewer.args...)
-if err := cmd.Start(); err == nil {
-m.imageViewerPID = cmd.Process.Pid
-return
-}
-}
-}
-
-func (m *Model) openVideo(url string) {
-// Kill previous video player if still running
-if m.videoPlayerPID > 0 {
-if process, err := os.FindProcess(m.videoPlayerPID); err == nil {
-process.Kill()
-}
-m.videoPlayerPID = 0
-}
-
-players := []struct {
-cmd  string
-args []string
-}{
-{"mpv", []string{"--geometry=800x600", url}},
-{"vlc", []string{"--width=800", "--height=600", url}},
-{"mplayer", []string{url}},
-{"xdg-open", []string{url}},
+// openImage shows an image file in the external viewer
+func (m *Model) openImage(path string) error {
+return m.media.OpenImage(path)
 }
 
-for _, player := range players {
-cmd := exec.Command(player.cmd, player.args...)
-if err := cmd.Start(); err == nil {
-m.videoPlayerPID = cmd.Process.Pid
-return
-}
+// openVideo plays a video in the external player, in the same window
+// as the previous one when the player is mpv. Handing a video to mpv can
+// take seconds, so it is done from a command; status is shown once it plays.
+func (m *Model) openVideo(url, status string) tea.Cmd {
+m.statusMessage = "Starting video..."
+return func() tea.Msg {
+return videoOpenedMsg{status: status, err: m.media.OpenVideo(url)}
 }
 }
 
-// showInlineImage fetches and displays an image inline in the terminal
+// showInlineImage fetches and displays an image inline in the terminal,
+// as large as the terminal allows
 func (m *Model) showInlineImage(imageURL string) tea.Cmd {
+width, height := max(m.width-4, 1), max(m.height-8, 1)
 return func() tea.Msg {
-// Check cache first
-var imageData string
-var err error
-
-if m.imgCache.IsCached(imageURL) {
-// Use cached version
-cachePath, cacheErr := m.imgCache.GetCached(imageURL)
-if cacheErr == nil {
-imageData, err = m.renderer.RenderImageInlineFromFile(cachePath, 80, 24)
-} else {
-err = cacheErr
-}
-} else {
-// Download and cache
-cachePath, dlErr := m.imgCache.Download(imageURL)
-if dlErr == nil {
-imageData, err = m.renderer.RenderImageInlineFromFile(cachePath, 80, 24)
-} else {
-err = dlErr
-}
-}
-
-if err != nil {
-return inlineImageMsg{"", err}
-}
-
-return inlineImageMsg{imageData, nil}
+imageData, err := m.images.Render(imageURL, width, height)
+return inlineImageMsg{imageData, err}
 }
 }
 
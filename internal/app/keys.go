@@ -0,0 +1,120 @@
+package app
+
+import (
+	"fmt"
+	"log/slog"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/plebone/nostrfeedz-cli/internal/config"
+	"github.com/plebone/nostrfeedz-cli/internal/keymap"
+	"github.com/plebone/nostrfeedz-cli/pkg/styles"
+)
+
+// loadKeymap builds the keymap from config, falling back to the defaults
+// when the keys section is invalid
+func loadKeymap(cfg *config.Config) (*keymap.Keymap, []keymap.Conflict) {
+	keys, conflicts, err := keymap.New(cfg.Keys.Preset, cfg.Keys.Overrides())
+	if err != nil {
+		slog.Warn("invalid key configuration, using defaults", "err", err)
+		return keymap.Default(), nil
+	}
+	for _, c := range conflicts {
+		slog.Warn("key binding conflict", "context", c.Context, "key", c.Key, "actions", strings.Join(c.Actions, ","))
+	}
+	return keys, conflicts
+}
+
+// keyContext returns the keymap context of the current view
+func (m *Model) keyContext() keymap.Context {
+	switch m.currentView {
+	case FeedsView:
+		return keymap.Feeds
+	case ArticlesView:
+		return keymap.Articles
+	case ReaderView:
+		return keymap.Reader
+	case RelaysView:
+		return keymap.Relays
+	case LogsView:
+		return keymap.Logs
+	case ComposeView:
+		return keymap.Compose
+	case LinksView:
+		return keymap.Links
+	case GalleryView:
+		return keymap.Gallery
+	case PodcastView:
+		return keymap.Podcasts
+	}
+	return keymap.Global
+}
+
+// action resolves a key press in the current view
+func (m *Model) action(key string) keymap.Action {
+	return m.keys.Action(m.keyContext(), key)
+}
+
+// hint renders a status bar entry using the action's configured keys
+func (m *Model) hint(action keymap.Action, label string) string {
+	return styles.RenderKeyValue(m.keys.Keys(m.keyContext(), action), label)
+}
+
+// navKeys describes a previous/next pair of actions for status messages
+func (m *Model) navKeys(prev, next keymap.Action) string {
+	return m.keys.Keys(m.keyContext(), prev) + " " + m.keys.Keys(m.keyContext(), next)
+}
+
+// renderHelp renders the help overlay for the current view
+func (m *Model) renderHelp() string {
+	var s strings.Builder
+
+	ctx := m.keyContext()
+	s.WriteString(styles.HeaderStyle.Render("⌨  Keys: " + string(ctx)))
+	s.WriteString("\n\n")
+
+	sections := []keymap.Context{ctx}
+	if ctx != keymap.Global {
+		sections = append(sections, keymap.Global)
+	}
+
+	for _, section := range sections {
+		if section == keymap.Global && ctx != keymap.Global {
+			s.WriteString("\n")
+			s.WriteString(styles.MutedStyle.Render("Everywhere"))
+			s.WriteString("\n")
+		}
+		for _, b := range m.keys.Bindings(section) {
+			if len(b.Keys) == 0 {
+				continue
+			}
+			keys := m.keys.Keys(section, b.Action)
+			s.WriteString(fmt.Sprintf("%s  %s\n", styles.KeyStyle.Render(fmt.Sprintf("%-14s", keys)), b.Help))
+		}
+	}
+
+	var conflicts []keymap.Conflict
+	for _, c := range m.keyConflicts {
+		if c.Context == ctx {
+			conflicts = append(conflicts, c)
+		}
+	}
+	if len(conflicts) > 0 {
+		s.WriteString("\n")
+		s.WriteString(styles.ErrorStyle.Render("Conflicts"))
+		s.WriteString("\n")
+		for _, c := range conflicts {
+			s.WriteString(styles.MutedStyle.Render(c.Error()))
+			s.WriteString("\n")
+		}
+	}
+
+	s.WriteString("\n")
+	s.WriteString(styles.MutedStyle.Render("Rebind keys in the keys section of config.yaml • any key closes"))
+
+	return lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(styles.AccentColor).
+		Padding(1, 2).
+		Render(s.String())
+}
@@ -0,0 +1,280 @@
+package app
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"net/url"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/mattn/go-runewidth"
+	"github.com/plebone/nostrfeedz-cli/internal/db"
+	"github.com/plebone/nostrfeedz-cli/internal/feed"
+	"github.com/plebone/nostrfeedz-cli/internal/keymap"
+	nostrClient "github.com/plebone/nostrfeedz-cli/internal/nostr"
+	"github.com/plebone/nostrfeedz-cli/pkg/styles"
+)
+
+type linkOpenedMsg struct {
+	article *feed.Article
+	err     error
+}
+
+type feedSubscribedMsg struct {
+	title string
+	err   error
+}
+
+// readerState is an article to return to after reading a followed link
+type readerState struct {
+	article *db.FeedItem
+	scroll  int
+	media   *feed.MediaLinks
+	social  *db.ArticleSocial
+}
+
+// openLinkPicker lists the numbered links of the open article
+func (m *Model) openLinkPicker() {
+	if m.currentArticle == nil {
+		return
+	}
+	content := m.articleContent()
+	links, err := m.renderer.ExtractLinks(content, isHTMLContent(content), m.currentArticle.URL)
+	if err != nil {
+		m.statusMessage = fmt.Sprintf("Failed to read links: %s", err)
+		return
+	}
+	if len(links) == 0 {
+		m.statusMessage = "No links in this article"
+		return
+	}
+	m.links = links
+	m.selectedLinkIdx = 0
+	m.currentView = LinksView
+	m.statusMessage = ""
+}
+
+func (m *Model) updateLinks(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	// Digits jump to a link by its number
+	if key := msg.String(); len(key) == 1 && key[0] >= '1' && key[0] <= '9' {
+		if n := int(key[0] - '0'); n <= len(m.links) {
+			m.selectedLinkIdx = n - 1
+		}
+		return m, nil
+	}
+
+	switch m.action(msg.String()) {
+	case keymap.Up:
+		if m.selectedLinkIdx > 0 {
+			m.selectedLinkIdx--
+		}
+	case keymap.Down:
+		if m.selectedLinkIdx < len(m.links)-1 {
+			m.selectedLinkIdx++
+		}
+	case keymap.Open:
+		m.currentView = ReaderView
+		return m, tea.Batch(tea.ClearScreen, m.followLink(m.links[m.selectedLinkIdx]))
+	case keymap.OpenBrowser:
+		link := m.links[m.selectedLinkIdx]
+		openInBrowser(link.URL)
+		m.statusMessage = "Opened " + link.URL
+	case keymap.Subscribe:
+		return m, m.subscribeLink(m.links[m.selectedLinkIdx])
+	case keymap.Back:
+		m.currentView = ReaderView
+		m.statusMessage = ""
+		return m, tea.ClearScreen
+	}
+	return m, nil
+}
+
+// followLink fetches a link and extracts its main content for the reader
+func (m *Model) followLink(link feed.Link) tea.Cmd {
+	if u, err := url.Parse(link.URL); err != nil || (u.Scheme != "http" && u.Scheme != "https") {
+		// nostr:, mailto: and the like are left to the system handler
+		openInBrowser(link.URL)
+		m.statusMessage = "Opened " + link.URL
+		return nil
+	}
+
+	m.statusMessage = "Fetching " + link.URL + "..."
+	return func() tea.Msg {
+		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+		defer cancel()
+		article, err := feed.FetchArticle(ctx, link.URL)
+		return linkOpenedMsg{article: article, err: err}
+	}
+}
+
+// linkOpened shows a followed link in the reader, remembering the article
+// it came from so Back returns to it
+func (m *Model) linkOpened(msg linkOpenedMsg) tea.Cmd {
+	if msg.err != nil {
+		slog.Warn("failed to open link", "err", msg.err)
+		m.statusMessage = fmt.Sprintf("Failed to open link: %s (press %s in the link list to open it externally)",
+			msg.err, m.keys.Keys(keymap.Links, keymap.OpenBrowser))
+		return nil
+	}
+	if m.currentView != ReaderView || m.currentArticle == nil {
+		return nil
+	}
+
+	m.saveReadingPosition()
+	m.readerStack = append(m.readerStack, readerState{
+		article: m.currentArticle,
+		scroll:  m.articleScrollOffset,
+		media:   m.currentMedia,
+		social:  m.social,
+	})
+
+	author := msg.article.Byline
+	if u, err := url.Parse(msg.article.URL); err == nil && author == "" {
+		author = u.Host
+	}
+	// Not stored, so it has no ID
+	m.showInReader(&db.FeedItem{
+		Title:   msg.article.Title,
+		Content: msg.article.Content,
+		URL:     msg.article.URL,
+		Author:  author,
+	}, 0)
+	m.social = nil
+	m.statusMessage = fmt.Sprintf("Reading a linked page (%s to go back)", m.keys.Keys(keymap.Reader, keymap.Back))
+	return tea.ClearScreen
+}
+
+// popReader returns to the article a followed link was opened from
+func (m *Model) popReader() bool {
+	if len(m.readerStack) == 0 {
+		return false
+	}
+	prev := m.readerStack[len(m.readerStack)-1]
+	m.readerStack = m.readerStack[:len(m.readerStack)-1]
+	m.showInReader(prev.article, prev.scroll)
+	m.currentMedia = prev.media
+	m.social = prev.social
+	m.statusMessage = ""
+	return true
+}
+
+// showInReader replaces the article in the reader
+func (m *Model) showInReader(item *db.FeedItem, scroll int) {
+	m.currentArticle = item
+	m.articleScrollOffset = scroll
+	m.threadOpen = false
+	m.selectedImageIdx = 0
+	m.selectedVideoIdx = 0
+	m.currentMedia = m.extractMedia()
+	m.loadRendered()
+}
+
+// subscribeLink subscribes to the feed a link points to or advertises and
+// adds it to the published subscription list
+func (m *Model) subscribeLink(link feed.Link) tea.Cmd {
+	m.statusMessage = "Looking for a feed at " + link.URL + "..."
+	return func() tea.Msg {
+		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+		defer cancel()
+		found, err := feed.DiscoverFeed(ctx, link.URL)
+		if err != nil {
+			return feedSubscribedMsg{err: err}
+		}
+		if existing, err := m.db.GetFeedByURL(found.URL); err == nil && existing != nil {
+			return feedSubscribedMsg{err: fmt.Errorf("already subscribed to %s", existing.Title)}
+		}
+
+		title := found.Title
+		if title == "" {
+			title = found.URL
+		}
+		if err := m.db.CreateFeed(&db.Feed{
+			ID:        fmt.Sprintf("feed_%d", time.Now().UnixNano()),
+			Type:      rssFeedType(found.URL),
+			URL:       found.URL,
+			Title:     title,
+			CreatedAt: time.Now(),
+		}); err != nil {
+			return feedSubscribedMsg{err: err}
+		}
+
+		if m.nostr != nil {
+			if err := m.publishSubscription(found.URL); err != nil {
+				slog.Warn("failed to publish subscription list", "err", err)
+				return feedSubscribedMsg{title: title, err: fmt.Errorf("subscribed locally, but publishing failed: %w", err)}
+			}
+		}
+		return feedSubscribedMsg{title: title}
+	}
+}
+
+// publishSubscription adds an RSS feed to the latest published subscription
+// list. Nothing is published unless a relay answered the fetch, so an
+// unreachable list is never replaced by one holding only this feed.
+func (m *Model) publishSubscription(feedURL string) error {
+	list, err := m.nostr.FetchSubscriptions(m.nostr.GetPublicKey())
+	if err != nil {
+		return fmt.Errorf("failed to fetch subscription list: %w", err)
+	}
+	if list == nil {
+		// A relay answered without one, so this starts the list
+		list = &nostrClient.SubscriptionList{}
+	}
+	for _, u := range list.RSS {
+		if u == feedURL {
+			return nil
+		}
+	}
+	list.RSS = append(list.RSS, feedURL)
+
+	deleted := list.Deleted[:0]
+	for _, u := range list.Deleted {
+		if u != feedURL {
+			deleted = append(deleted, u)
+		}
+	}
+	list.Deleted = deleted
+	list.LastUpdated = time.Now().Unix()
+	return m.nostr.PublishSubscriptions(list)
+}
+
+func (m *Model) renderLinks() string {
+	var s strings.Builder
+	s.WriteString(styles.HeaderStyle.Render("🔗 Links in " + m.currentArticle.Title))
+	s.WriteString("\n\n")
+
+	// Keep the selection on screen
+	maxVisible := max((m.height-8)/2, 1)
+	start := max(m.selectedLinkIdx-maxVisible/2, 0)
+	end := min(start+maxVisible, len(m.links))
+
+	for i := start; i < end; i++ {
+		link := m.links[i]
+		text := link.Text
+		text = runewidth.Truncate(text, 70, "...")
+		line := fmt.Sprintf("[%d] %s", link.Number, text)
+		if i == m.selectedLinkIdx {
+			s.WriteString(styles.SelectedStyle.Render("▸ " + line))
+		} else {
+			s.WriteString(styles.FeedItemStyle.Render("  " + line))
+		}
+		s.WriteString("\n")
+		s.WriteString(styles.MutedStyle.Render("     " + link.URL))
+		s.WriteString("\n")
+	}
+
+	s.WriteString("\n")
+	s.WriteString(styles.StatusBarStyle.Render(
+		m.hint(keymap.Down, "select") + " • " +
+			styles.RenderKeyValue("1-9", "jump") + " • " +
+			m.hint(keymap.Open, "read here") + " • " +
+			m.hint(keymap.OpenBrowser, "browser") + " • " +
+			m.hint(keymap.Subscribe, "subscribe") + " • " +
+			m.hint(keymap.Back, "back")))
+	if m.statusMessage != "" {
+		s.WriteString("\n" + styles.SuccessStyle.Render(m.statusMessage))
+	}
+	return s.String()
+}
@@ -0,0 +1,143 @@
+package app
+
+import (
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/plebone/nostrfeedz-cli/internal/keymap"
+	"github.com/plebone/nostrfeedz-cli/internal/logging"
+	"github.com/plebone/nostrfeedz-cli/pkg/styles"
+)
+
+// logRefreshInterval is how often the log viewer picks up new entries
+const logRefreshInterval = time.Second
+
+type logTickMsg struct{}
+
+// nextLogLevel cycles the log viewer's minimum level
+var nextLogLevel = map[slog.Level]slog.Level{
+	slog.LevelDebug: slog.LevelInfo,
+	slog.LevelInfo:  slog.LevelWarn,
+	slog.LevelWarn:  slog.LevelError,
+	slog.LevelError: slog.LevelDebug,
+}
+
+func (m *Model) updateLogs(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch m.action(msg.String()) {
+	case keymap.Back:
+		m.currentView = FeedsView
+		m.logScrollOffset = 0
+
+	case keymap.Up:
+		m.logScrollOffset++
+
+	case keymap.Down:
+		if m.logScrollOffset > 0 {
+			m.logScrollOffset--
+		}
+
+	case keymap.PageUp:
+		m.logScrollOffset += m.logPageSize()
+
+	case keymap.PageDown:
+		m.logScrollOffset -= m.logPageSize()
+		if m.logScrollOffset < 0 {
+			m.logScrollOffset = 0
+		}
+
+	case keymap.Follow:
+		// Follow the newest entries again
+		m.logScrollOffset = 0
+
+	case keymap.LogLevel:
+		m.logMinLevel = nextLogLevel[m.logMinLevel]
+		m.logScrollOffset = 0
+	}
+	return m, nil
+}
+
+// waitForLogTick refreshes the log viewer while it is open
+func waitForLogTick() tea.Cmd {
+	return tea.Tick(logRefreshInterval, func(time.Time) tea.Msg {
+		return logTickMsg{}
+	})
+}
+
+func (m *Model) logPageSize() int {
+	// Header, blank lines and status bar take about 6 lines
+	if m.height > 10 {
+		return m.height - 6
+	}
+	return 10
+}
+
+func (m *Model) renderLogs() string {
+	var s strings.Builder
+
+	s.WriteString(styles.HeaderStyle.Render("📜 Log"))
+	s.WriteString("\n")
+	s.WriteString(styles.MutedStyle.Render(fmt.Sprintf("Recent entries at %s and above", m.logMinLevel)))
+	s.WriteString("\n\n")
+
+	var entries []logging.Entry
+	for _, e := range logging.Recent(0) {
+		if e.Level >= m.logMinLevel {
+			entries = append(entries, e)
+		}
+	}
+
+	// Scroll offset counts entries back from the newest
+	page := m.logPageSize()
+	if m.logScrollOffset > len(entries)-page {
+		m.logScrollOffset = max(len(entries)-page, 0)
+	}
+	end := len(entries) - m.logScrollOffset
+	start := max(end-page, 0)
+
+	if len(entries) == 0 {
+		s.WriteString(styles.MutedStyle.Render("No log entries yet."))
+		s.WriteString("\n")
+	}
+
+	for _, e := range entries[start:end] {
+		level := fmt.Sprintf("%-5s", e.Level)
+		switch {
+		case e.Level >= slog.LevelError:
+			level = styles.ErrorStyle.Render(level)
+		case e.Level >= slog.LevelWarn:
+			level = styles.WarningStyle.Render(level)
+		case e.Level >= slog.LevelInfo:
+			level = styles.SuccessStyle.Render(level)
+		default:
+			level = styles.MutedStyle.Render(level)
+		}
+
+		line := e.Message
+		if e.Attrs != "" {
+			line += " " + styles.MutedStyle.Render(e.Attrs)
+		}
+		s.WriteString(styles.MutedStyle.Render(e.Time.Format("15:04:05")) + " " + level + " " + line)
+		s.WriteString("\n")
+	}
+
+	s.WriteString("\n")
+
+	position := "following"
+	if m.logScrollOffset > 0 {
+		position = fmt.Sprintf("%d newer", m.logScrollOffset)
+	}
+	statusBar := styles.StatusBarStyle.Render(
+		m.hint(keymap.Back, "back") + " • " +
+			m.hint(keymap.Up, "older") + " • " +
+			m.hint(keymap.Down, "newer") + " • " +
+			m.hint(keymap.Follow, "follow") + " • " +
+			m.hint(keymap.LogLevel, "level") + " • " +
+			m.hint(keymap.Help, "help") + " • " +
+			styles.MutedStyle.Render(position))
+	s.WriteString(statusBar)
+
+	return s.String()
+}
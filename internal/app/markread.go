@@ -0,0 +1,210 @@
+package app
+
+import (
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/plebone/nostrfeedz-cli/internal/db"
+)
+
+// markReadMode is when an opened article is marked read automatically
+type markReadMode int
+
+const (
+	markReadOnOpen markReadMode = iota
+	markReadAfterDelay
+	markReadOnScrollEnd
+	markReadNever
+)
+
+// parseMarkRead parses reading.mark_read_behavior: "on-open",
+// "after-<duration>" (e.g. "after-10s"), "on-scroll-end" or "never"
+func parseMarkRead(behavior string) (markReadMode, time.Duration) {
+	switch {
+	case behavior == "" || behavior == "on-open":
+		return markReadOnOpen, 0
+	case behavior == "on-scroll-end":
+		return markReadOnScrollEnd, 0
+	case behavior == "never":
+		return markReadNever, 0
+	case strings.HasPrefix(behavior, "after-"):
+		delay, err := time.ParseDuration(strings.TrimPrefix(behavior, "after-"))
+		if err == nil && delay > 0 {
+			return markReadAfterDelay, delay
+		}
+	}
+	slog.Warn("unknown mark_read_behavior, using on-open", "behavior", behavior)
+	return markReadOnOpen, 0
+}
+
+// markReadTimerMsg fires when an article has been open for the configured delay
+type markReadTimerMsg struct {
+	itemID string
+}
+
+type itemsMarkedMsg struct {
+	count int64
+	err   error
+}
+
+// articleOpened applies the mark-read behavior to the article just opened
+func (m *Model) articleOpened() tea.Cmd {
+	switch m.markReadMode {
+	case markReadOnOpen:
+		m.setItemRead(m.currentArticle, true)
+	case markReadAfterDelay:
+		itemID := m.currentArticle.ID
+		return tea.Tick(m.markReadDelay, func(time.Time) tea.Msg {
+			return markReadTimerMsg{itemID: itemID}
+		})
+	case markReadOnScrollEnd:
+		m.checkScrollEnd()
+	}
+	return nil
+}
+
+// markReadTimerFired marks the article read if it is still being read
+func (m *Model) markReadTimerFired(msg markReadTimerMsg) {
+	if m.currentView == ReaderView && m.currentArticle != nil && m.currentArticle.ID == msg.itemID {
+		m.setItemRead(m.currentArticle, true)
+	}
+}
+
+// checkScrollEnd marks the article read once its last line is on screen
+func (m *Model) checkScrollEnd() {
+	if m.markReadMode != markReadOnScrollEnd || m.currentArticle == nil || m.currentArticle.IsRead || m.threadOpen || m.readerLines == 0 {
+		return
+	}
+	if m.articleScrollOffset+m.readerVisibleLines() >= m.readerLines {
+		m.setItemRead(m.currentArticle, true)
+	}
+}
+
+// readerVisibleLines is how many article lines fit on screen
+func (m *Model) readerVisibleLines() int {
+	return m.height - 8 // Leave room for header/footer
+}
+
+// setItemRead updates an item's read flag in the database and in the loaded list
+func (m *Model) setItemRead(item *db.FeedItem, isRead bool) {
+	if err := m.db.MarkItemRead(item.ID, isRead); err != nil {
+		slog.Warn("failed to update read state", "item", item.ID, "err", err)
+		m.statusMessage = fmt.Sprintf("Failed to update read state: %s", err)
+		return
+	}
+	item.IsRead = isRead
+	for i := range m.articles {
+		if m.articles[i].ID == item.ID {
+			m.articles[i].IsRead = isRead
+		}
+	}
+}
+
+// toggleItemRead flips the read flag of an item
+func (m *Model) toggleItemRead(item *db.FeedItem) {
+	m.setItemRead(item, !item.IsRead)
+	if item.IsRead {
+		m.statusMessage = "Marked read"
+	} else {
+		m.statusMessage = "Marked unread"
+	}
+}
+
+// markAboveRead marks every article above the cursor read
+func (m *Model) markAboveRead() {
+	var ids []string
+	for i := 0; i < m.selectedArticleIdx && i < len(m.articles); i++ {
+		if !m.articles[i].IsRead {
+			ids = append(ids, m.articles[i].ID)
+		}
+	}
+	if err := m.db.MarkItemsRead(ids, true); err != nil {
+		slog.Warn("failed to mark articles read", "err", err)
+		m.statusMessage = fmt.Sprintf("Failed to mark articles read: %s", err)
+		return
+	}
+	for i := 0; i < m.selectedArticleIdx && i < len(m.articles); i++ {
+		m.articles[i].IsRead = true
+	}
+	m.statusMessage = fmt.Sprintf("Marked %d articles read", len(ids))
+}
+
+// markArticlesRead marks every article in the open feed, tag, category or view read
+func (m *Model) markArticlesRead() tea.Cmd {
+	for i := range m.articles {
+		m.articles[i].IsRead = true
+	}
+	if m.currentSmart != nil {
+		return m.markFilterRead(m.currentSmart.Filter)
+	}
+
+	var feedIDs []string
+	if m.currentFeed != nil {
+		feedIDs = []string{m.currentFeed.ID}
+	} else {
+		// Tag and category views load their feeds into m.feeds
+		for _, f := range m.feeds {
+			feedIDs = append(feedIDs, f.ID)
+		}
+	}
+	return m.markFeedsRead(func() ([]string, error) { return feedIDs, nil })
+}
+
+// markSelectionRead marks the feed, tag, category or view under the cursor read
+func (m *Model) markSelectionRead() tea.Cmd {
+	switch m.viewMode {
+	case ViewModeFeeds:
+		if m.selectedFeedIdx < len(m.feeds) {
+			feedID := m.feeds[m.selectedFeedIdx].ID
+			return m.markFeedsRead(func() ([]string, error) { return []string{feedID}, nil })
+		}
+	case ViewModeTags:
+		if m.selectedTagIdx < len(m.tags) {
+			tagID := m.tags[m.selectedTagIdx].ID
+			return m.markFeedsRead(func() ([]string, error) {
+				return feedIDs(m.db.GetFeedsByTag(tagID))
+			})
+		}
+	case ViewModeCategories:
+		if m.selectedCategoryIdx < len(m.categories) {
+			categoryID := m.categories[m.selectedCategoryIdx].ID
+			return m.markFeedsRead(func() ([]string, error) {
+				if categoryID == "uncategorized" {
+					return feedIDs(m.db.GetUncategorizedFeeds())
+				}
+				return feedIDs(m.db.GetFeedsByCategory(categoryID))
+			})
+		}
+	case ViewModeSmart:
+		if m.selectedSmartIdx < len(m.smartViews) {
+			return m.markFilterRead(m.smartViews[m.selectedSmartIdx].Filter)
+		}
+	}
+	return nil
+}
+
+// markFeedsRead marks all items of the resolved feeds read in the background
+func (m *Model) markFeedsRead(resolve func() ([]string, error)) tea.Cmd {
+	return func() tea.Msg {
+		ids, err := resolve()
+		if err != nil {
+			return itemsMarkedMsg{err: err}
+		}
+		count, err := m.db.MarkFeedsRead(ids)
+		return itemsMarkedMsg{count: count, err: err}
+	}
+}
+
+func feedIDs(feeds []db.Feed, err error) ([]string, error) {
+	if err != nil {
+		return nil, err
+	}
+	ids := make([]string, len(feeds))
+	for i, f := range feeds {
+		ids[i] = f.ID
+	}
+	return ids, nil
+}
@@ -0,0 +1,448 @@
+package app
+
+import (
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/mattn/go-runewidth"
+	"github.com/plebone/nostrfeedz-cli/internal/db"
+	"github.com/plebone/nostrfeedz-cli/internal/keymap"
+	"github.com/plebone/nostrfeedz-cli/internal/player"
+	"github.com/plebone/nostrfeedz-cli/pkg/styles"
+)
+
+const (
+	playerPollInterval   = time.Second
+	positionSaveInterval = 10 * time.Second
+	seekBackStep         = 15 * time.Second
+	seekForwardStep      = 30 * time.Second
+	speedStep            = 0.25
+	minSpeed, maxSpeed   = 0.5, 3.0
+)
+
+type podcastQueueMsg struct {
+	queue    []db.Episode
+	playNext bool // An episode finished: play the head of the queue
+	err      error
+}
+
+type episodeQueuedMsg struct {
+	title string
+	err   error
+}
+
+type playerStartedMsg struct {
+	player  *player.Player
+	episode db.Episode
+	err     error
+}
+
+type playerTickMsg struct {
+	player *player.Player
+	status player.Status
+	err    error
+}
+
+type playerCommandMsg struct {
+	player *player.Player
+	status player.Status // After the command
+	err    error
+}
+
+type playerDoneMsg struct {
+	player *player.Player
+}
+
+// playerSocket is where mpv listens for commands from this process
+func playerSocket() string {
+	return filepath.Join(os.TempDir(), fmt.Sprintf("nostrfeedz-mpv-%d.sock", os.Getpid()))
+}
+
+// openPodcasts shows the episode queue
+func (m *Model) openPodcasts() tea.Cmd {
+	m.currentView = PodcastView
+	m.statusMessage = ""
+	return m.loadPodcastQueue(false)
+}
+
+func (m *Model) loadPodcastQueue(playNext bool) tea.Cmd {
+	return func() tea.Msg {
+		queue, err := m.db.GetPodcastQueue()
+		return podcastQueueMsg{queue: queue, playNext: playNext, err: err}
+	}
+}
+
+func (m *Model) podcastQueueLoaded(msg podcastQueueMsg) tea.Cmd {
+	if msg.err != nil {
+		m.statusMessage = fmt.Sprintf("Failed to load the podcast queue: %s", msg.err)
+		return nil
+	}
+	m.podcastQueue = msg.queue
+	if m.selectedEpisodeIdx >= len(m.podcastQueue) {
+		m.selectedEpisodeIdx = max(len(m.podcastQueue)-1, 0)
+	}
+	if msg.playNext && m.player == nil && len(m.podcastQueue) > 0 {
+		return m.playEpisode(m.podcastQueue[0])
+	}
+	return nil
+}
+
+// enqueueEpisode adds an article's audio enclosure to the podcast queue
+func (m *Model) enqueueEpisode(item *db.FeedItem) tea.Cmd {
+	if item == nil || item.ID == "" {
+		return nil
+	}
+	return func() tea.Msg {
+		episode, err := m.db.GetEpisode(item.ID)
+		if err != nil {
+			return episodeQueuedMsg{err: err}
+		}
+		if episode == nil {
+			return episodeQueuedMsg{err: fmt.Errorf("no audio attached to this article")}
+		}
+		if err := m.db.EnqueueEpisode(item.ID, episode.URL); err != nil {
+			return episodeQueuedMsg{err: err}
+		}
+		return episodeQueuedMsg{title: item.Title}
+	}
+}
+
+func (m *Model) episodeQueued(msg episodeQueuedMsg) tea.Cmd {
+	if msg.err != nil {
+		m.statusMessage = fmt.Sprintf("Not queued: %s", msg.err)
+		return nil
+	}
+	m.statusMessage = fmt.Sprintf("Queued %s (%s in the feed list for the queue)",
+		msg.title, m.keys.Keys(keymap.Feeds, keymap.ShowPodcasts))
+	if m.currentView == PodcastView {
+		return m.loadPodcastQueue(false)
+	}
+	return nil
+}
+
+// playEpisode stops whatever is playing and starts an episode where it
+// was left
+func (m *Model) playEpisode(episode db.Episode) tea.Cmd {
+	if m.playerStarting {
+		return nil // Both would take the same socket
+	}
+	stop := m.stopPlayback()
+	m.playerStarting = true
+	start := episode.Position
+	if episode.Duration > 0 && start >= episode.Duration-5*time.Second {
+		start = 0 // Heard to the end: start over
+	}
+	m.statusMessage = "Starting " + episode.Title + "..."
+	return func() tea.Msg {
+		if stop != nil {
+			stop() // The old player gives up the socket first
+		}
+		p, err := player.Start(episode.URL, start, playerSocket())
+		return playerStartedMsg{player: p, episode: episode, err: err}
+	}
+}
+
+func (m *Model) playerStarted(msg playerStartedMsg) tea.Cmd {
+	m.playerStarting = false
+	if msg.err != nil {
+		slog.Warn("failed to start player", "url", msg.episode.URL, "err", msg.err)
+		m.statusMessage = fmt.Sprintf("Failed to play: %s (is mpv installed?)", msg.err)
+		return nil
+	}
+	m.player = msg.player
+	m.playing = &msg.episode
+	m.playerStatus = player.Status{Position: msg.episode.Position, Duration: msg.episode.Duration, Speed: 1}
+	m.positionSaved = time.Now()
+	m.statusMessage = "Playing " + msg.episode.Title
+	return tea.Batch(pollPlayer(m.player), waitForPlayer(m.player))
+}
+
+func pollPlayer(p *player.Player) tea.Cmd {
+	return tea.Tick(playerPollInterval, func(time.Time) tea.Msg {
+		status, err := p.Status()
+		return playerTickMsg{player: p, status: status, err: err}
+	})
+}
+
+func waitForPlayer(p *player.Player) tea.Cmd {
+	return func() tea.Msg {
+		<-p.Done()
+		return playerDoneMsg{player: p}
+	}
+}
+
+// playerTicked shows the playback position and saves it now and then
+func (m *Model) playerTicked(msg playerTickMsg) tea.Cmd {
+	if msg.player != m.player {
+		return nil
+	}
+	if msg.err != nil {
+		return nil // Gone; playerDone follows
+	}
+	m.playerStatus = msg.status
+	if time.Since(m.positionSaved) >= positionSaveInterval {
+		m.savePlaybackPosition()
+	}
+	return pollPlayer(m.player)
+}
+
+// playerDone moves on to the next episode when one finishes
+func (m *Model) playerDone(msg playerDoneMsg) tea.Cmd {
+	if msg.player != m.player {
+		return nil // Stopped from here
+	}
+	episode := *m.playing
+	m.player = nil
+	m.playing = nil
+
+	if !msg.player.Finished() {
+		m.savePosition(episode, m.playerStatus)
+		m.statusMessage = "Playback stopped"
+		return m.loadPodcastQueue(false)
+	}
+
+	m.savePosition(episode, player.Status{Duration: m.playerStatus.Duration})
+	if err := m.db.DequeueEpisode(episode.ItemID); err != nil {
+		slog.Warn("failed to dequeue episode", "item", episode.ItemID, "err", err)
+	}
+	m.statusMessage = "Finished " + episode.Title
+	return m.loadPodcastQueue(true)
+}
+
+// stopPlayback forgets the player, remembering where it was, and returns
+// the command that stops it: a hung mpv can take seconds to give up on, too
+// long to wait for on the UI goroutine
+func (m *Model) stopPlayback() tea.Cmd {
+	if m.player == nil {
+		return nil
+	}
+	m.savePlaybackPosition()
+	p := m.player
+	m.player = nil
+	m.playing = nil
+	return func() tea.Msg {
+		p.Stop()
+		return nil
+	}
+}
+
+func (m *Model) savePlaybackPosition() {
+	if m.playing == nil {
+		return
+	}
+	m.savePosition(*m.playing, m.playerStatus)
+	m.positionSaved = time.Now()
+}
+
+// savePosition stores an episode's position, in the listed queue too
+func (m *Model) savePosition(episode db.Episode, status player.Status) {
+	if err := m.db.SaveEpisodePosition(episode.ItemID, episode.URL, status.Position, status.Duration); err != nil {
+		slog.Warn("failed to save episode position", "item", episode.ItemID, "err", err)
+	}
+	for i := range m.podcastQueue {
+		if m.podcastQueue[i].ItemID == episode.ItemID {
+			m.podcastQueue[i].Position = status.Position
+			if status.Duration > 0 {
+				m.podcastQueue[i].Duration = status.Duration
+			}
+		}
+	}
+}
+
+// isPlaying reports whether an episode is the one playing
+func (m *Model) isPlaying(episode db.Episode) bool {
+	return m.playing != nil && m.playing.ItemID == episode.ItemID
+}
+
+func (m *Model) updatePodcasts(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	var selected *db.Episode
+	if m.selectedEpisodeIdx < len(m.podcastQueue) {
+		selected = &m.podcastQueue[m.selectedEpisodeIdx]
+	}
+
+	switch action := m.action(msg.String()); action {
+	case keymap.Up:
+		if m.selectedEpisodeIdx > 0 {
+			m.selectedEpisodeIdx--
+		}
+	case keymap.Down:
+		if m.selectedEpisodeIdx < len(m.podcastQueue)-1 {
+			m.selectedEpisodeIdx++
+		}
+	case keymap.Open:
+		if selected != nil {
+			return m, m.playEpisode(*selected)
+		}
+	case keymap.PlayPause:
+		if m.player == nil {
+			if selected != nil {
+				return m, m.playEpisode(*selected)
+			}
+			return m, nil
+		}
+		return m, runPlayerCommand(m.player, m.player.TogglePause)
+	case keymap.SeekBack, keymap.SeekForward:
+		if m.player == nil {
+			return m, nil
+		}
+		offset := seekForwardStep
+		if action == keymap.SeekBack {
+			offset = -seekBackStep
+		}
+		p := m.player
+		return m, runPlayerCommand(p, func() error { return p.Seek(offset) })
+	case keymap.SlowDown, keymap.SpeedUp:
+		if m.player == nil {
+			return m, nil
+		}
+		speed := m.playerStatus.Speed - speedStep
+		if action == keymap.SpeedUp {
+			speed = m.playerStatus.Speed + speedStep
+		}
+		speed = min(max(speed, minSpeed), maxSpeed)
+		p := m.player
+		return m, runPlayerCommand(p, func() error { return p.SetSpeed(speed) })
+	case keymap.Dequeue:
+		if selected == nil {
+			return m, nil
+		}
+		var stop tea.Cmd
+		if m.isPlaying(*selected) {
+			stop = m.stopPlayback()
+		}
+		if err := m.db.DequeueEpisode(selected.ItemID); err != nil {
+			m.statusMessage = fmt.Sprintf("Failed to remove episode: %s", err)
+			return m, stop
+		}
+		m.statusMessage = "Removed " + selected.Title
+		return m, tea.Batch(stop, m.loadPodcastQueue(false))
+	case keymap.StopPlaying:
+		if m.player != nil {
+			m.statusMessage = "Playback stopped"
+			return m, m.stopPlayback()
+		}
+	case keymap.Back:
+		m.currentView = FeedsView
+		m.statusMessage = ""
+	}
+	return m, nil
+}
+
+// runPlayerCommand sends a command to mpv and reads the playback status
+// it led to, off the UI goroutine: mpv can take seconds to answer
+func runPlayerCommand(p *player.Player, command func() error) tea.Cmd {
+	return func() tea.Msg {
+		if err := command(); err != nil {
+			return playerCommandMsg{player: p, err: err}
+		}
+		status, err := p.Status()
+		return playerCommandMsg{player: p, status: status, err: err}
+	}
+}
+
+// playerCommanded shows the status after a player command, or why it failed
+func (m *Model) playerCommanded(msg playerCommandMsg) {
+	if msg.player != m.player {
+		return
+	}
+	if msg.err != nil {
+		m.statusMessage = fmt.Sprintf("Player: %s", msg.err)
+		return
+	}
+	m.playerStatus = msg.status
+}
+
+// formatPlayback formats a playback time as M:SS or H:MM:SS
+func formatPlayback(d time.Duration) string {
+	s := int(d.Seconds())
+	if s >= 3600 {
+		return fmt.Sprintf("%d:%02d:%02d", s/3600, s/60%60, s%60)
+	}
+	return fmt.Sprintf("%d:%02d", s/60, s%60)
+}
+
+// nowPlaying is the status line of the playing episode, "" when idle
+func (m *Model) nowPlaying() string {
+	if m.playing == nil {
+		return ""
+	}
+	icon := "▶"
+	if m.playerStatus.Paused {
+		icon = "⏸"
+	}
+	progress := formatPlayback(m.playerStatus.Position)
+	if m.playerStatus.Duration > 0 {
+		progress += " / " + formatPlayback(m.playerStatus.Duration)
+	}
+	line := fmt.Sprintf("%s %s  %s", icon, m.playing.Title, progress)
+	if m.playerStatus.Speed != 1 {
+		line += fmt.Sprintf("  %.2g×", m.playerStatus.Speed)
+	}
+	return styles.StatusBarStyle.Render(line)
+}
+
+func (m *Model) renderPodcasts() string {
+	var s strings.Builder
+	s.WriteString(styles.HeaderStyle.Render("🎧 Podcast Queue"))
+	s.WriteString("\n\n")
+
+	if len(m.podcastQueue) == 0 {
+		s.WriteString(styles.MutedStyle.Render(fmt.Sprintf(
+			"The queue is empty. Press %s on an article with audio to add its episode.",
+			m.keys.Keys(keymap.Articles, keymap.Enqueue))))
+		s.WriteString("\n")
+	}
+
+	// Keep the selection on screen
+	maxVisible := max((m.height-10)/2, 1)
+	start := max(m.selectedEpisodeIdx-maxVisible/2, 0)
+	end := min(start+maxVisible, len(m.podcastQueue))
+
+	for i := start; i < end; i++ {
+		episode := m.podcastQueue[i]
+		marker := "  "
+		if m.isPlaying(episode) {
+			marker = "▶ "
+		}
+		title := runewidth.Truncate(episode.Title, 70, "...")
+		if i == m.selectedEpisodeIdx {
+			s.WriteString(styles.SelectedStyle.Render(marker + title))
+		} else {
+			s.WriteString(styles.FeedItemStyle.Render(marker + title))
+		}
+		s.WriteString("\n")
+
+		details := episode.FeedTitle
+		switch {
+		case episode.Position > 0 && episode.Duration > 0:
+			details += fmt.Sprintf(" • %s of %s", formatPlayback(episode.Position), formatPlayback(episode.Duration))
+		case episode.Position > 0:
+			details += " • at " + formatPlayback(episode.Position)
+		case episode.Duration > 0:
+			details += " • " + formatPlayback(episode.Duration)
+		}
+		s.WriteString(styles.MutedStyle.Render("    " + details))
+		s.WriteString("\n")
+	}
+
+	s.WriteString("\n")
+	s.WriteString(styles.StatusBarStyle.Render(
+		m.hint(keymap.Open, "play") + " • " +
+			m.hint(keymap.PlayPause, "pause") + " • " +
+			m.hint(keymap.SeekBack, "-15s") + " • " +
+			m.hint(keymap.SeekForward, "+30s") + " • " +
+			m.hint(keymap.SpeedUp, "faster") + " • " +
+			m.hint(keymap.SlowDown, "slower") + " • " +
+			m.hint(keymap.Dequeue, "remove") + " • " +
+			m.hint(keymap.StopPlaying, "stop") + " • " +
+			m.hint(keymap.Back, "back")))
+	if m.statusMessage != "" {
+		s.WriteString("\n" + styles.SuccessStyle.Render(m.statusMessage))
+	}
+	return s.String()
+}
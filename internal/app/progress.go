@@ -0,0 +1,85 @@
+package app
+
+import (
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/plebone/nostrfeedz-cli/internal/db"
+)
+
+// resumeReadingPosition scrolls the article just opened to where it was
+// left and records when it was opened
+func (m *Model) resumeReadingPosition() {
+	p, err := m.db.GetReadingProgress(m.currentArticle.ID)
+	if err != nil {
+		slog.Warn("failed to load reading progress", "item", m.currentArticle.ID, "err", err)
+	}
+	if p != nil && p.InProgress() {
+		m.articleScrollOffset = p.ScrollOffset
+		m.statusMessage = fmt.Sprintf("Resumed at %d%%", p.Percent)
+	}
+
+	// The position itself is only known once the article is rendered
+	now := time.Now()
+	if err := m.db.RecordOpened(m.currentArticle.ID, now); err != nil {
+		slog.Warn("failed to record article open", "item", m.currentArticle.ID, "err", err)
+	} else {
+		opened := m.readingProgress[m.currentArticle.ID]
+		opened.ItemID = m.currentArticle.ID
+		opened.OpenedAt = now
+		m.readingProgress[m.currentArticle.ID] = opened
+	}
+	m.saveReadingPosition()
+}
+
+// saveReadingPosition stores the scroll offset and progress of the open article
+func (m *Model) saveReadingPosition() {
+	// Nothing is known about the position before the article is rendered
+	if m.currentArticle == nil || m.currentArticle.ID == "" || m.threadOpen || m.readerLines == 0 {
+		return
+	}
+
+	// Scrolling past the end is allowed, but the last screen is as far as it goes
+	offset := m.articleScrollOffset
+	if last := m.readerLines - m.readerVisibleLines(); offset > last {
+		offset = max(last, 0)
+	}
+	p := db.ReadingProgress{
+		ItemID:       m.currentArticle.ID,
+		ScrollOffset: offset,
+		Percent:      m.readingPercent(offset),
+		OpenedAt:     time.Now(),
+	}
+	if err := m.db.SaveReadingProgress(&p); err != nil {
+		slog.Warn("failed to save reading progress", "item", p.ItemID, "err", err)
+		return
+	}
+	m.readingProgress[p.ItemID] = p
+}
+
+// readingPercent is how much of the article has been on screen at an offset
+func (m *Model) readingPercent(offset int) int {
+	if m.readerLines <= 0 {
+		return 0
+	}
+	seen := offset + m.readerVisibleLines()
+	if seen >= m.readerLines {
+		return 100
+	}
+	return seen * 100 / m.readerLines
+}
+
+// loadReadingProgress loads the progress shown in the article list
+func (m *Model) loadReadingProgress() {
+	ids := make([]string, len(m.articles))
+	for i, a := range m.articles {
+		ids[i] = a.ID
+	}
+	progress, err := m.db.GetReadingProgressFor(ids)
+	if err != nil {
+		slog.Warn("failed to load reading progress", "err", err)
+		progress = make(map[string]db.ReadingProgress)
+	}
+	m.readingProgress = progress
+}
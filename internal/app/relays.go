@@ -0,0 +1,414 @@
+package app
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/plebone/nostrfeedz-cli/internal/config"
+	"github.com/plebone/nostrfeedz-cli/internal/keymap"
+	nostrClient "github.com/plebone/nostrfeedz-cli/internal/nostr"
+	"github.com/plebone/nostrfeedz-cli/pkg/styles"
+)
+
+// Relay panel message types
+type relayStatusMsg []nostrClient.RelayStatus
+type relayListPublishedMsg struct {
+	err error
+}
+type authRequestMsg nostrClient.AuthRequest
+type authResultMsg nostrClient.AuthResult
+type authDoneMsg nostrClient.AuthRequest
+
+// nextAuthPolicy cycles always -> ask -> never
+var nextAuthPolicy = map[string]string{
+	string(nostrClient.AuthAlways): string(nostrClient.AuthAsk),
+	string(nostrClient.AuthAsk):    string(nostrClient.AuthNever),
+	string(nostrClient.AuthNever):  string(nostrClient.AuthAlways),
+}
+
+func (m *Model) updateRelays(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	// Text input for adding a relay
+	if m.relayInputActive {
+		switch msg.String() {
+		case "enter":
+			url := strings.TrimSpace(m.relayInput)
+			m.relayInputActive = false
+			m.relayInput = ""
+			if url == "" {
+				return m, nil
+			}
+			if !strings.HasPrefix(url, "wss://") && !strings.HasPrefix(url, "ws://") {
+				url = "wss://" + url
+			}
+			m.cfg.Nostr.SetRelaySetting(config.RelayConfig{URL: url, Read: true, Write: true})
+			m.selectedRelayIdx = len(m.cfg.Nostr.Relays) - 1
+			m.saveRelayConfig()
+			return m, m.checkRelays([]string{url})
+		case "esc":
+			m.relayInputActive = false
+			m.relayInput = ""
+		case "backspace":
+			if len(m.relayInput) > 0 {
+				m.relayInput = m.relayInput[:len(m.relayInput)-1]
+			}
+		default:
+			if len(msg.String()) == 1 {
+				m.relayInput += msg.String()
+			}
+		}
+		return m, nil
+	}
+
+	relays := m.cfg.Nostr.Relays
+
+	switch m.action(msg.String()) {
+	case keymap.Back:
+		m.currentView = FeedsView
+		return m, nil
+
+	case keymap.Up:
+		if m.selectedRelayIdx > 0 {
+			m.selectedRelayIdx--
+		}
+
+	case keymap.Down:
+		if m.selectedRelayIdx < len(relays)-1 {
+			m.selectedRelayIdx++
+		}
+
+	case keymap.AddRelay:
+		m.relayInputActive = true
+		m.relayInput = ""
+
+	case keymap.RemoveRelay:
+		if m.selectedRelayIdx < len(relays) {
+			url := relays[m.selectedRelayIdx]
+			m.cfg.Nostr.RemoveRelay(url)
+			delete(m.relayStatuses, url)
+			if m.selectedRelayIdx >= len(m.cfg.Nostr.Relays) && m.selectedRelayIdx > 0 {
+				m.selectedRelayIdx--
+			}
+			m.saveRelayConfig()
+			m.statusMessage = "Removed " + url
+		}
+
+	case keymap.ToggleRead:
+		if m.selectedRelayIdx < len(relays) {
+			rc := m.cfg.Nostr.RelaySetting(relays[m.selectedRelayIdx])
+			rc.Read = !rc.Read
+			m.cfg.Nostr.SetRelaySetting(rc)
+			m.saveRelayConfig()
+		}
+
+	case keymap.ToggleWrite:
+		if m.selectedRelayIdx < len(relays) {
+			rc := m.cfg.Nostr.RelaySetting(relays[m.selectedRelayIdx])
+			rc.Write = !rc.Write
+			m.cfg.Nostr.SetRelaySetting(rc)
+			m.saveRelayConfig()
+		}
+
+	case keymap.AuthPolicy:
+		if m.selectedRelayIdx < len(relays) {
+			rc := m.cfg.Nostr.RelaySetting(relays[m.selectedRelayIdx])
+			rc.Auth = nextAuthPolicy[rc.Auth]
+			m.cfg.Nostr.SetRelaySetting(rc)
+			m.saveRelayConfig()
+		}
+
+	case keymap.CheckRelays:
+		m.statusMessage = "Checking relays..."
+		return m, m.checkRelays(relays)
+
+	case keymap.PublishList:
+		m.statusMessage = "Publishing relay list (kind 10002)..."
+		return m, m.publishRelayList()
+	}
+	return m, nil
+}
+
+// saveRelayConfig persists relay changes and applies them to the running clients
+func (m *Model) saveRelayConfig() {
+	if m.nostr != nil {
+		m.nostr.SetRelays(m.cfg.Nostr.ReadRelays(), m.cfg.Nostr.WriteRelays())
+		m.nostr.SetAuthPolicies(m.authPolicies())
+	}
+	m.fetcher.SetRelays(m.cfg.Nostr.ReadRelays())
+
+	if err := config.Save(m.cfg); err != nil {
+		m.statusMessage = fmt.Sprintf("Failed to save configuration: %v", err)
+		return
+	}
+	m.statusMessage = "Relay configuration saved"
+}
+
+// authPolicies converts the configured NIP-42 policies for the client
+func (m *Model) authPolicies() map[string]nostrClient.AuthPolicy {
+	policies := make(map[string]nostrClient.AuthPolicy)
+	for url, policy := range m.cfg.Nostr.RelayAuthPolicies() {
+		policies[url] = nostrClient.AuthPolicy(policy)
+	}
+	return policies
+}
+
+// waitForAuthRequest waits for a relay to ask for authentication
+func (m *Model) waitForAuthRequest() tea.Cmd {
+	if m.nostr == nil {
+		return nil
+	}
+	requests := m.nostr.AuthRequests()
+	return func() tea.Msg {
+		return authRequestMsg(<-requests)
+	}
+}
+
+// waitForAuthResult waits for the outcome of the next AUTH attempt
+func (m *Model) waitForAuthResult() tea.Cmd {
+	if m.nostr == nil {
+		return nil
+	}
+	results := m.nostr.AuthResults()
+	return func() tea.Msg {
+		return authResultMsg(<-results)
+	}
+}
+
+// waitForAuthDone reports when a prompt no longer needs an answer
+func waitForAuthDone(req nostrClient.AuthRequest) tea.Cmd {
+	return func() tea.Msg {
+		<-req.Done
+		return authDoneMsg(req)
+	}
+}
+
+// dropAuthRequest removes an answered or expired prompt from the queue
+func (m *Model) dropAuthRequest(reply chan bool) {
+	for i, req := range m.authQueue {
+		if req.Reply == reply {
+			m.authQueue = append(m.authQueue[:i], m.authQueue[i+1:]...)
+			return
+		}
+	}
+}
+
+// answerAuthRequest replies to the oldest "ask" prompt, and to any other
+// prompt from the same relay. Choosing "always" also stores the policy for
+// the relay.
+func (m *Model) answerAuthRequest(key string) bool {
+	req := m.authQueue[0]
+	var approved bool
+	switch key {
+	case "y":
+		approved = true
+	case "n":
+		approved = false
+	case "a":
+		url := req.Relay
+		for _, configured := range m.cfg.Nostr.Relays {
+			if nostrClient.NormalizeRelayURL(configured) == req.Relay {
+				url = configured
+				break
+			}
+		}
+		rc := m.cfg.Nostr.RelaySetting(url)
+		rc.Auth = string(nostrClient.AuthAlways)
+		m.cfg.Nostr.SetRelaySetting(rc)
+		m.saveRelayConfig()
+		approved = true
+	default:
+		return false
+	}
+
+	queue := m.authQueue[:0]
+	for _, pending := range m.authQueue {
+		if pending.Relay == req.Relay {
+			pending.Reply <- approved
+		} else {
+			queue = append(queue, pending)
+		}
+	}
+	m.authQueue = queue
+	return true
+}
+
+func (m *Model) renderAuthRequest() string {
+	if len(m.authQueue) == 0 {
+		return ""
+	}
+	prompt := "🔐 " + m.authQueue[0].Relay + " requests authentication (NIP-42): " +
+		styles.RenderKeyValue("y", "allow") + " • " +
+		styles.RenderKeyValue("a", "always") + " • " +
+		styles.RenderKeyValue("n", "deny")
+	if waiting := len(m.authQueue) - 1; waiting > 0 {
+		prompt += fmt.Sprintf(" (%d more waiting)", waiting)
+	}
+	return styles.StatusBarStyle.Render(prompt)
+}
+
+// checkRelays measures connection status, latency and NIP-11 info for relays
+func (m *Model) checkRelays(urls []string) tea.Cmd {
+	if m.nostr == nil {
+		m.statusMessage = "Not connected to Nostr"
+		return nil
+	}
+	if len(urls) == 0 {
+		m.statusMessage = "No relays to check"
+		return nil
+	}
+	m.relayChecking = true
+	return func() tea.Msg {
+		ctx, cancel := context.WithTimeout(context.Background(), 20*time.Second)
+		defer cancel()
+		return relayStatusMsg(m.nostr.CheckRelays(ctx, urls))
+	}
+}
+
+// publishRelayList publishes the configured relays as a kind 10002 event
+func (m *Model) publishRelayList() tea.Cmd {
+	return func() tea.Msg {
+		if m.nostr == nil {
+			return relayListPublishedMsg{fmt.Errorf("not connected to Nostr")}
+		}
+		var entries []nostrClient.RelayListEntry
+		for _, url := range m.cfg.Nostr.Relays {
+			rc := m.cfg.Nostr.RelaySetting(url)
+			entries = append(entries, nostrClient.RelayListEntry{URL: url, Read: rc.Read, Write: rc.Write})
+		}
+		return relayListPublishedMsg{m.nostr.PublishRelayList(entries)}
+	}
+}
+
+func (m *Model) renderRelays() string {
+	var s strings.Builder
+
+	s.WriteString(styles.HeaderStyle.Render("📡 Relays"))
+	s.WriteString("\n")
+	s.WriteString(styles.MutedStyle.Render("Connection status, latency and NIP-11 info for each relay"))
+	s.WriteString("\n\n")
+
+	relays := m.cfg.Nostr.Relays
+	if len(relays) == 0 {
+		s.WriteString(styles.MutedStyle.Render("No relays configured. Press 'a' to add one."))
+		s.WriteString("\n")
+	}
+
+	for i, url := range relays {
+		rc := m.cfg.Nostr.RelaySetting(url)
+		status, checked := m.relayStatuses[url]
+
+		indicator := styles.MutedStyle.Render("○")
+		if checked {
+			if status.Connected && status.LastError == "" {
+				indicator = styles.SuccessStyle.Render("●")
+			} else {
+				indicator = styles.ErrorStyle.Render("●")
+			}
+		}
+
+		flags := ""
+		if rc.Read {
+			flags += "R"
+		} else {
+			flags += "-"
+		}
+		if rc.Write {
+			flags += "W"
+		} else {
+			flags += "-"
+		}
+
+		latency := "   --  "
+		if checked && status.Latency > 0 {
+			latency = fmt.Sprintf("%5dms", status.Latency.Milliseconds())
+		}
+
+		line := fmt.Sprintf("[%s] %s  %-6s %s", flags, latency, rc.Auth, url)
+		if i == m.selectedRelayIdx {
+			s.WriteString(indicator + " " + styles.SelectedStyle.Render("▸ "+line))
+		} else {
+			s.WriteString(indicator + " " + styles.FeedItemStyle.Render("  "+line))
+		}
+		s.WriteString("\n")
+	}
+
+	// Details for the selected relay
+	if m.selectedRelayIdx < len(relays) {
+		url := relays[m.selectedRelayIdx]
+		s.WriteString("\n")
+		if status, ok := m.relayStatuses[url]; ok {
+			if status.Info != nil {
+				name := status.Info.Name
+				if name == "" {
+					name = url
+				}
+				s.WriteString(styles.KeyStyle.Render(name))
+				if status.Info.Software != "" {
+					s.WriteString(styles.MutedStyle.Render(fmt.Sprintf("  %s %s", status.Info.Software, status.Info.Version)))
+				}
+				s.WriteString("\n")
+				if status.Info.Description != "" {
+					s.WriteString(styles.MutedStyle.Render(status.Info.Description))
+					s.WriteString("\n")
+				}
+				if len(status.Info.SupportedNIPs) > 0 {
+					s.WriteString(styles.MutedStyle.Render(fmt.Sprintf("NIPs: %v", status.Info.SupportedNIPs)))
+					s.WriteString("\n")
+				}
+			} else {
+				s.WriteString(styles.MutedStyle.Render("No NIP-11 information available"))
+				s.WriteString("\n")
+			}
+
+			auth := "not required"
+			if status.AuthRequired {
+				auth = "required (NIP-42)"
+			}
+			if status.PaymentRequired {
+				auth += ", payment required"
+			}
+			s.WriteString(styles.RenderKeyValue("auth", auth))
+			s.WriteString("\n")
+			if status.LastError != "" {
+				s.WriteString(styles.RenderError(status.LastError))
+				s.WriteString("\n")
+			}
+			s.WriteString(styles.MutedStyle.Render("Checked " + status.CheckedAt.Format("15:04:05")))
+			s.WriteString("\n")
+		} else if m.relayChecking {
+			s.WriteString(styles.MutedStyle.Render("Checking..."))
+			s.WriteString("\n")
+		} else {
+			s.WriteString(styles.MutedStyle.Render("Not checked yet. Press 'c' to check."))
+			s.WriteString("\n")
+		}
+	}
+
+	if m.relayInputActive {
+		s.WriteString("\n")
+		s.WriteString("Add relay: " + m.relayInput + "▊")
+		s.WriteString("\n")
+	}
+
+	s.WriteString("\n")
+
+	statusBar := styles.StatusBarStyle.Render(
+		m.hint(keymap.Back, "back") + " • " +
+			m.hint(keymap.AddRelay, "add") + " • " +
+			m.hint(keymap.RemoveRelay, "remove") + " • " +
+			m.hint(keymap.ToggleRead, "read") + " • " +
+			m.hint(keymap.ToggleWrite, "write") + " • " +
+			m.hint(keymap.AuthPolicy, "auth policy") + " • " +
+			m.hint(keymap.CheckRelays, "check") + " • " +
+			m.hint(keymap.PublishList, "publish 10002") + " • " +
+			m.hint(keymap.Help, "help"))
+	s.WriteString(statusBar)
+
+	if m.statusMessage != "" {
+		s.WriteString("\n" + styles.SuccessStyle.Render(m.statusMessage))
+	}
+
+	return s.String()
+}
@@ -0,0 +1,152 @@
+package app
+
+import (
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/plebone/nostrfeedz-cli/internal/feed"
+	"github.com/plebone/nostrfeedz-cli/pkg/styles"
+)
+
+// spinnerFrames animate the reader while an article renders
+var spinnerFrames = []string{"⠋", "⠙", "⠹", "⠸", "⠼", "⠴", "⠦", "⠧", "⠇", "⠏"}
+
+const spinnerInterval = 100 * time.Millisecond
+
+type articleRenderedMsg struct {
+	key   feed.RenderKey
+	lines []string
+	err   error
+}
+
+type renderSpinnerMsg struct{}
+
+// renderedArticle is the open article rendered for the reader
+type renderedArticle struct {
+	key   feed.RenderKey
+	lines []string
+	err   error
+}
+
+// renderKey identifies the rendering the reader needs for the open article
+func (m *Model) renderKey() (feed.RenderKey, bool) {
+	if m.currentArticle == nil {
+		return feed.RenderKey{}, false
+	}
+	id := m.currentArticle.ID
+	if id == "" {
+		id = m.currentArticle.URL // Followed links are not stored
+	}
+	return feed.RenderKey{
+		ItemID: id,
+		Full:   m.currentArticle.FullContent != "" && !m.showSummary,
+		Width:  m.width,
+		Theme:  styles.Current().Name,
+	}, true
+}
+
+// loadRendered picks up the open article's rendering from the cache, or
+// leaves it for renderArticle to make in the background. Call it whenever
+// the article, its content, the width or the theme change.
+func (m *Model) loadRendered() {
+	key, ok := m.renderKey()
+	if !ok {
+		m.rendered = nil
+		m.readerLines = 0
+		return
+	}
+	if m.rendered != nil && m.rendered.key == key {
+		return
+	}
+	if lines, ok := m.renderCache.Get(key); ok {
+		m.setRendered(&renderedArticle{key: key, lines: lines})
+		return
+	}
+	m.rendered = nil
+	m.readerLines = 0 // Unknown until rendered
+}
+
+// renderArticle renders the open article off the UI goroutine when the
+// reader is waiting for it
+func (m *Model) renderArticle() tea.Cmd {
+	if m.currentView != ReaderView || m.rendered != nil {
+		return nil
+	}
+	key, ok := m.renderKey()
+	if !ok || (m.renderPending != nil && *m.renderPending == key) {
+		return nil
+	}
+	m.renderPending = &key
+
+	content := m.articleContent()
+	card := m.videoCard(key.Width)
+	style := styles.Current().GlamourStyle()
+	render := func() tea.Msg {
+		// A renderer of its own: glamour renderers are not safe to share
+		renderer, err := feed.NewRenderer(key.Width, style)
+		if err != nil {
+			return articleRenderedMsg{key: key, err: err}
+		}
+		rendered, err := renderer.RenderContent(content, isHTMLContent(content))
+		if err != nil {
+			return articleRenderedMsg{key: key, err: err}
+		}
+		if card != "" {
+			rendered = card + "\n" + rendered
+		}
+		return articleRenderedMsg{key: key, lines: strings.Split(rendered, "\n")}
+	}
+	if m.renderSpinning {
+		return render
+	}
+	m.renderSpinning = true
+	return tea.Batch(render, tickRenderSpinner())
+}
+
+// articleRendered caches a finished rendering and shows it if the reader
+// still needs it
+func (m *Model) articleRendered(msg articleRenderedMsg) {
+	if msg.err == nil {
+		m.renderCache.Put(msg.key, msg.lines)
+	}
+	if m.renderPending != nil && *m.renderPending == msg.key {
+		m.renderPending = nil
+	}
+	if key, ok := m.renderKey(); ok && key == msg.key {
+		m.setRendered(&renderedArticle{key: msg.key, lines: msg.lines, err: msg.err})
+		if m.currentView == ReaderView {
+			m.saveReadingPosition() // Skipped while the length was unknown
+		}
+	}
+}
+
+// setRendered shows a rendering, now that the article's length is known
+func (m *Model) setRendered(r *renderedArticle) {
+	m.rendered = r
+	m.readerLines = len(r.lines)
+	if m.currentView == ReaderView {
+		m.checkScrollEnd()
+	}
+}
+
+func tickRenderSpinner() tea.Cmd {
+	return tea.Tick(spinnerInterval, func(time.Time) tea.Msg {
+		return renderSpinnerMsg{}
+	})
+}
+
+// spinRender advances the spinner while a rendering is pending
+func (m *Model) spinRender() tea.Cmd {
+	if m.renderPending == nil {
+		m.renderSpinning = false
+		return nil
+	}
+	m.spinnerFrame = (m.spinnerFrame + 1) % len(spinnerFrames)
+	return tickRenderSpinner()
+}
+
+// renderSpinner is shown in place of the article while it renders
+func (m *Model) renderSpinner() string {
+	return styles.MutedStyle.Render(spinnerFrames[m.spinnerFrame] + " Rendering article...")
+}
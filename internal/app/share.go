@@ -0,0 +1,286 @@
+package app
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/blacktop/go-termimg"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/nbd-wtf/go-nostr"
+	"github.com/plebone/nostrfeedz-cli/internal/db"
+	"github.com/plebone/nostrfeedz-cli/internal/keymap"
+	nostrClient "github.com/plebone/nostrfeedz-cli/internal/nostr"
+	"github.com/plebone/nostrfeedz-cli/pkg/styles"
+)
+
+// composeStage is where the share dialog is
+type composeStage int
+
+const (
+	composeEditing composeStage = iota // Typing the comment
+	composePreview                     // Reviewing the note and picking relays
+	composePublishing
+	composeDone
+)
+
+type composeRelay struct {
+	URL      string
+	Selected bool
+}
+
+// compose is a note being written to share or reply to an article
+type compose struct {
+	article  db.FeedItem
+	reply    *nostrClient.ArticleRef // Set when replying instead of sharing
+	comment  string
+	stage    composeStage
+	relays   []composeRelay
+	relayIdx int
+	results  []nostrClient.PublishResult
+}
+
+type notePublishedMsg struct {
+	results []nostrClient.PublishResult
+	err     error
+}
+
+// startCompose opens the share dialog for the current article, with the
+// write relays selected
+func (m *Model) startCompose() {
+	if m.currentArticle == nil {
+		return
+	}
+	if m.nostr == nil {
+		m.statusMessage = "Not connected to Nostr"
+		return
+	}
+
+	c := &compose{article: *m.currentArticle}
+	for _, url := range m.cfg.Nostr.Relays {
+		c.relays = append(c.relays, composeRelay{URL: url, Selected: m.cfg.Nostr.RelaySetting(url).Write})
+	}
+	m.compose = c
+	m.currentView = ComposeView
+	m.statusMessage = ""
+	if m.inlineImageData != "" {
+		termimg.ClearAll() // Clear terminal images
+		m.inlineImageData = ""
+	}
+}
+
+func (m *Model) updateCompose(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	c := m.compose
+
+	switch c.stage {
+	case composeEditing:
+		// Free text, so no keymap
+		switch msg.String() {
+		case "enter":
+			if _, err := m.composeNote(); err != nil {
+				m.statusMessage = err.Error()
+				return m, nil
+			}
+			m.statusMessage = ""
+			c.stage = composePreview
+		case "esc":
+			return m.closeCompose()
+		case "backspace":
+			if len(c.comment) > 0 {
+				c.comment = c.comment[:len(c.comment)-1]
+			}
+		default:
+			if len(msg.String()) == 1 {
+				c.comment += msg.String()
+			} else if msg.Type == tea.KeySpace {
+				c.comment += " "
+			}
+		}
+
+	case composePreview:
+		switch m.action(msg.String()) {
+		case keymap.Up:
+			if c.relayIdx > 0 {
+				c.relayIdx--
+			}
+		case keymap.Down:
+			if c.relayIdx < len(c.relays)-1 {
+				c.relayIdx++
+			}
+		case keymap.ToggleRelay:
+			if c.relayIdx < len(c.relays) {
+				c.relays[c.relayIdx].Selected = !c.relays[c.relayIdx].Selected
+			}
+		case keymap.EditNote:
+			c.stage = composeEditing
+		case keymap.PublishList:
+			return m, m.publishNote()
+		case keymap.Back:
+			return m.closeCompose()
+		}
+
+	case composeDone:
+		switch m.action(msg.String()) {
+		case keymap.Back, keymap.Open, keymap.PublishList:
+			return m.closeCompose()
+		}
+	}
+	return m, nil
+}
+
+func (m *Model) closeCompose() (tea.Model, tea.Cmd) {
+	m.compose = nil
+	m.currentView = ReaderView
+	return m, tea.ClearScreen
+}
+
+// composeNote builds the note for the dialog's article and comment
+func (m *Model) composeNote() (*nostr.Event, error) {
+	c := m.compose
+	if c.reply != nil {
+		return nostrClient.NewReplyNote(*c.reply, c.comment)
+	}
+	return nostrClient.NewShareNote(c.comment, c.article.Title, c.article.URL)
+}
+
+// publishNote signs the note and publishes it to the selected relays
+func (m *Model) publishNote() tea.Cmd {
+	c := m.compose
+	var relays []string
+	for _, r := range c.relays {
+		if r.Selected {
+			relays = append(relays, r.URL)
+		}
+	}
+	if len(relays) == 0 {
+		m.statusMessage = "Select at least one relay"
+		return nil
+	}
+
+	note, err := m.composeNote()
+	if err != nil {
+		m.statusMessage = err.Error()
+		return nil
+	}
+	c.stage = composePublishing
+	m.statusMessage = fmt.Sprintf("Signing and publishing to %d relays...", len(relays))
+
+	return func() tea.Msg {
+		results, err := m.nostr.PublishEventTo(note, relays)
+		return notePublishedMsg{results: results, err: err}
+	}
+}
+
+// notePublished shows the per-relay results of a publish
+func (m *Model) notePublished(msg notePublishedMsg) tea.Cmd {
+	if m.compose == nil {
+		return nil
+	}
+	if msg.err != nil {
+		// Nothing was sent, so let the note be edited and retried
+		m.compose.stage = composePreview
+		m.statusMessage = fmt.Sprintf("Failed to sign note: %s", msg.err)
+		return nil
+	}
+
+	m.compose.stage = composeDone
+	m.compose.results = msg.results
+	ok := 0
+	for _, r := range msg.results {
+		if r.Err == nil {
+			ok++
+		}
+	}
+	m.statusMessage = fmt.Sprintf("Published to %d of %d relays", ok, len(msg.results))
+
+	// Show the new reply in the thread
+	if m.compose.reply != nil && ok > 0 {
+		return m.loadSocial()
+	}
+	return nil
+}
+
+func (m *Model) renderCompose() string {
+	var s strings.Builder
+	c := m.compose
+
+	if c.reply != nil {
+		s.WriteString(styles.HeaderStyle.Render("💬 Reply"))
+	} else {
+		s.WriteString(styles.HeaderStyle.Render("📣 Share to Nostr"))
+	}
+	s.WriteString("\n")
+	s.WriteString(styles.MutedStyle.Render(c.article.Title))
+	s.WriteString("\n\n")
+
+	var hints []string
+	switch c.stage {
+	case composeEditing:
+		if c.reply != nil {
+			s.WriteString("Your reply:\n\n")
+		} else {
+			s.WriteString("Add a comment (optional):\n\n")
+		}
+		s.WriteString(styles.PanelBorder.Width(min(m.width-4, 80)).Render(c.comment + "▊"))
+		s.WriteString("\n\n")
+		hints = []string{styles.RenderKeyValue("enter", "preview"), styles.RenderKeyValue("esc", "cancel")}
+
+	case composePreview, composePublishing:
+		note, err := m.composeNote()
+		if err != nil {
+			s.WriteString(styles.RenderError(err.Error()))
+			s.WriteString("\n\n")
+		} else {
+			s.WriteString(styles.PanelBorder.Width(min(m.width-4, 80)).Render(note.Content))
+			s.WriteString("\n")
+			for _, tag := range note.Tags {
+				s.WriteString(styles.MutedStyle.Render("  " + strings.Join(tag, " ")))
+				s.WriteString("\n")
+			}
+			s.WriteString("\n")
+		}
+
+		s.WriteString(styles.SuccessStyle.Render("Relays"))
+		s.WriteString("\n")
+		for i, r := range c.relays {
+			box := "[ ] "
+			if r.Selected {
+				box = "[x] "
+			}
+			if i == c.relayIdx && c.stage == composePreview {
+				s.WriteString(styles.SelectedStyle.Render("▸ " + box + r.URL))
+			} else {
+				s.WriteString(styles.FeedItemStyle.Render("  " + box + r.URL))
+			}
+			s.WriteString("\n")
+		}
+		s.WriteString("\n")
+		if c.stage == composePreview {
+			hints = []string{
+				m.hint(keymap.PublishList, "sign & publish"),
+				m.hint(keymap.ToggleRelay, "toggle relay"),
+				m.hint(keymap.EditNote, "edit"),
+				m.hint(keymap.Back, "cancel"),
+			}
+		}
+
+	case composeDone:
+		for _, r := range c.results {
+			if r.Err != nil {
+				s.WriteString(styles.RenderError(fmt.Sprintf("%s: %s", r.Relay, r.Err)))
+			} else {
+				s.WriteString(styles.RenderSuccess(r.Relay))
+			}
+			s.WriteString("\n")
+		}
+		s.WriteString("\n")
+		hints = []string{m.hint(keymap.Back, "back to article")}
+	}
+
+	if len(hints) > 0 {
+		s.WriteString(styles.StatusBarStyle.Render(strings.Join(hints, " • ")))
+	}
+	if m.statusMessage != "" {
+		s.WriteString("\n" + styles.SuccessStyle.Render(m.statusMessage))
+	}
+	return s.String()
+}
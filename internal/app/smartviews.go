@@ -0,0 +1,245 @@
+package app
+
+import (
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/plebone/nostrfeedz-cli/internal/db"
+	"github.com/plebone/nostrfeedz-cli/internal/keymap"
+	"github.com/plebone/nostrfeedz-cli/pkg/styles"
+)
+
+// smartView is a virtual feed backed by an item filter
+type smartView struct {
+	ID     string
+	Name   string
+	Icon   string
+	Filter db.ItemFilter
+	Saved  bool // A user-defined filter that can be deleted
+}
+
+type smartViewsLoadedMsg struct {
+	views  []smartView
+	counts map[string]int
+}
+
+type savedFilterMsg struct {
+	err error
+}
+
+// filterInputStage tracks the two-step saved filter prompt
+type filterInputStage int
+
+const (
+	filterInputNone filterInputStage = iota
+	filterInputQuery
+	filterInputName
+)
+
+// builtinSmartViews returns the built-in views, evaluated at the current time
+func builtinSmartViews() []smartView {
+	now := time.Now()
+	midnight := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+
+	return []smartView{
+		{ID: "unread", Name: "All Unread", Icon: "📬", Filter: db.ItemFilter{Read: db.ReadUnread}},
+		{ID: "today", Name: "Today", Icon: "📅", Filter: db.ItemFilter{Since: midnight}},
+		{ID: "starred", Name: "Starred", Icon: "⭐", Filter: db.ItemFilter{Starred: true}},
+		{ID: "continue", Name: "Continue Reading", Icon: "🔖", Filter: db.ItemFilter{InProgress: true}},
+		{ID: "recent", Name: "Recently Read", Icon: "🕘", Filter: db.ItemFilter{RecentlyRead: true}},
+	}
+}
+
+// loadSmartViews loads built-in and saved views with their unread counts
+func (m *Model) loadSmartViews() tea.Cmd {
+	return func() tea.Msg {
+		views := builtinSmartViews()
+
+		saved, err := m.db.GetSavedFilters()
+		if err != nil {
+			return errMsg(err)
+		}
+		for _, sf := range saved {
+			filter, err := db.ParseItemFilter(sf.Query)
+			if err != nil {
+				slog.Warn("skipping invalid saved filter", "name", sf.Name, "query", sf.Query, "err", err)
+				continue
+			}
+			views = append(views, smartView{ID: sf.ID, Name: sf.Name, Icon: "🔎", Filter: filter, Saved: true})
+		}
+
+		counts := make(map[string]int, len(views))
+		for _, v := range views {
+			count, err := m.db.CountUnreadItems(v.Filter)
+			if err != nil {
+				return errMsg(err)
+			}
+			counts[v.ID] = count
+		}
+
+		return smartViewsLoadedMsg{views: views, counts: counts}
+	}
+}
+
+// loadArticlesForFilter loads the articles of a smart view
+func (m *Model) loadArticlesForFilter(filter db.ItemFilter) tea.Cmd {
+	return func() tea.Msg {
+		articles, err := m.db.QueryFeedItems(filter)
+		if err != nil {
+			return errMsg(err)
+		}
+		return articlesLoadedMsg(articles)
+	}
+}
+
+// openSmartView shows the articles of the selected smart view
+func (m *Model) openSmartView() tea.Cmd {
+	if m.selectedSmartIdx >= len(m.smartViews) {
+		return nil
+	}
+	m.clearArticleSource()
+	view := m.smartViews[m.selectedSmartIdx]
+	m.currentSmart = &view
+	m.currentView = ArticlesView
+	m.selectedArticleIdx = 0
+	return m.loadArticlesForFilter(view.Filter)
+}
+
+// clearArticleSource forgets which feed, tag, category or view the
+// articles list was opened from
+func (m *Model) clearArticleSource() {
+	m.currentFeed = nil
+	m.currentTag = nil
+	m.currentCategory = nil
+	m.currentSmart = nil
+	m.selected = nil
+}
+
+// updateFilterInput handles typing a new saved filter: the query, then its name
+func (m *Model) updateFilterInput(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "enter":
+		input := strings.TrimSpace(m.filterInput)
+		m.filterInput = ""
+		if input == "" {
+			m.filterInputStage = filterInputNone
+			return m, nil
+		}
+		if m.filterInputStage == filterInputQuery {
+			if _, err := db.ParseItemFilter(input); err != nil {
+				m.statusMessage = fmt.Sprintf("Invalid filter: %s", err)
+				m.filterInput = input
+				return m, nil
+			}
+			m.pendingFilterQuery = input
+			m.filterInputStage = filterInputName
+			return m, nil
+		}
+		m.filterInputStage = filterInputNone
+		return m, m.saveFilter(input, m.pendingFilterQuery)
+	case "esc":
+		m.filterInputStage = filterInputNone
+		m.filterInput = ""
+	case "backspace":
+		if len(m.filterInput) > 0 {
+			m.filterInput = m.filterInput[:len(m.filterInput)-1]
+		}
+	default:
+		if len(msg.String()) == 1 {
+			m.filterInput += msg.String()
+		} else if msg.String() == "space" || msg.Type == tea.KeySpace {
+			m.filterInput += " "
+		}
+	}
+	return m, nil
+}
+
+func (m *Model) saveFilter(name, query string) tea.Cmd {
+	return func() tea.Msg {
+		err := m.db.CreateSavedFilter(&db.SavedFilter{
+			ID:        fmt.Sprintf("filter_%d", time.Now().UnixNano()),
+			Name:      name,
+			Query:     query,
+			CreatedAt: time.Now(),
+		})
+		return savedFilterMsg{err: err}
+	}
+}
+
+// deleteSelectedFilter removes the saved filter under the cursor
+func (m *Model) deleteSelectedFilter() tea.Cmd {
+	if m.selectedSmartIdx >= len(m.smartViews) || !m.smartViews[m.selectedSmartIdx].Saved {
+		m.statusMessage = "Built-in views cannot be deleted"
+		return nil
+	}
+	view := m.smartViews[m.selectedSmartIdx]
+	if m.selectedSmartIdx > 0 {
+		m.selectedSmartIdx--
+	}
+	return func() tea.Msg {
+		if err := m.db.DeleteSavedFilter(view.ID); err != nil {
+			return savedFilterMsg{err: err}
+		}
+		return savedFilterMsg{}
+	}
+}
+
+// markFilterRead marks every item of a smart view read in the background
+func (m *Model) markFilterRead(filter db.ItemFilter) tea.Cmd {
+	return func() tea.Msg {
+		count, err := m.db.MarkFilterRead(filter)
+		return itemsMarkedMsg{count: count, err: err}
+	}
+}
+
+func (m *Model) renderSmartViews(s *strings.Builder) {
+	if len(m.smartViews) == 0 {
+		s.WriteString(styles.MutedStyle.Render("Loading views..."))
+		return
+	}
+
+	s.WriteString(styles.SuccessStyle.Render(fmt.Sprintf("🔎 %d views", len(m.smartViews))))
+	s.WriteString("\n\n")
+
+	for i, v := range m.smartViews {
+		if i > 0 && v.Saved && !m.smartViews[i-1].Saved {
+			s.WriteString(styles.MutedStyle.Render("  Saved filters"))
+			s.WriteString("\n")
+		}
+
+		displayName := v.Icon + " " + v.Name
+		if count := m.smartCounts[v.ID]; count > 0 {
+			displayName = fmt.Sprintf("%s (%d)", displayName, count)
+		}
+
+		if i == m.selectedSmartIdx {
+			s.WriteString(styles.SelectedStyle.Render("▸ " + displayName))
+		} else {
+			s.WriteString(styles.FeedItemStyle.Render("  " + displayName))
+		}
+		s.WriteString("\n")
+	}
+
+	switch m.filterInputStage {
+	case filterInputQuery:
+		s.WriteString("\n")
+		s.WriteString(styles.MutedStyle.Render("feed: tag: category: author: since:7d until:2024-12-31 is:unread|read|starred, other words match text"))
+		s.WriteString("\n")
+		s.WriteString("Filter: " + m.filterInput + "▊")
+		s.WriteString("\n")
+	case filterInputName:
+		s.WriteString("\n")
+		s.WriteString(styles.MutedStyle.Render(m.pendingFilterQuery))
+		s.WriteString("\n")
+		s.WriteString("Name: " + m.filterInput + "▊")
+		s.WriteString("\n")
+	default:
+		s.WriteString("\n")
+		s.WriteString(styles.MutedStyle.Render(fmt.Sprintf("%s: new filter • %s: delete filter",
+			m.keys.Keys(keymap.Feeds, keymap.NewFilter), m.keys.Keys(keymap.Feeds, keymap.DeleteFilter))))
+		s.WriteString("\n")
+	}
+}
@@ -0,0 +1,196 @@
+package app
+
+import (
+	"fmt"
+	"log/slog"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/plebone/nostrfeedz-cli/internal/db"
+	"github.com/plebone/nostrfeedz-cli/internal/keymap"
+	nostrClient "github.com/plebone/nostrfeedz-cli/internal/nostr"
+	"github.com/plebone/nostrfeedz-cli/pkg/styles"
+)
+
+type socialLoadedMsg struct {
+	itemID string
+	social *db.ArticleSocial
+	err    error
+}
+
+type likedMsg struct {
+	itemID string
+	err    error
+}
+
+// articleRef returns the Nostr reference of the open article, if it is one
+func (m *Model) articleRef() (nostrClient.ArticleRef, bool) {
+	if m.currentArticle == nil {
+		return nostrClient.ArticleRef{}, false
+	}
+	return nostrClient.ArticleRefFor(m.currentArticle.URL, m.currentArticle.GUID)
+}
+
+// loadSocial shows the cached reactions, replies and zaps of the open
+// article and refreshes them from relays
+func (m *Model) loadSocial() tea.Cmd {
+	m.social = nil
+	m.threadOpen = false
+
+	ref, ok := m.articleRef()
+	if !ok {
+		return nil
+	}
+	itemID := m.currentArticle.ID
+	if cached, err := m.db.GetArticleSocial(itemID); err != nil {
+		slog.Warn("failed to load cached social context", "item", itemID, "err", err)
+	} else {
+		m.social = cached
+	}
+	if m.nostr == nil {
+		return nil
+	}
+
+	m.socialLoading = true
+	return func() tea.Msg {
+		social, err := m.nostr.FetchSocial(ref)
+		if err != nil {
+			return socialLoadedMsg{itemID: itemID, err: err}
+		}
+		social.ItemID = itemID
+		if err := m.db.SaveArticleSocial(social); err != nil {
+			slog.Warn("failed to cache social context", "item", itemID, "err", err)
+		}
+		return socialLoadedMsg{itemID: itemID, social: social}
+	}
+}
+
+func (m *Model) socialLoaded(msg socialLoadedMsg) {
+	if m.currentArticle == nil || m.currentArticle.ID != msg.itemID {
+		return
+	}
+	m.socialLoading = false
+	if msg.err != nil {
+		slog.Warn("failed to fetch social context", "item", msg.itemID, "err", msg.err)
+		return
+	}
+	m.social = msg.social
+}
+
+// like reacts to the open article with "+"
+func (m *Model) like() tea.Cmd {
+	ref, ok := m.articleRef()
+	if !ok {
+		m.statusMessage = "Only Nostr articles can be liked"
+		return nil
+	}
+	if m.nostr == nil {
+		m.statusMessage = "Not connected to Nostr"
+		return nil
+	}
+	if m.social != nil && m.social.Liked {
+		m.statusMessage = "Already liked"
+		return nil
+	}
+
+	itemID := m.currentArticle.ID
+	m.statusMessage = "Liking..."
+	return func() tea.Msg {
+		return likedMsg{itemID: itemID, err: m.nostr.React(ref)}
+	}
+}
+
+func (m *Model) liked(msg likedMsg) {
+	if msg.err != nil {
+		m.statusMessage = fmt.Sprintf("Failed to like: %s", msg.err)
+		return
+	}
+	m.statusMessage = "Liked"
+	if m.currentArticle == nil || m.currentArticle.ID != msg.itemID {
+		return
+	}
+	if m.social == nil {
+		m.social = &db.ArticleSocial{ItemID: msg.itemID}
+	}
+	m.social.Reactions++
+	m.social.Liked = true
+	if err := m.db.SaveArticleSocial(m.social); err != nil {
+		slog.Warn("failed to cache social context", "item", msg.itemID, "err", err)
+	}
+}
+
+// startReply opens the compose pane to reply to the open article
+func (m *Model) startReply() {
+	ref, ok := m.articleRef()
+	if !ok {
+		m.statusMessage = "Only Nostr articles can be replied to"
+		return
+	}
+	m.startCompose()
+	if m.compose != nil {
+		m.compose.reply = &ref
+	}
+}
+
+// toggleThread swaps the article body for its replies
+func (m *Model) toggleThread() {
+	if _, ok := m.articleRef(); !ok {
+		m.statusMessage = "Only Nostr articles have replies"
+		return
+	}
+	m.threadOpen = !m.threadOpen
+	m.articleScrollOffset = 0
+}
+
+// renderSocial renders the reaction, reply and zap counts of the open article
+func (m *Model) renderSocial() string {
+	if _, ok := m.articleRef(); !ok {
+		return ""
+	}
+	if m.social == nil {
+		if m.socialLoading {
+			return styles.MutedStyle.Render("Loading reactions...")
+		}
+		return ""
+	}
+
+	heart := styles.MutedStyle.Render("♡")
+	if m.social.Liked {
+		heart = styles.FavoriteBadge.Render("♥")
+	}
+	line := fmt.Sprintf("%s %d   💬 %d   ⚡ %d sats", heart, m.social.Reactions, len(m.social.Replies), m.social.ZapSats)
+	if m.social.ZapCount > 0 {
+		line += fmt.Sprintf(" (%d zaps)", m.social.ZapCount)
+	}
+	if m.socialLoading {
+		line += styles.MutedStyle.Render("   updating...")
+	}
+	return line
+}
+
+// renderThread renders the replies of the open article
+func (m *Model) renderThread() string {
+	var s strings.Builder
+	s.WriteString(styles.TitleStyle.Render(fmt.Sprintf("💬 Replies (%s to return to the article)", m.keys.Keys(keymap.Reader, keymap.Thread))))
+	s.WriteString("\n\n")
+
+	if m.social == nil || len(m.social.Replies) == 0 {
+		s.WriteString(styles.MutedStyle.Render(fmt.Sprintf("No replies yet. Press %s to reply.", m.keys.Keys(keymap.Reader, keymap.Reply))))
+		return s.String()
+	}
+
+	body := lipgloss.NewStyle().Width(max(m.width-6, 20)).PaddingLeft(2)
+	for _, r := range m.social.Replies {
+		author := r.PubKey
+		if len(author) > 8 {
+			author = author[:8] + "..."
+		}
+		s.WriteString(styles.KeyStyle.Render(author))
+		s.WriteString(styles.MutedStyle.Render(" • " + r.CreatedAt.Format("Jan 2, 15:04")))
+		s.WriteString("\n")
+		s.WriteString(body.Render(r.Content))
+		s.WriteString("\n\n")
+	}
+	return s.String()
+}
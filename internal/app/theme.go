@@ -0,0 +1,59 @@
+package app
+
+import (
+	"fmt"
+	"log/slog"
+
+	"github.com/plebone/nostrfeedz-cli/internal/config"
+	"github.com/plebone/nostrfeedz-cli/internal/db"
+	"github.com/plebone/nostrfeedz-cli/internal/feed"
+	"github.com/plebone/nostrfeedz-cli/pkg/styles"
+)
+
+// loadTheme loads the theme catalog and applies display.theme
+func loadTheme(cfg *config.Config) *styles.Catalog {
+	themes, errs := styles.LoadCatalog(config.ThemesDir())
+	for _, err := range errs {
+		slog.Warn("failed to load theme", "err", err)
+	}
+
+	theme, ok := themes.Get(cfg.Display.Theme)
+	if !ok {
+		slog.Warn("unknown theme, using default", "theme", cfg.Display.Theme)
+		theme, _ = themes.Get("default")
+	}
+	styles.Apply(theme)
+	return themes
+}
+
+// switchTheme applies a theme at runtime and saves it as display.theme
+func (m *Model) switchTheme(theme styles.Theme) {
+	styles.Apply(theme)
+
+	renderer, err := feed.NewRenderer(m.width, theme.GlamourStyle())
+	if err != nil {
+		slog.Warn("invalid glamour style, keeping previous renderer", "theme", theme.Name, "glamour", theme.Glamour, "err", err)
+	} else {
+		m.renderer = renderer
+	}
+	m.loadRendered() // Rendered in the new theme's glamour style
+
+	m.cfg.Display.Theme = theme.Name
+	if err := config.Save(m.cfg); err != nil {
+		slog.Warn("failed to save theme", "err", err)
+	}
+	m.statusMessage = fmt.Sprintf("Theme: %s", theme.Name)
+}
+
+// feedCategory returns the category a feed belongs to, if loaded
+func (m *Model) feedCategory(f db.Feed) *db.Category {
+	if f.CategoryID == "" {
+		return nil
+	}
+	for i := range m.categories {
+		if m.categories[i].ID == f.CategoryID {
+			return &m.categories[i]
+		}
+	}
+	return nil
+}
@@ -0,0 +1,136 @@
+package app
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/plebone/nostrfeedz-cli/internal/db"
+	"github.com/plebone/nostrfeedz-cli/internal/feed"
+	"github.com/plebone/nostrfeedz-cli/internal/keymap"
+	"github.com/plebone/nostrfeedz-cli/pkg/styles"
+)
+
+// rssFeedType is the feed type for a subscribed feed URL: YouTube
+// channels and playlists are kept as given and resolved when fetched
+func rssFeedType(url string) string {
+	if feed.IsYouTubeFeed(url) {
+		return "youtube"
+	}
+	return "rss"
+}
+
+// fetchType is how a feed is fetched. Channels subscribed to before the
+// youtube type existed are stored as rss but fetched as YouTube feeds.
+func fetchType(f *db.Feed) string {
+	if f.Type == "rss" && feed.IsYouTubeFeed(f.URL) {
+		return "youtube"
+	}
+	return f.Type
+}
+
+// resolveFeedURL returns the URL to parse a feed from
+func resolveFeedURL(f *db.Feed) (string, error) {
+	if fetchType(f) != "youtube" {
+		return f.URL, nil
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+	return feed.ResolveYouTubeFeed(ctx, f.URL)
+}
+
+// loadVideoMeta fills in the open article's video details
+func (m *Model) loadVideoMeta() {
+	item := m.currentArticle
+	if item == nil || item.ID == "" || item.Video != nil {
+		return
+	}
+	meta, err := m.db.GetVideoMeta(item.ID)
+	if err != nil {
+		slog.Warn("failed to load video details", "item", item.ID, "err", err)
+		return
+	}
+	item.Video = meta
+}
+
+// extractMedia finds the open article's images and videos. The article's
+// own video is named after it, and its thumbnail is the first image.
+func (m *Model) extractMedia() *feed.MediaLinks {
+	item := m.currentArticle
+	media := m.renderer.ExtractMedia(m.articleContent(), item.URL)
+	if len(media.Videos) > 0 {
+		if v, ok := feed.ParseVideoURL(item.URL); ok && v.URL == media.Videos[0].URL && item.Title != "" {
+			media.Videos[0].Title = item.Title
+		}
+	}
+	if item.Video != nil && item.Video.Thumbnail != "" {
+		media.Images = append([]string{item.Video.Thumbnail}, media.Images...)
+	}
+	return media
+}
+
+// videoCard sums up the open article's video above its description, or
+// returns "" when it is not a video from a video feed
+func (m *Model) videoCard(width int) string {
+	item := m.currentArticle
+	if item == nil || item.Video == nil {
+		return ""
+	}
+
+	var details []string
+	if item.Author != "" {
+		details = append(details, item.Author)
+	}
+	if item.Video.Views > 0 {
+		details = append(details, formatViews(item.Video.Views))
+	}
+	if !item.PublishedAt.IsZero() {
+		details = append(details, item.PublishedAt.Format("January 2, 2006"))
+	}
+
+	var s strings.Builder
+	s.WriteString(styles.HeaderStyle.Render("▶ " + item.Title))
+	if len(details) > 0 {
+		s.WriteString("\n")
+		s.WriteString(styles.MutedStyle.Render(strings.Join(details, " • ")))
+	}
+	s.WriteString("\n\n")
+	hint := styles.RenderKeyValue(m.keys.Keys(keymap.Reader, keymap.PlayVideo), "play")
+	if item.Video.Thumbnail != "" {
+		hint += " • " + styles.RenderKeyValue(m.keys.Keys(keymap.Reader, keymap.ViewImage), "thumbnail")
+	}
+	s.WriteString(hint)
+
+	return lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(styles.AccentColor).
+		Padding(0, 1).
+		Width(min(width-4, 76)).
+		Render(s.String())
+}
+
+// formatViews shortens a view count: "812 views", "12K views", "1.2M views"
+func formatViews(n int64) string {
+	switch {
+	case n == 1:
+		return "1 view"
+	case n < 1000:
+		return fmt.Sprintf("%d views", n)
+	case n < 1_000_000:
+		return fmt.Sprintf("%s views", shortCount(float64(n)/1e3, "K"))
+	case n < 1_000_000_000:
+		return fmt.Sprintf("%s views", shortCount(float64(n)/1e6, "M"))
+	}
+	return fmt.Sprintf("%s views", shortCount(float64(n)/1e9, "B"))
+}
+
+// shortCount keeps one decimal below 10: "1.2M", but "12M"
+func shortCount(n float64, unit string) string {
+	if n < 10 {
+		return strings.TrimSuffix(fmt.Sprintf("%.1f", n), ".0") + unit
+	}
+	return fmt.Sprintf("%d%s", int(n), unit)
+}
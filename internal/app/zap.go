@@ -0,0 +1,125 @@
+package app
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strconv"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/plebone/nostrfeedz-cli/internal/db"
+	nostrClient "github.com/plebone/nostrfeedz-cli/internal/nostr"
+	"github.com/plebone/nostrfeedz-cli/pkg/styles"
+)
+
+type zappedMsg struct {
+	itemID string
+	sats   int64
+	err    error
+}
+
+// startZap opens the amount prompt for zapping the open article's author
+func (m *Model) startZap() {
+	ref, ok := m.articleRef()
+	if !ok {
+		m.statusMessage = "Only Nostr articles can be zapped"
+		return
+	}
+	if ref.Author == "" {
+		m.statusMessage = "Article author is unknown"
+		return
+	}
+	if m.nostr == nil {
+		m.statusMessage = "Not connected to Nostr"
+		return
+	}
+	if m.cfg.Nostr.WalletConnect == "" {
+		m.statusMessage = "Set nostr.wallet_connect in the config to zap"
+		return
+	}
+
+	m.zapInputActive = true
+	m.zapInput = ""
+	if m.cfg.Nostr.ZapAmount > 0 {
+		m.zapInput = strconv.FormatInt(m.cfg.Nostr.ZapAmount, 10)
+	}
+	m.statusMessage = ""
+}
+
+func (m *Model) updateZapInput(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	// Free text, so no keymap
+	switch msg.String() {
+	case "enter":
+		sats, err := strconv.ParseInt(m.zapInput, 10, 64)
+		if err != nil || sats <= 0 {
+			m.statusMessage = "Enter an amount in sats"
+			return m, nil
+		}
+		m.zapInputActive = false
+		return m, m.zap(sats)
+	case "esc":
+		m.zapInputActive = false
+		m.statusMessage = ""
+	case "backspace":
+		if len(m.zapInput) > 0 {
+			m.zapInput = m.zapInput[:len(m.zapInput)-1]
+		}
+	default:
+		for _, r := range msg.Runes {
+			if r >= '0' && r <= '9' && len(m.zapInput) < 9 {
+				m.zapInput += string(r)
+			}
+		}
+	}
+	return m, nil
+}
+
+// zap pays the open article's author through the configured wallet
+func (m *Model) zap(sats int64) tea.Cmd {
+	ref, ok := m.articleRef()
+	if !ok || m.nostr == nil {
+		return nil
+	}
+	wallet, err := nostrClient.ParseWalletConnect(m.cfg.Nostr.WalletConnect)
+	if err != nil {
+		m.statusMessage = err.Error()
+		return nil
+	}
+
+	itemID := m.currentArticle.ID
+	m.statusMessage = fmt.Sprintf("⚡ Zapping %d sats...", sats)
+	return func() tea.Msg {
+		ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
+		defer cancel()
+		_, err := m.nostr.Zap(ctx, wallet, ref, sats, "")
+		return zappedMsg{itemID: itemID, sats: sats, err: err}
+	}
+}
+
+func (m *Model) zapped(msg zappedMsg) {
+	if msg.err != nil {
+		slog.Warn("zap failed", "item", msg.itemID, "err", msg.err)
+		m.statusMessage = fmt.Sprintf("Zap failed: %s", msg.err)
+		return
+	}
+	m.statusMessage = fmt.Sprintf("⚡ Zapped %d sats", msg.sats)
+	if m.currentArticle == nil || m.currentArticle.ID != msg.itemID {
+		return
+	}
+
+	// The receipt reaches relays shortly; count it now
+	if m.social == nil {
+		m.social = &db.ArticleSocial{ItemID: msg.itemID}
+	}
+	m.social.ZapCount++
+	m.social.ZapSats += msg.sats
+	if err := m.db.SaveArticleSocial(m.social); err != nil {
+		slog.Warn("failed to cache social context", "item", msg.itemID, "err", err)
+	}
+}
+
+func (m *Model) renderZapPrompt() string {
+	return styles.KeyStyle.Render("⚡ Zap amount (sats): ") + m.zapInput + "█" +
+		styles.MutedStyle.Render("   enter zap • esc cancel")
+}
@@ -0,0 +1,142 @@
+// Package archive keeps articles readable offline: the full article and
+// every image it references, pinned in the image cache
+package archive
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/plebone/nostrfeedz-cli/internal/cache"
+	"github.com/plebone/nostrfeedz-cli/internal/db"
+	"github.com/plebone/nostrfeedz-cli/internal/export"
+	"github.com/plebone/nostrfeedz-cli/internal/feed"
+)
+
+// batchSize caps how many items one Sync archives, so a newly archived
+// feed is worked through over several syncs
+const batchSize = 20
+
+// Archiver archives starred items and items of archived feeds
+type Archiver struct {
+	db      *db.DB
+	images  *cache.ImageCache
+	starred bool // Archive starred items
+
+	mu sync.Mutex // One sync at a time
+}
+
+// New returns an archiver. Starred items are archived when starred is set;
+// items of feeds with Archive set always are.
+func New(database *db.DB, images *cache.ImageCache, starred bool) *Archiver {
+	return &Archiver{db: database, images: images, starred: starred}
+}
+
+// Result reports what a Sync did
+type Result struct {
+	Archived int
+	Removed  int
+	Failed   int
+	More     bool // Items are left for another Sync
+	Busy     bool // Another sync was running, nothing was done
+}
+
+// Sync archives items that should be and are not yet, and releases
+// archives that are no longer wanted
+func (a *Archiver) Sync(ctx context.Context) (Result, error) {
+	var result Result
+	if !a.mu.TryLock() {
+		result.Busy = true
+		return result, nil
+	}
+	defer a.mu.Unlock()
+
+	stale, err := a.db.GetStaleArchives(a.starred)
+	if err != nil {
+		return result, err
+	}
+	for _, id := range stale {
+		if err := a.images.Unpin(id); err != nil {
+			return result, err
+		}
+		if err := a.db.DeleteArchive(id); err != nil {
+			return result, err
+		}
+		result.Removed++
+	}
+
+	items, err := a.db.GetItemsToArchive(a.starred, batchSize)
+	if err != nil {
+		return result, err
+	}
+	for _, item := range items {
+		if err := ctx.Err(); err != nil {
+			return result, err
+		}
+		if err := a.Archive(ctx, &item); err != nil {
+			slog.Warn("failed to archive item", "item", item.ID, "err", err)
+			result.Failed++
+			continue
+		}
+		result.Archived++
+	}
+	// Keep going while batches come back full, but not over failures
+	result.More = len(items) == batchSize && result.Failed == 0
+	return result, nil
+}
+
+// Archive saves one item for offline reading. RSS items get their full
+// article extracted if they do not have it yet; when that fails the feed's
+// content is archived instead. Every referenced image is downloaded and
+// pinned.
+func (a *Archiver) Archive(ctx context.Context, item *db.ArchivedItem) error {
+	if item.FullContent == "" && item.FeedType == "rss" && item.URL != "" {
+		pageCtx, cancel := context.WithTimeout(ctx, 30*time.Second)
+		article, err := feed.FetchArticle(pageCtx, item.URL)
+		cancel()
+		if err != nil {
+			slog.Debug("archiving feed content only", "item", item.ID, "err", err)
+		} else {
+			if err := a.db.SetItemFullContent(item.ID, article.Content); err != nil {
+				return err
+			}
+			item.FullContent = article.Content
+		}
+	}
+
+	var pinned []string
+	for _, url := range export.ImageURLs(export.FromItem(item.FeedItem, item.FeedTitle)) {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		if _, err := a.images.Download(url); err != nil {
+			slog.Debug("failed to archive image", "url", url, "err", err)
+			continue
+		}
+		pinned = append(pinned, url)
+	}
+	if err := a.images.Pin(item.ID, pinned); err != nil {
+		return fmt.Errorf("failed to pin images: %w", err)
+	}
+	item.Images = len(pinned)
+	return a.db.SaveArchive(item.ID, len(pinned))
+}
+
+// Articles returns every archived item as an export article, newest first
+func (a *Archiver) Articles() ([]export.Article, error) {
+	items, err := a.db.GetArchivedItems()
+	if err != nil {
+		return nil, err
+	}
+	if len(items) == 0 {
+		return nil, fmt.Errorf("nothing archived yet")
+	}
+
+	articles := make([]export.Article, len(items))
+	for i, item := range items {
+		articles[i] = export.FromItem(item.FeedItem, item.FeedTitle)
+	}
+	return articles, nil
+}
@@ -0,0 +1,218 @@
+package cache
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"mime"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// DownloadTimeout bounds one image download, from connecting to the
+	// last byte
+	DownloadTimeout = 30 * time.Second
+
+	// MaxImageBytes is the largest image that is downloaded (20 MB)
+	MaxImageBytes = 20 * 1024 * 1024
+
+	// DownloadWorkers is how many background downloads run at once
+	DownloadWorkers = 4
+
+	// queueSize is how many background downloads can wait for a worker;
+	// more are dropped, they are retried when the article is opened
+	queueSize = 256
+)
+
+// downloader fetches images into the cache directory. Downloads of the
+// same URL share one request, background downloads run on a fixed pool of
+// workers, and files only appear under their final name once complete.
+type downloader struct {
+	client   *http.Client
+	maxBytes int64
+	dir      string
+
+	mu       sync.Mutex
+	inflight map[string]*download
+
+	queue   chan string
+	workers sync.Once
+}
+
+// download is a request in flight, shared by everyone asking for its URL
+type download struct {
+	done        chan struct{}
+	path        string
+	contentType string
+	size        int64
+	err         error
+}
+
+func newDownloader(dir string) *downloader {
+	return &downloader{
+		client:   &http.Client{Timeout: DownloadTimeout},
+		maxBytes: MaxImageBytes,
+		dir:      dir,
+		inflight: make(map[string]*download),
+		queue:    make(chan string, queueSize),
+	}
+}
+
+// fetch downloads an image, or waits for the download of it already
+// running. save is called once per download with the completed file, to
+// index it; if it fails the file is removed.
+func (d *downloader) fetch(ctx context.Context, imageURL string, save func(*download) error) (*download, error) {
+	d.mu.Lock()
+	if dl, ok := d.inflight[imageURL]; ok {
+		d.mu.Unlock()
+		select {
+		case <-dl.done:
+			return dl, dl.err
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+	dl := &download{done: make(chan struct{})}
+	d.inflight[imageURL] = dl
+	d.mu.Unlock()
+
+	dl.err = d.get(ctx, imageURL, dl)
+	if dl.err == nil {
+		if dl.err = save(dl); dl.err != nil {
+			os.Remove(dl.path)
+		}
+	}
+
+	d.mu.Lock()
+	delete(d.inflight, imageURL)
+	d.mu.Unlock()
+	close(dl.done)
+	return dl, dl.err
+}
+
+// get downloads an image to a temporary file, checks it is an image and
+// renames it to its final name
+func (d *downloader) get(ctx context.Context, imageURL string, dl *download) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, imageURL, nil)
+	if err != nil {
+		return fmt.Errorf("invalid image URL: %w", err)
+	}
+	req.Header.Set("User-Agent", "Mozilla/5.0 (compatible; nostrfeedz-cli)")
+	req.Header.Set("Accept", "image/*")
+
+	resp, err := d.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to download: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("download failed with status: %d", resp.StatusCode)
+	}
+	if resp.ContentLength > d.maxBytes {
+		return fmt.Errorf("image is too large (%d bytes)", resp.ContentLength)
+	}
+
+	tmp, err := os.CreateTemp(d.dir, ".download-*")
+	if err != nil {
+		return fmt.Errorf("failed to create cache file: %w", err)
+	}
+	defer os.Remove(tmp.Name()) // A no-op once renamed
+
+	// Read one byte past the limit to tell a full image from a cut one
+	size, err := io.Copy(tmp, io.LimitReader(resp.Body, d.maxBytes+1))
+	if cerr := tmp.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		return fmt.Errorf("failed to write cache: %w", err)
+	}
+	if size > d.maxBytes {
+		return fmt.Errorf("image is larger than %d bytes", d.maxBytes)
+	}
+
+	contentType, err := sniff(tmp.Name(), resp.Header.Get("Content-Type"))
+	if err != nil {
+		return err
+	}
+
+	path := filepath.Join(d.dir, hashName(imageURL)+imageExt(contentType))
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return fmt.Errorf("failed to write cache: %w", err)
+	}
+	dl.path, dl.contentType, dl.size = path, contentType, size
+	return nil
+}
+
+// sniff returns the content type of a downloaded file, or an error if it
+// is not an image. The server's Content-Type is only trusted for image
+// formats the sniffer does not know.
+func sniff(path, declared string) (string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+	head := make([]byte, 512)
+	n, _ := io.ReadFull(f, head)
+	head = head[:n]
+
+	detected := http.DetectContentType(head)
+	declared, _, _ = mime.ParseMediaType(declared)
+	switch {
+	case strings.HasPrefix(detected, "image/"):
+		return detected, nil
+	case (strings.HasPrefix(detected, "text/xml") || strings.HasPrefix(detected, "text/plain")) &&
+		bytes.Contains(head, []byte("<svg")):
+		return "image/svg+xml", nil
+	case detected == "application/octet-stream" && strings.HasPrefix(declared, "image/"):
+		// AVIF, HEIC and the like
+		return declared, nil
+	}
+	return "", fmt.Errorf("not an image (%s)", detected)
+}
+
+// imageExt is the file extension for an image content type
+func imageExt(contentType string) string {
+	switch contentType {
+	case "image/jpeg":
+		return ".jpg"
+	case "image/svg+xml":
+		return ".svg"
+	case "image/x-icon", "image/vnd.microsoft.icon":
+		return ".ico"
+	}
+	sub := strings.TrimPrefix(contentType, "image/")
+	if sub == contentType || strings.ContainsAny(sub, "/+;. ") {
+		return ""
+	}
+	return "." + strings.TrimPrefix(sub, "x-")
+}
+
+// enqueue schedules a background download, starting the workers on first
+// use. It returns false if the queue is full.
+func (d *downloader) enqueue(imageURL string, download func(string)) bool {
+	d.workers.Do(func() {
+		for i := 0; i < DownloadWorkers; i++ {
+			go func() {
+				for url := range d.queue {
+					download(url)
+				}
+			}()
+		}
+	})
+	select {
+	case d.queue <- imageURL:
+		return true
+	default:
+		slog.Debug("image download queue is full", "url", imageURL)
+		return false
+	}
+}
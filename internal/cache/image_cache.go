@@ -1,230 +1,327 @@
 package cache
 
 import (
+	"context"
 	"crypto/sha256"
 	"encoding/hex"
 	"fmt"
-	"io"
-	"net/http"
+	"log/slog"
 	"os"
 	"path/filepath"
+	"sync"
 	"time"
 
 	"github.com/plebone/nostrfeedz-cli/internal/db"
 )
 
 const (
-	// CacheExpiration is how long to keep non-favorited images (30 days)
-	CacheExpiration = 30 * 24 * time.Hour
-	
-	// MaxCacheSize is the maximum cache size in bytes (500 MB)
-	MaxCacheSize = 500 * 1024 * 1024
+	// DefaultExpiration is how long unused images are kept (30 days)
+	DefaultExpiration = 30 * 24 * time.Hour
+
+	// DefaultMaxSize is the default cache size limit in bytes (500 MB)
+	DefaultMaxSize = 500 * 1024 * 1024
 )
 
-// ImageCache manages cached images
+// ImageCache manages cached images. Every image is indexed in the database
+// with its size, content type, last access and the items showing it, so
+// eviction is least recently used first and images of starred and archived
+// items are never evicted.
 type ImageCache struct {
 	cacheDir string
 	db       *db.DB
+	dl       *downloader
+
+	mu         sync.Mutex // Guards the limits
+	maxSize    int64
+	expiration time.Duration
 }
 
 // NewImageCache creates a new image cache
 func NewImageCache(cacheDir string, database *db.DB) (*ImageCache, error) {
+	if database == nil {
+		return nil, fmt.Errorf("the image cache needs a database")
+	}
 	// Create cache directory if it doesn't exist
 	if err := os.MkdirAll(cacheDir, 0755); err != nil {
 		return nil, fmt.Errorf("failed to create cache directory: %w", err)
 	}
 
 	return &ImageCache{
-		cacheDir: cacheDir,
-		db:       database,
+		cacheDir:   cacheDir,
+		db:         database,
+		dl:         newDownloader(cacheDir),
+		maxSize:    DefaultMaxSize,
+		expiration: DefaultExpiration,
 	}, nil
 }
 
-// GetCachePath returns the cache file path for a URL
+// SetLimits sets the size limit in bytes and how long unused images are
+// kept. Zero disables either limit.
+func (c *ImageCache) SetLimits(maxSize int64, expiration time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.maxSize = maxSize
+	c.expiration = expiration
+}
+
+// SetDownloadLimits overrides MaxImageBytes and DownloadTimeout. Call it
+// before the first download.
+func (c *ImageCache) SetDownloadLimits(maxBytes int64, timeout time.Duration) {
+	c.dl.maxBytes = maxBytes
+	c.dl.client.Timeout = timeout
+}
+
+func (c *ImageCache) limits() (int64, time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.maxSize, c.expiration
+}
+
+// GetCachePath returns the cache file path for a URL as named before the
+// index: downloads are now named after their sniffed content type and
+// looked up through the index
 func (c *ImageCache) GetCachePath(imageURL string) string {
-	// Hash the URL to create a unique filename
+	return filepath.Join(c.cacheDir, hashName(imageURL)+filepath.Ext(imageURL))
+}
+
+// hashName is the file name of a cached URL, without extension
+func hashName(imageURL string) string {
 	hash := sha256.Sum256([]byte(imageURL))
-	filename := hex.EncodeToString(hash[:]) + filepath.Ext(imageURL)
-	return filepath.Join(c.cacheDir, filename)
+	return hex.EncodeToString(hash[:])
 }
 
 // IsCached checks if an image is already cached
 func (c *ImageCache) IsCached(imageURL string) bool {
-	cachePath := c.GetCachePath(imageURL)
-	_, err := os.Stat(cachePath)
+	_, err := c.lookup(imageURL)
 	return err == nil
 }
 
-// GetCached returns the cached file path if it exists
+// GetCached returns the cached file path if it exists, and records the
+// access for eviction
 func (c *ImageCache) GetCached(imageURL string) (string, error) {
+	path, err := c.lookup(imageURL)
+	if err != nil {
+		return "", err
+	}
+	if err := c.db.TouchCachedImage(imageURL); err != nil {
+		slog.Debug("failed to record image access", "url", imageURL, "err", err)
+	}
+	return path, nil
+}
+
+// lookup finds an image's file through the index. Files cached before the
+// index existed are indexed on first sight; index entries whose file is
+// gone are dropped.
+func (c *ImageCache) lookup(imageURL string) (string, error) {
+	img, err := c.db.GetCachedImage(imageURL)
+	if err != nil {
+		return "", err
+	}
+	if img != nil {
+		if _, err := os.Stat(img.Path); err == nil {
+			return img.Path, nil
+		}
+		c.db.DeleteCachedImage(imageURL)
+		return "", fmt.Errorf("image not cached")
+	}
+
 	cachePath := c.GetCachePath(imageURL)
-	if _, err := os.Stat(cachePath); err != nil {
+	info, err := os.Stat(cachePath)
+	if err != nil {
 		return "", fmt.Errorf("image not cached")
 	}
-	
-	// Update access time
-	now := time.Now()
-	os.Chtimes(cachePath, now, now)
-	
+	if err := c.index(imageURL, cachePath, info.Size(), "", info.ModTime()); err != nil {
+		return "", err
+	}
 	return cachePath, nil
 }
 
-// Download downloads and caches an image
+// index records a cached file
+func (c *ImageCache) index(imageURL, path string, size int64, contentType string, accessed time.Time) error {
+	return c.db.SaveCachedImage(&db.CachedImage{
+		URL:         imageURL,
+		Path:        path,
+		Size:        size,
+		ContentType: contentType,
+		LastAccess:  accessed,
+		CreatedAt:   time.Now(),
+	})
+}
+
+// Download downloads and caches an image. Only images are kept: anything
+// else, anything larger than MaxImageBytes, or a download exceeding
+// DownloadTimeout fails. Concurrent downloads of a URL share one request.
 func (c *ImageCache) Download(imageURL string) (string, error) {
 	// Check if already cached
 	if c.IsCached(imageURL) {
 		return c.GetCached(imageURL)
 	}
 
-	// Download the image
-	resp, err := http.Get(imageURL)
+	dl, err := c.dl.fetch(context.Background(), imageURL, func(dl *download) error {
+		if err := c.index(imageURL, dl.path, dl.size, dl.contentType, time.Now()); err != nil {
+			return fmt.Errorf("failed to index cached image: %w", err)
+		}
+		return nil
+	})
 	if err != nil {
-		return "", fmt.Errorf("failed to download: %w", err)
+		return "", err
 	}
-	defer resp.Body.Close()
 
-	if resp.StatusCode != http.StatusOK {
-		return "", fmt.Errorf("download failed with status: %d", resp.StatusCode)
+	if err := c.EnforceSizeLimit(); err != nil {
+		slog.Warn("failed to enforce image cache size limit", "err", err)
 	}
+	return dl.path, nil
+}
 
-	// Create cache file
-	cachePath := c.GetCachePath(imageURL)
-	file, err := os.Create(cachePath)
-	if err != nil {
-		return "", fmt.Errorf("failed to create cache file: %w", err)
+// CleanupExpired removes images that were not used within the expiration,
+// along with files the index does not know that are as old
+func (c *ImageCache) CleanupExpired() error {
+	_, expiration := c.limits()
+	if expiration <= 0 {
+		return nil
 	}
-	defer file.Close()
+	cutoff := time.Now().Add(-expiration)
 
-	// Copy image data to cache
-	_, err = io.Copy(file, resp.Body)
-	if err != nil {
-		os.Remove(cachePath)
-		return "", fmt.Errorf("failed to write cache: %w", err)
+	if err := c.evict(func(img db.CachedImage, _ int64) bool {
+		return img.LastAccess.Before(cutoff)
+	}); err != nil {
+		return err
 	}
 
-	return cachePath, nil
-}
-
-// DownloadAsync downloads an image in the background
-func (c *ImageCache) DownloadAsync(imageURL string, callback func(string, error)) {
-	go func() {
-		path, err := c.Download(imageURL)
-		if callback != nil {
-			callback(path, err)
-		}
-	}()
-}
-
-// CleanupExpired removes expired cached images
-func (c *ImageCache) CleanupExpired() error {
-	now := time.Now()
-	
-	return filepath.Walk(c.cacheDir, func(path string, info os.FileInfo, err error) error {
-		if err != nil {
-			return err
-		}
-		
-		if info.IsDir() {
-			return nil
+	// Files left over from before the index, or from an interrupted write
+	indexed, err := c.db.GetCachedImagePaths()
+	if err != nil {
+		return err
+	}
+	entries, err := os.ReadDir(c.cacheDir)
+	if err != nil {
+		return err
+	}
+	for _, entry := range entries {
+		path := filepath.Join(c.cacheDir, entry.Name())
+		if entry.IsDir() || indexed[path] {
+			continue
 		}
-		
-		// Check if file is expired
-		age := now.Sub(info.ModTime())
-		if age > CacheExpiration {
-			// Check if any favorited article uses this image
-			// (We'll need to track image-to-article mapping)
-			// For now, just delete if expired
+		if info, err := entry.Info(); err == nil && info.ModTime().Before(cutoff) {
 			os.Remove(path)
 		}
-		
-		return nil
-	})
-}
-
-// CleanupDeleted removes cached images for deleted articles
-func (c *ImageCache) CleanupDeleted(articleID string, imageURLs []string) error {
-	for _, url := range imageURLs {
-		cachePath := c.GetCachePath(url)
-		os.Remove(cachePath)
 	}
+	c.cleanupThumbnails(cutoff)
 	return nil
 }
 
-// GetCacheSize returns the total size of the cache
-func (c *ImageCache) GetCacheSize() (int64, error) {
-	var size int64
-	
-	err := filepath.Walk(c.cacheDir, func(path string, info os.FileInfo, err error) error {
-		if err != nil {
-			return err
-		}
-		if !info.IsDir() {
-			size += info.Size()
-		}
+// EnforceSizeLimit removes the least recently used images until the cache
+// fits its size limit
+func (c *ImageCache) EnforceSizeLimit() error {
+	maxSize, _ := c.limits()
+	if maxSize <= 0 {
 		return nil
+	}
+	return c.evict(func(_ db.CachedImage, size int64) bool {
+		return size > maxSize
 	})
-	
-	return size, err
 }
 
-// EnforceSizeLimit removes oldest files if cache exceeds size limit
-func (c *ImageCache) EnforceSizeLimit() error {
-	size, err := c.GetCacheSize()
+// Cleanup applies the expiration and then the size limit
+func (c *ImageCache) Cleanup() error {
+	if err := c.CleanupExpired(); err != nil {
+		return err
+	}
+	return c.EnforceSizeLimit()
+}
+
+// evict walks the unpinned images, least recently used first, removing
+// them while remove holds for the image and the current cache size
+func (c *ImageCache) evict(remove func(img db.CachedImage, size int64) bool) error {
+	size, err := c.db.GetCachedImagesSize()
 	if err != nil {
 		return err
 	}
-	
-	if size <= MaxCacheSize {
-		return nil
+	images, err := c.db.GetEvictableImages()
+	if err != nil {
+		return err
 	}
-	
-	// Collect all files with their access times
-	type fileInfo struct {
-		path    string
-		modTime time.Time
-		size    int64
-	}
-	
-	var files []fileInfo
-	
-	filepath.Walk(c.cacheDir, func(path string, info os.FileInfo, err error) error {
-		if err != nil || info.IsDir() {
-			return err
+
+	removed := 0
+	for _, img := range images {
+		if !remove(img, size) {
+			break
 		}
-		files = append(files, fileInfo{
-			path:    path,
-			modTime: info.ModTime(),
-			size:    info.Size(),
-		})
-		return nil
-	})
-	
-	// Sort by modification time (oldest first)
-	for i := 0; i < len(files)-1; i++ {
-		for j := i + 1; j < len(files); j++ {
-			if files[i].modTime.After(files[j].modTime) {
-				files[i], files[j] = files[j], files[i]
-			}
+		if err := os.Remove(img.Path); err != nil && !os.IsNotExist(err) {
+			slog.Warn("failed to remove cached image", "path", img.Path, "err", err)
+			continue
 		}
+		c.removeThumbnails(img.URL)
+		if err := c.db.DeleteCachedImage(img.URL); err != nil {
+			return err
+		}
+		size -= img.Size
+		removed++
 	}
-	
-	// Remove oldest files until under limit
-	for _, f := range files {
-		if size <= MaxCacheSize {
-			break
+	if removed > 0 {
+		slog.Debug("evicted cached images", "count", removed, "size", size)
+	}
+	return nil
+}
+
+// Reference records that an item shows images, so they are kept while the
+// item is starred
+func (c *ImageCache) Reference(itemID string, imageURLs []string) error {
+	return c.db.AddImageReferences(itemID, imageURLs)
+}
+
+// Pin keeps an item's images in the cache until Unpin, regardless of age
+// or the size limit
+func (c *ImageCache) Pin(itemID string, imageURLs []string) error {
+	if err := c.Reference(itemID, imageURLs); err != nil {
+		return err
+	}
+	return c.db.PinImages(itemID, imageURLs)
+}
+
+// Unpin lets an item's images expire normally again
+func (c *ImageCache) Unpin(itemID string) error {
+	return c.db.UnpinImages(itemID)
+}
+
+// CleanupDeleted removes cached images for deleted articles
+func (c *ImageCache) CleanupDeleted(articleID string, imageURLs []string) error {
+	for _, url := range imageURLs {
+		if path, err := c.lookup(url); err == nil {
+			os.Remove(path)
+		}
+		c.removeThumbnails(url)
+		if err := c.db.DeleteCachedImage(url); err != nil {
+			return err
 		}
-		os.Remove(f.path)
-		size -= f.size
 	}
-	
 	return nil
 }
 
-// PreloadArticleImages downloads all images for an article in the background
-func (c *ImageCache) PreloadArticleImages(imageURLs []string) {
+// GetCacheSize returns the total size of the cache
+func (c *ImageCache) GetCacheSize() (int64, error) {
+	return c.db.GetCachedImagesSize()
+}
+
+// PreloadArticleImages downloads all images for an article in the
+// background, on the download workers
+func (c *ImageCache) PreloadArticleImages(itemID string, imageURLs []string) {
+	if err := c.Reference(itemID, imageURLs); err != nil {
+		slog.Debug("failed to record image references", "item", itemID, "err", err)
+	}
 	for _, url := range imageURLs {
 		if !c.IsCached(url) {
-			c.DownloadAsync(url, nil)
+			c.dl.enqueue(url, c.preload)
 		}
 	}
 }
+
+// preload is a background download, whose failure nobody waits for
+func (c *ImageCache) preload(imageURL string) {
+	if c.IsCached(imageURL) {
+		return
+	}
+	if _, err := c.Download(imageURL); err != nil {
+		slog.Debug("failed to preload image", "url", imageURL, "err", err)
+	}
+}
@@ -0,0 +1,134 @@
+package cache
+
+import (
+	"fmt"
+	"image"
+	_ "image/gif" // Decoders for thumbnails
+	_ "image/jpeg"
+	"image/png"
+	"io"
+	"os"
+	"path/filepath"
+	"time"
+
+	"golang.org/x/image/draw"
+	_ "golang.org/x/image/webp"
+)
+
+const (
+	// ThumbnailWidth and ThumbnailHeight bound the size of a thumbnail in
+	// pixels; the image's aspect ratio is kept
+	ThumbnailWidth  = 240
+	ThumbnailHeight = 180
+
+	// MaxDecodePixels is the largest image, in pixels, decoded to make a
+	// thumbnail; a small file can claim a huge size and exhaust memory
+	MaxDecodePixels = 40 * 1000 * 1000
+
+	thumbDir = "thumbs"
+)
+
+// Thumbnail returns a PNG of the image scaled down to fit width x height
+// pixels, downloading the image first if needed. Thumbnails are generated
+// once per size and removed along with their image.
+func (c *ImageCache) Thumbnail(imageURL string, width, height int) (string, error) {
+	src, err := c.Download(imageURL)
+	if err != nil {
+		return "", err
+	}
+	path := filepath.Join(c.cacheDir, thumbDir, fmt.Sprintf("%s-%dx%d.png", hashName(imageURL), width, height))
+	if thumb, err := os.Stat(path); err == nil {
+		if orig, err := os.Stat(src); err == nil && !thumb.ModTime().Before(orig.ModTime()) {
+			return path, nil
+		}
+	}
+
+	f, err := os.Open(src)
+	if err != nil {
+		return "", err
+	}
+	img, err := decodeBounded(f)
+	f.Close()
+	if err != nil {
+		return "", err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return "", err
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".thumb-*")
+	if err != nil {
+		return "", fmt.Errorf("failed to create thumbnail: %w", err)
+	}
+	defer os.Remove(tmp.Name()) // A no-op once renamed
+
+	err = png.Encode(tmp, scaleToFit(img, width, height))
+	if cerr := tmp.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to write thumbnail: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return "", fmt.Errorf("failed to write thumbnail: %w", err)
+	}
+	return path, nil
+}
+
+// decodeBounded decodes an image after checking from its header that it
+// is no larger than MaxDecodePixels
+func decodeBounded(f *os.File) (image.Image, error) {
+	cfg, _, err := image.DecodeConfig(f)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode image: %w", err)
+	}
+	if int64(cfg.Width)*int64(cfg.Height) > MaxDecodePixels {
+		return nil, fmt.Errorf("image is too large to decode: %dx%d pixels", cfg.Width, cfg.Height)
+	}
+	if _, err := f.Seek(0, io.SeekStart); err != nil {
+		return nil, err
+	}
+	img, _, err := image.Decode(f)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode image: %w", err)
+	}
+	return img, nil
+}
+
+// scaleToFit shrinks an image to fit width x height, keeping its aspect
+// ratio. Smaller images are returned as they are.
+func scaleToFit(img image.Image, width, height int) image.Image {
+	b := img.Bounds()
+	if b.Dx() <= width && b.Dy() <= height {
+		return img
+	}
+	w, h := width, b.Dy()*width/b.Dx()
+	if h > height {
+		w, h = b.Dx()*height/b.Dy(), height
+	}
+	dst := image.NewRGBA(image.Rect(0, 0, max(w, 1), max(h, 1)))
+	draw.CatmullRom.Scale(dst, dst.Bounds(), img, b, draw.Src, nil)
+	return dst
+}
+
+// removeThumbnails removes the thumbnails of every size of an image
+func (c *ImageCache) removeThumbnails(imageURL string) {
+	thumbs, _ := filepath.Glob(filepath.Join(c.cacheDir, thumbDir, hashName(imageURL)+"-*.png"))
+	for _, path := range thumbs {
+		os.Remove(path)
+	}
+}
+
+// cleanupThumbnails removes thumbnails not generated since cutoff; they
+// are cheap to make again
+func (c *ImageCache) cleanupThumbnails(cutoff time.Time) {
+	entries, err := os.ReadDir(filepath.Join(c.cacheDir, thumbDir))
+	if err != nil {
+		return
+	}
+	for _, entry := range entries {
+		if info, err := entry.Info(); err == nil && info.ModTime().Before(cutoff) {
+			os.Remove(filepath.Join(c.cacheDir, thumbDir, entry.Name()))
+		}
+	}
+}
@@ -8,50 +8,166 @@ import (
 )
 
 type Config struct {
-	Nostr   NostrConfig   `mapstructure:"nostr"`
-	Sync    SyncConfig    `mapstructure:"sync"`
-	Reading ReadingConfig `mapstructure:"reading"`
-	Display DisplayConfig `mapstructure:"display"`
-	Database DatabaseConfig `mapstructure:"database"`
+	Nostr    NostrConfig    `mapstructure:"nostr" yaml:"nostr"`
+	Sync     SyncConfig     `mapstructure:"sync" yaml:"sync"`
+	Reading  ReadingConfig  `mapstructure:"reading" yaml:"reading"`
+	Display  DisplayConfig  `mapstructure:"display" yaml:"display"`
+	Database DatabaseConfig `mapstructure:"database" yaml:"database"`
+	Logging  LoggingConfig  `mapstructure:"logging" yaml:"logging"`
+	Keys     KeysConfig     `mapstructure:"keys" yaml:"keys"`
+	Archive  ArchiveConfig  `mapstructure:"archive" yaml:"archive"`
+	Cache    CacheConfig    `mapstructure:"cache" yaml:"cache"`
+	Media    MediaConfig    `mapstructure:"media" yaml:"media"`
 }
 
 type NostrConfig struct {
-	NPUB         string              `mapstructure:"npub"`
-	NSEC         string              `mapstructure:"nsec"`
-	Relays       []string            `mapstructure:"relays"`
-	RemoteSigner RemoteSignerConfig  `mapstructure:"remote_signer"`
-	PlebSigner   PlebSignerConfig    `mapstructure:"pleb_signer"`
+	NPUB          string             `mapstructure:"npub" yaml:"npub"`
+	NSEC          string             `mapstructure:"nsec" yaml:"nsec"`
+	Relays        []string           `mapstructure:"relays" yaml:"relays"`
+	RelaySettings []RelayEntry       `mapstructure:"relay_settings" yaml:"relay_settings"`
+	RemoteSigner  RemoteSignerConfig `mapstructure:"remote_signer" yaml:"remote_signer"`
+	PlebSigner    PlebSignerConfig   `mapstructure:"pleb_signer" yaml:"pleb_signer"`
+	BookmarkSet   string             `mapstructure:"bookmark_set" yaml:"bookmark_set"`     // NIP-51 set name, "" for the kind 10003 list
+	WalletConnect string             `mapstructure:"wallet_connect" yaml:"wallet_connect"` // NIP-47 nostr+walletconnect:// URI
+	ZapAmount     int64              `mapstructure:"zap_amount" yaml:"zap_amount"`         // Default zap in sats
 }
 
+// RelayConfig holds the flags of a relay, see NostrConfig.RelaySetting
+type RelayConfig struct {
+	URL   string
+	Read  bool
+	Write bool
+	Auth  string // NIP-42: "always" | "ask" | "never"
+}
+
+// RelayEntry is a relay_settings entry. Flags it leaves out keep their
+// defaults: read, write and DefaultRelayAuth.
+type RelayEntry struct {
+	URL   string `mapstructure:"url" yaml:"url"`
+	Read  *bool  `mapstructure:"read" yaml:"read,omitempty"`
+	Write *bool  `mapstructure:"write" yaml:"write,omitempty"`
+	Auth  string `mapstructure:"auth" yaml:"auth,omitempty"`
+}
+
+// DefaultRelayAuth is the NIP-42 policy for relays without an explicit one
+const DefaultRelayAuth = "ask"
+
 type RemoteSignerConfig struct {
-	Enabled         bool   `mapstructure:"enabled"`
-	BunkerURL       string `mapstructure:"bunker_url"`
-	ConnectionToken string `mapstructure:"connection_token"`
+	Enabled         bool   `mapstructure:"enabled" yaml:"enabled"`
+	BunkerURL       string `mapstructure:"bunker_url" yaml:"bunker_url"`
+	ConnectionToken string `mapstructure:"connection_token" yaml:"connection_token"`
 }
 
 type PlebSignerConfig struct {
-	Enabled bool   `mapstructure:"enabled"`
-	KeyID   string `mapstructure:"key_id"` // Optional: specific key to use
+	Enabled bool   `mapstructure:"enabled" yaml:"enabled"`
+	KeyID   string `mapstructure:"key_id" yaml:"key_id"` // Optional: specific key to use
 }
 
 type SyncConfig struct {
-	Enabled          bool   `mapstructure:"enabled"`
-	AutoSyncInterval string `mapstructure:"auto_sync_interval"`
+	Enabled          bool   `mapstructure:"enabled" yaml:"enabled"`
+	AutoSyncInterval string `mapstructure:"auto_sync_interval" yaml:"auto_sync_interval"`
 }
 
 type ReadingConfig struct {
-	MarkReadBehavior   string `mapstructure:"mark_read_behavior"`
-	OrganizationMode   string `mapstructure:"organization_mode"`
+	MarkReadBehavior string `mapstructure:"mark_read_behavior" yaml:"mark_read_behavior"`
+	OrganizationMode string `mapstructure:"organization_mode" yaml:"organization_mode"`
 }
 
 type DisplayConfig struct {
-	Theme             string `mapstructure:"theme"`
-	FeedListWidth     int    `mapstructure:"feed_list_width"`
-	ArticleListWidth  int    `mapstructure:"article_list_width"`
+	Theme            string `mapstructure:"theme" yaml:"theme"`
+	FeedListWidth    int    `mapstructure:"feed_list_width" yaml:"feed_list_width"`
+	ArticleListWidth int    `mapstructure:"article_list_width" yaml:"article_list_width"`
+	ImageProtocol    string `mapstructure:"image_protocol" yaml:"image_protocol"` // "auto" | "kitty" | "sixel" | "iterm2" | "halfblocks"
+}
+
+// ArchiveConfig controls offline archives of full articles and their images
+type ArchiveConfig struct {
+	Starred   bool   `mapstructure:"starred" yaml:"starred"`       // Archive starred articles
+	ExportDir string `mapstructure:"export_dir" yaml:"export_dir"` // Where exports are written
+}
+
+// CacheConfig limits the image cache. Images of starred and archived
+// articles are never evicted.
+type CacheConfig struct {
+	MaxSizeMB  int `mapstructure:"max_size_mb" yaml:"max_size_mb"` // Least recently used images go first, 0 is unlimited
+	ExpireDays int `mapstructure:"expire_days" yaml:"expire_days"` // Images unused this long are removed, 0 keeps them
+}
+
+// MediaConfig lists the programs images and videos open in, as command
+// lines where {url} stands for the file or URL. The first one installed
+// is used; mpv is also remote-controlled to reuse its window.
+type MediaConfig struct {
+	ImageViewers []string `mapstructure:"image_viewers" yaml:"image_viewers"`
+	VideoPlayers []string `mapstructure:"video_players" yaml:"video_players"`
+}
+
+// DefaultImageViewers are tried in order when none are configured
+var DefaultImageViewers = []string{
+	"sxiv -b -g 800x600 {url}",
+	"feh --scale-down --auto-zoom --borderless --geometry 800x600 {url}",
+	"imv-wayland {url}",
+	"imv-x11 {url}",
+	"imv {url}",
+	"eog {url}",
+	"eom {url}",
+	"xdg-open {url}",
+}
+
+// DefaultVideoPlayers are tried in order when none are configured
+var DefaultVideoPlayers = []string{
+	"mpv --geometry=800x600 {url}",
+	"vlc --width=800 --height=600 {url}",
+	"mplayer {url}",
+	"xdg-open {url}",
 }
 
 type DatabaseConfig struct {
-	Path string `mapstructure:"path"`
+	Path string `mapstructure:"path" yaml:"path"`
+}
+
+// KeysConfig selects a key preset and overrides individual actions.
+// Each context maps an action name to its keys, e.g. reader: {close_image: ["x", "q"]}.
+type KeysConfig struct {
+	Preset   string              `mapstructure:"preset" yaml:"preset"` // "default" | "vim" | "emacs" | "arrows"
+	Global   map[string][]string `mapstructure:"global" yaml:"global,omitempty"`
+	Feeds    map[string][]string `mapstructure:"feeds" yaml:"feeds,omitempty"`
+	Articles map[string][]string `mapstructure:"articles" yaml:"articles,omitempty"`
+	Reader   map[string][]string `mapstructure:"reader" yaml:"reader,omitempty"`
+	Relays   map[string][]string `mapstructure:"relays" yaml:"relays,omitempty"`
+	Logs     map[string][]string `mapstructure:"logs" yaml:"logs,omitempty"`
+	Compose  map[string][]string `mapstructure:"compose" yaml:"compose,omitempty"`
+	Links    map[string][]string `mapstructure:"links" yaml:"links,omitempty"`
+	Gallery  map[string][]string `mapstructure:"gallery" yaml:"gallery,omitempty"`
+	Podcasts map[string][]string `mapstructure:"podcasts" yaml:"podcasts,omitempty"`
+}
+
+// Overrides returns the per-context overrides keyed by context name
+func (k *KeysConfig) Overrides() map[string]map[string][]string {
+	overrides := make(map[string]map[string][]string)
+	for name, actions := range map[string]map[string][]string{
+		"global":   k.Global,
+		"feeds":    k.Feeds,
+		"articles": k.Articles,
+		"reader":   k.Reader,
+		"relays":   k.Relays,
+		"logs":     k.Logs,
+		"compose":  k.Compose,
+		"links":    k.Links,
+		"gallery":  k.Gallery,
+		"podcasts": k.Podcasts,
+	} {
+		if len(actions) > 0 {
+			overrides[name] = actions
+		}
+	}
+	return overrides
+}
+
+type LoggingConfig struct {
+	Level      string `mapstructure:"level" yaml:"level"` // "debug" | "info" | "warn" | "error"
+	Path       string `mapstructure:"path" yaml:"path"`
+	MaxSizeMB  int    `mapstructure:"max_size_mb" yaml:"max_size_mb"`
+	MaxBackups int    `mapstructure:"max_backups" yaml:"max_backups"`
 }
 
 var DefaultRelays = []string{
@@ -62,6 +178,101 @@ var DefaultRelays = []string{
 	"wss://nostr-pub.wellorder.net",
 }
 
+// RelaySetting returns the flags for a relay, defaulting each one the
+// relay's entry leaves out
+func (n *NostrConfig) RelaySetting(url string) RelayConfig {
+	rc := RelayConfig{URL: url, Read: true, Write: true, Auth: DefaultRelayAuth}
+	for _, entry := range n.RelaySettings {
+		if entry.URL != url {
+			continue
+		}
+		if entry.Read != nil {
+			rc.Read = *entry.Read
+		}
+		if entry.Write != nil {
+			rc.Write = *entry.Write
+		}
+		if entry.Auth != "" {
+			rc.Auth = entry.Auth
+		}
+		break
+	}
+	return rc
+}
+
+// SetRelaySetting stores the flags for a relay, adding it to Relays if needed
+func (n *NostrConfig) SetRelaySetting(rc RelayConfig) {
+	found := false
+	for _, url := range n.Relays {
+		if url == rc.URL {
+			found = true
+			break
+		}
+	}
+	if !found {
+		n.Relays = append(n.Relays, rc.URL)
+	}
+
+	entry := RelayEntry{URL: rc.URL, Read: &rc.Read, Write: &rc.Write, Auth: rc.Auth}
+	for i := range n.RelaySettings {
+		if n.RelaySettings[i].URL == rc.URL {
+			n.RelaySettings[i] = entry
+			return
+		}
+	}
+	n.RelaySettings = append(n.RelaySettings, entry)
+}
+
+// RemoveRelay removes a relay and its flags
+func (n *NostrConfig) RemoveRelay(url string) {
+	relays := []string{}
+	for _, r := range n.Relays {
+		if r != url {
+			relays = append(relays, r)
+		}
+	}
+	n.Relays = relays
+
+	settings := []RelayEntry{}
+	for _, entry := range n.RelaySettings {
+		if entry.URL != url {
+			settings = append(settings, entry)
+		}
+	}
+	n.RelaySettings = settings
+}
+
+// RelayAuthPolicies returns the NIP-42 policy of every configured relay
+func (n *NostrConfig) RelayAuthPolicies() map[string]string {
+	policies := make(map[string]string, len(n.Relays))
+	for _, url := range n.Relays {
+		policies[url] = n.RelaySetting(url).Auth
+	}
+	return policies
+}
+
+// ReadRelays returns the relays used for queries
+func (n *NostrConfig) ReadRelays() []string {
+	var relays []string
+	for _, url := range n.Relays {
+		if n.RelaySetting(url).Read {
+			relays = append(relays, url)
+		}
+	}
+	return relays
+}
+
+// WriteRelays returns the relays events are published to
+func (n *NostrConfig) WriteRelays() []string {
+	var relays []string
+	for _, url := range n.Relays {
+		if n.RelaySetting(url).Write {
+			relays = append(relays, url)
+		}
+	}
+	return relays
+}
+
 func Load() (*Config, error) {
 	configDir, err := getConfigDir()
 	if err != nil {
@@ -76,6 +287,7 @@ func Load() (*Config, error) {
 	viper.SetDefault("nostr.relays", DefaultRelays)
 	viper.SetDefault("nostr.remote_signer.enabled", false)
 	viper.SetDefault("nostr.pleb_signer.enabled", false)
+	viper.SetDefault("nostr.zap_amount", 21)
 	viper.SetDefault("sync.enabled", true)
 	viper.SetDefault("sync.auto_sync_interval", "15m")
 	viper.SetDefault("reading.mark_read_behavior", "on-open")
@@ -83,9 +295,21 @@ func Load() (*Config, error) {
 	viper.SetDefault("display.theme", "default")
 	viper.SetDefault("display.feed_list_width", 30)
 	viper.SetDefault("display.article_list_width", 40)
-	
+	viper.SetDefault("display.image_protocol", "auto")
+
 	dbPath := filepath.Join(getDataDir(), "feeds.db")
 	viper.SetDefault("database.path", dbPath)
+	viper.SetDefault("logging.level", "info")
+	viper.SetDefault("logging.path", filepath.Join(getDataDir(), "nostrfeedz.log"))
+	viper.SetDefault("logging.max_size_mb", 5)
+	viper.SetDefault("logging.max_backups", 3)
+	viper.SetDefault("keys.preset", "default")
+	viper.SetDefault("archive.starred", true)
+	viper.SetDefault("archive.export_dir", filepath.Join(getDataDir(), "exports"))
+	viper.SetDefault("cache.max_size_mb", 500)
+	viper.SetDefault("cache.expire_days", 30)
+	viper.SetDefault("media.image_viewers", DefaultImageViewers)
+	viper.SetDefault("media.video_players", DefaultVideoPlayers)
 
 	// Read config file
 	if err := viper.ReadInConfig(); err != nil {
@@ -121,6 +345,11 @@ func Save(cfg *Config) error {
 	viper.Set("reading", cfg.Reading)
 	viper.Set("display", cfg.Display)
 	viper.Set("database", cfg.Database)
+	viper.Set("logging", cfg.Logging)
+	viper.Set("keys", cfg.Keys)
+	viper.Set("archive", cfg.Archive)
+	viper.Set("cache", cfg.Cache)
+	viper.Set("media", cfg.Media)
 
 	configPath := filepath.Join(configDir, "config.yaml")
 	return viper.WriteConfigAs(configPath)
@@ -134,6 +363,24 @@ func getConfigDir() (string, error) {
 	return filepath.Join(home, ".config", "nostrfeedz"), nil
 }
 
+// ThemesDir is where user themes (*.yaml, *.yml, *.toml) are loaded from
+func ThemesDir() string {
+	configDir, err := getConfigDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(configDir, "themes")
+}
+
+// GetImageCacheDir returns the directory downloaded images are kept in
+func GetImageCacheDir() string {
+	configDir, err := getConfigDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(configDir, "cache", "images")
+}
+
 func getDataDir() string {
 	home, _ := os.UserHomeDir()
 	return filepath.Join(home, ".local", "share", "nostrfeedz")
@@ -156,6 +403,13 @@ nostr:
     - "wss://relay.snort.social"
     - "wss://relay.nostr.band"
     - "wss://nostr-pub.wellorder.net"
+
+  # Per-relay flags (managed from the relay panel, press 'R')
+  # relay_settings:
+  #   - url: "wss://nos.lol"
+  #     read: true
+  #     write: false
+  #     auth: "ask"             # NIP-42 AUTH: "always" | "ask" | "never"
   
   # Remote Signer (NIP-46) - For desktop remote signers
   remote_signer:
@@ -168,6 +422,15 @@ nostr:
     enabled: false              # Set to true to use Pleb_Signer
     key_id: ""                  # Optional: specific key ID to use
 
+  # Starred articles are published as NIP-51 bookmarks: "" uses your
+  # bookmark list (kind 10003), a name uses a bookmark set (kind 30003)
+  bookmark_set: ""
+
+  # Zaps (NIP-57) are paid through a Nostr Wallet Connect (NIP-47) wallet.
+  # Paste the nostr+walletconnect:// URI from your wallet (Alby, Mutiny, ...)
+  wallet_connect: ""
+  zap_amount: 21                # Default zap in sats
+
 # Sync Settings
 sync:
   enabled: true
@@ -175,18 +438,62 @@ sync:
 
 # Reading Preferences
 reading:
-  mark_read_behavior: "on-open" # "on-open" | "after-10s" | "never"
+  mark_read_behavior: "on-open" # "on-open" | "after-10s" (any duration) | "on-scroll-end" | "never"
   organization_mode: "tags"     # "tags" | "categories"
 
 # Display
 display:
-  theme: "default"              # "default" | "dark" | "light"
+  theme: "default"              # "default" | "dark" | "light" | "dracula" | a file in ~/.config/nostrfeedz/themes
   feed_list_width: 30
   article_list_width: 40
+  image_protocol: "auto"        # "auto" | "kitty" | "sixel" | "iterm2" | "halfblocks" (colored blocks, works anywhere)
 
 # Database
 database:
   path: "~/.local/share/nostrfeedz/feeds.db"
+
+# Logging (view recent entries in the app with 'L')
+logging:
+  level: "info"                 # "debug" | "info" | "warn" | "error" (--debug overrides)
+  path: "~/.local/share/nostrfeedz/nostrfeedz.log"
+  max_size_mb: 5                # Rotate after this size
+  max_backups: 3                # Rotated files to keep
+
+# Offline archive: full articles with their images, kept out of cache cleanup.
+# Press 'A' on a feed to archive all of its articles, 'E' to export the archive
+archive:
+  starred: true                 # Archive starred articles
+  export_dir: "~/.local/share/nostrfeedz/exports"
+
+# Image cache (images of starred and archived articles are always kept)
+cache:
+  max_size_mb: 500              # Least recently viewed images are removed first
+  expire_days: 30               # Remove images not viewed for this long, 0 keeps them
+
+# External programs for images and videos, tried in order; {url} is the
+# file or URL. mpv is driven over its IPC socket so the next video plays
+# in the same window
+media:
+  image_viewers:
+    - "sxiv -b -g 800x600 {url}"
+    - "feh --scale-down --auto-zoom --borderless --geometry 800x600 {url}"
+    - "imv {url}"
+    - "eog {url}"
+    - "xdg-open {url}"
+  video_players:
+    - "mpv --geometry=800x600 {url}"
+    - "vlc --width=800 --height=600 {url}"
+    - "mplayer {url}"
+    - "xdg-open {url}"
+
+# Key bindings (press '?' in the app to see the active map)
+keys:
+  preset: "default"             # "default" (vim + arrows) | "vim" | "emacs" | "arrows"
+  # Override single actions per context: global, feeds, articles, reader, relays, logs, compose
+  # reader:
+  #   close_image: ["x", "backspace"]
+  # feeds:
+  #   sync: "ctrl+r"
 `
 
 	configPath := filepath.Join(configDir, "config.yaml")
@@ -205,3 +512,30 @@ func GetDatabasePath(cfg *Config) string {
 	}
 	return dbPath
 }
+
+// GetExportDir returns the directory exports are written to
+func GetExportDir(cfg *Config) string {
+	dir := cfg.Archive.ExportDir
+	if dir == "" {
+		dir = filepath.Join(getDataDir(), "exports")
+	}
+	// Expand ~ to home directory
+	if dir[0] == '~' {
+		home, _ := os.UserHomeDir()
+		dir = filepath.Join(home, dir[1:])
+	}
+	return dir
+}
+
+func GetLogPath(cfg *Config) string {
+	logPath := cfg.Logging.Path
+	if logPath == "" {
+		logPath = filepath.Join(getDataDir(), "nostrfeedz.log")
+	}
+	// Expand ~ to home directory
+	if logPath[0] == '~' {
+		home, _ := os.UserHomeDir()
+		logPath = filepath.Join(home, logPath[1:])
+	}
+	return logPath
+}
@@ -0,0 +1,152 @@
+package db
+
+import "time"
+
+// SetFeedArchive turns archiving of every item on or off for a feed
+func (db *DB) SetFeedArchive(feedID string, enabled bool) error {
+	_, err := db.conn.Exec("UPDATE feeds SET archive = ? WHERE id = ?", boolToInt(enabled), feedID)
+	return err
+}
+
+// archiveWanted selects the items that should be archived: those in
+// archived feeds, plus starred items when starred is set
+const archiveWanted = `(f.archive = 1 OR (? = 1 AND fi.is_favorite = 1))`
+
+// GetItemsToArchive returns up to limit items that should be archived but
+// are not yet, newest first
+func (db *DB) GetItemsToArchive(starred bool, limit int) ([]ArchivedItem, error) {
+	return db.queryArchived(`
+		SELECT fi.id, fi.feed_id, fi.guid, fi.title, COALESCE(fi.content, ''), COALESCE(fi.url, ''),
+		       COALESCE(fi.author, ''), fi.published_at, fi.is_read, fi.is_favorite,
+		       COALESCE(fi.full_content, ''), f.title, f.type, 0, 0
+		FROM feed_items fi
+		JOIN feeds f ON f.id = fi.feed_id
+		WHERE `+archiveWanted+`
+		  AND NOT EXISTS (SELECT 1 FROM archived_items a WHERE a.item_id = fi.id)
+		ORDER BY fi.published_at DESC
+		LIMIT ?
+	`, boolToInt(starred), limit)
+}
+
+// GetArchivedItems returns every archived item with its full content,
+// newest first
+func (db *DB) GetArchivedItems() ([]ArchivedItem, error) {
+	return db.queryArchived(`
+		SELECT fi.id, fi.feed_id, fi.guid, fi.title, COALESCE(fi.content, ''), COALESCE(fi.url, ''),
+		       COALESCE(fi.author, ''), fi.published_at, fi.is_read, fi.is_favorite,
+		       COALESCE(fi.full_content, ''), f.title, f.type, a.images, a.archived_at
+		FROM archived_items a
+		JOIN feed_items fi ON fi.id = a.item_id
+		JOIN feeds f ON f.id = fi.feed_id
+		ORDER BY fi.published_at DESC
+	`)
+}
+
+func (db *DB) queryArchived(query string, args ...interface{}) ([]ArchivedItem, error) {
+	rows, err := db.conn.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var items []ArchivedItem
+	for rows.Next() {
+		var item ArchivedItem
+		var publishedAt, archivedAt int64
+		var isRead, isFavorite int
+		if err := rows.Scan(&item.ID, &item.FeedID, &item.GUID, &item.Title, &item.Content,
+			&item.URL, &item.Author, &publishedAt, &isRead, &isFavorite,
+			&item.FullContent, &item.FeedTitle, &item.FeedType, &item.Images, &archivedAt); err != nil {
+			return nil, err
+		}
+		item.PublishedAt = time.Unix(publishedAt, 0)
+		item.IsRead = isRead == 1
+		item.IsFavorite = isFavorite == 1
+		item.ArchivedAt = time.Unix(archivedAt, 0)
+		items = append(items, item)
+	}
+	return items, rows.Err()
+}
+
+// GetStaleArchives returns the IDs of archived items that are no longer
+// wanted: unstarred, or their feed stopped being archived
+func (db *DB) GetStaleArchives(starred bool) ([]string, error) {
+	rows, err := db.conn.Query(`
+		SELECT a.item_id
+		FROM archived_items a
+		LEFT JOIN feed_items fi ON fi.id = a.item_id
+		LEFT JOIN feeds f ON f.id = fi.feed_id
+		WHERE fi.id IS NULL OR f.id IS NULL OR NOT `+archiveWanted+`
+	`, boolToInt(starred))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var ids []string
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+	return ids, rows.Err()
+}
+
+// SaveArchive records that an item was archived with its images
+func (db *DB) SaveArchive(itemID string, images int) error {
+	_, err := db.conn.Exec(`
+		INSERT INTO archived_items (item_id, images, archived_at)
+		VALUES (?, ?, ?)
+		ON CONFLICT(item_id) DO UPDATE SET images = excluded.images, archived_at = excluded.archived_at
+	`, itemID, images, time.Now().Unix())
+	return err
+}
+
+// DeleteArchive forgets that an item was archived
+func (db *DB) DeleteArchive(itemID string) error {
+	_, err := db.conn.Exec("DELETE FROM archived_items WHERE item_id = ?", itemID)
+	return err
+}
+
+// PinImages keeps images in the image cache for an item
+func (db *DB) PinImages(itemID string, urls []string) error {
+	tx, err := db.conn.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	for _, url := range urls {
+		if _, err := tx.Exec("INSERT OR IGNORE INTO pinned_images (url, item_id) VALUES (?, ?)", url, itemID); err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
+// UnpinImages releases the images pinned for an item
+func (db *DB) UnpinImages(itemID string) error {
+	_, err := db.conn.Exec("DELETE FROM pinned_images WHERE item_id = ?", itemID)
+	return err
+}
+
+// GetPinnedImages returns the URLs of all pinned images
+func (db *DB) GetPinnedImages() ([]string, error) {
+	rows, err := db.conn.Query("SELECT DISTINCT url FROM pinned_images")
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var urls []string
+	for rows.Next() {
+		var url string
+		if err := rows.Scan(&url); err != nil {
+			return nil, err
+		}
+		urls = append(urls, url)
+	}
+	return urls, rows.Err()
+}
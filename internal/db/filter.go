@@ -0,0 +1,334 @@
+package db
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ReadState restricts a filter to read or unread items
+type ReadState string
+
+const (
+	ReadAny    ReadState = ""
+	ReadUnread ReadState = "unread"
+	ReadRead   ReadState = "read"
+)
+
+// ItemFilter selects feed items across feeds. Empty fields match everything.
+type ItemFilter struct {
+	Feed     string // Feed ID or part of its title
+	Tag      string // Tag name
+	Category string // Category name, or "Uncategorized"
+	Author   string // Part of the author name
+	Since    time.Time
+	Until    time.Time
+	Keyword  string // Part of the title or content
+	Read     ReadState
+	Starred  bool
+	// Order by when the item was read instead of when it was published.
+	// Only items with a read time are returned.
+	RecentlyRead bool
+	// Only items left partway through, most recently opened first
+	InProgress bool
+	Limit      int // 0 returns up to 100 items, negative returns all
+}
+
+// ParseItemFilter parses a filter query such as
+//
+//	tag:nostr author:jack since:7d is:unread bitcoin
+//
+// Terms are feed:, tag:, category:, author:, since:, until: and is:
+// (unread, read, starred, reading). Dates are YYYY-MM-DD or a relative age
+// like 7d, 12h or 2w. Remaining words are matched against title and content.
+// Values containing spaces can be quoted: tag:"long reads".
+func ParseItemFilter(query string) (ItemFilter, error) {
+	var f ItemFilter
+	var keywords []string
+
+	for _, term := range splitQuery(query) {
+		key, value, ok := strings.Cut(term, ":")
+		if !ok || value == "" {
+			keywords = append(keywords, term)
+			continue
+		}
+
+		switch strings.ToLower(key) {
+		case "feed":
+			f.Feed = value
+		case "tag":
+			f.Tag = value
+		case "category", "cat":
+			f.Category = value
+		case "author":
+			f.Author = value
+		case "since", "after":
+			t, err := parseFilterTime(value)
+			if err != nil {
+				return f, err
+			}
+			f.Since = t
+		case "until", "before":
+			t, err := parseFilterTime(value)
+			if err != nil {
+				return f, err
+			}
+			f.Until = t
+		case "is":
+			switch strings.ToLower(value) {
+			case "unread":
+				f.Read = ReadUnread
+			case "read":
+				f.Read = ReadRead
+			case "starred", "favorite":
+				f.Starred = true
+			case "reading":
+				f.InProgress = true
+			default:
+				return f, fmt.Errorf("unknown is:%s (want unread, read, starred or reading)", value)
+			}
+		default:
+			// Not a filter term, e.g. a URL
+			keywords = append(keywords, term)
+		}
+	}
+
+	f.Keyword = strings.Join(keywords, " ")
+	return f, nil
+}
+
+// splitQuery splits on spaces, keeping double-quoted values together
+func splitQuery(query string) []string {
+	var terms []string
+	var current strings.Builder
+	quoted := false
+	for _, r := range query {
+		switch {
+		case r == '"':
+			quoted = !quoted
+		case r == ' ' && !quoted:
+			if current.Len() > 0 {
+				terms = append(terms, current.String())
+				current.Reset()
+			}
+		default:
+			current.WriteRune(r)
+		}
+	}
+	if current.Len() > 0 {
+		terms = append(terms, current.String())
+	}
+	return terms
+}
+
+// parseFilterTime accepts a date or an age relative to now
+func parseFilterTime(value string) (time.Time, error) {
+	if t, err := time.ParseInLocation("2006-01-02", value, time.Local); err == nil {
+		return t, nil
+	}
+
+	if len(value) > 1 {
+		n, err := strconv.Atoi(value[:len(value)-1])
+		if err == nil && n >= 0 {
+			var unit time.Duration
+			switch value[len(value)-1] {
+			case 'h':
+				unit = time.Hour
+			case 'd':
+				unit = 24 * time.Hour
+			case 'w':
+				unit = 7 * 24 * time.Hour
+			}
+			if unit > 0 {
+				return time.Now().Add(-time.Duration(n) * unit), nil
+			}
+		}
+	}
+
+	return time.Time{}, fmt.Errorf("invalid date %q (want YYYY-MM-DD or an age like 7d)", value)
+}
+
+// where builds the WHERE clause for a filter over feed_items fi joined to feeds f
+func (f ItemFilter) where() (string, []interface{}) {
+	conds := []string{"1 = 1"}
+	var args []interface{}
+
+	if f.Feed != "" {
+		conds = append(conds, "(f.id = ? OR f.title LIKE ?)")
+		args = append(args, f.Feed, "%"+f.Feed+"%")
+	}
+	if f.Tag != "" {
+		conds = append(conds, `EXISTS (
+			SELECT 1 FROM feed_tags ft JOIN tags t ON t.id = ft.tag_id
+			WHERE ft.feed_id = f.id AND t.name = ? COLLATE NOCASE)`)
+		args = append(args, f.Tag)
+	}
+	if strings.EqualFold(f.Category, "uncategorized") {
+		conds = append(conds, "(f.category_id IS NULL OR f.category_id = '')")
+	} else if f.Category != "" {
+		conds = append(conds, "f.category_id IN (SELECT id FROM categories WHERE name = ? COLLATE NOCASE)")
+		args = append(args, f.Category)
+	}
+	if f.Author != "" {
+		conds = append(conds, "fi.author LIKE ?")
+		args = append(args, "%"+f.Author+"%")
+	}
+	if !f.Since.IsZero() {
+		conds = append(conds, "fi.published_at >= ?")
+		args = append(args, f.Since.Unix())
+	}
+	if !f.Until.IsZero() {
+		conds = append(conds, "fi.published_at < ?")
+		args = append(args, f.Until.Unix())
+	}
+	if f.Keyword != "" {
+		conds = append(conds, "(fi.title LIKE ? OR fi.content LIKE ?)")
+		args = append(args, "%"+f.Keyword+"%", "%"+f.Keyword+"%")
+	}
+	switch f.Read {
+	case ReadUnread:
+		conds = append(conds, "fi.is_read = 0")
+	case ReadRead:
+		conds = append(conds, "fi.is_read = 1")
+	}
+	if f.Starred {
+		conds = append(conds, "fi.is_favorite = 1")
+	}
+	if f.RecentlyRead {
+		conds = append(conds, "fi.read_at IS NOT NULL")
+	}
+	if f.InProgress {
+		conds = append(conds, `EXISTS (
+			SELECT 1 FROM reading_progress rp
+			WHERE rp.item_id = fi.id AND rp.scroll_offset > 0 AND rp.percent < 100)`)
+	}
+
+	return strings.Join(conds, " AND "), args
+}
+
+// QueryFeedItems returns the items matching a filter, newest first
+func (db *DB) QueryFeedItems(f ItemFilter) ([]FeedItem, error) {
+	where, args := f.where()
+
+	order := "fi.published_at DESC"
+	if f.RecentlyRead {
+		order = "fi.read_at DESC"
+	} else if f.InProgress {
+		order = "(SELECT opened_at FROM reading_progress WHERE item_id = fi.id) DESC"
+	}
+	limit := f.Limit
+	if limit == 0 {
+		limit = 100
+	}
+
+	rows, err := db.conn.Query(fmt.Sprintf(`
+		SELECT fi.id, fi.feed_id, fi.guid, fi.title, COALESCE(fi.content, ''), COALESCE(fi.url, ''),
+		       COALESCE(fi.author, ''), fi.published_at, fi.is_read, fi.is_favorite,
+		       COALESCE(fi.thumbnail, ''), COALESCE(fi.video_id, ''), fi.created_at
+		FROM feed_items fi
+		JOIN feeds f ON f.id = fi.feed_id
+		WHERE %s
+		ORDER BY %s
+		LIMIT ?
+	`, where, order), append(args, limit)...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var items []FeedItem
+	for rows.Next() {
+		var item FeedItem
+		var publishedAt, createdAt int64
+		var isRead, isFavorite int
+		if err := rows.Scan(&item.ID, &item.FeedID, &item.GUID, &item.Title, &item.Content,
+			&item.URL, &item.Author, &publishedAt, &isRead, &isFavorite,
+			&item.Thumbnail, &item.VideoID, &createdAt); err != nil {
+			return nil, err
+		}
+		item.PublishedAt = time.Unix(publishedAt, 0)
+		item.CreatedAt = time.Unix(createdAt, 0)
+		item.IsRead = isRead == 1
+		item.IsFavorite = isFavorite == 1
+		items = append(items, item)
+	}
+	return items, rows.Err()
+}
+
+// CountUnreadItems returns how many unread items match a filter
+func (db *DB) CountUnreadItems(f ItemFilter) (int, error) {
+	f.Read = ReadUnread
+	where, args := f.where()
+
+	var count int
+	err := db.conn.QueryRow(fmt.Sprintf(`
+		SELECT COUNT(*)
+		FROM feed_items fi
+		JOIN feeds f ON f.id = fi.feed_id
+		WHERE %s
+	`, where), args...).Scan(&count)
+	return count, err
+}
+
+// MarkFilterRead marks every unread item matching a filter read and returns
+// how many items changed
+func (db *DB) MarkFilterRead(f ItemFilter) (int64, error) {
+	f.Read = ReadUnread
+	where, args := f.where()
+
+	result, err := db.conn.Exec(fmt.Sprintf(`
+		UPDATE feed_items SET is_read = 1
+		WHERE id IN (
+			SELECT fi.id
+			FROM feed_items fi
+			JOIN feeds f ON f.id = fi.feed_id
+			WHERE %s
+		)
+	`, where), args...)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+// Saved filters
+func (db *DB) CreateSavedFilter(sf *SavedFilter) error {
+	if _, err := ParseItemFilter(sf.Query); err != nil {
+		return err
+	}
+	_, err := db.conn.Exec(`
+		INSERT INTO saved_filters (id, name, query, created_at)
+		VALUES (?, ?, ?, ?)
+	`, sf.ID, sf.Name, sf.Query, sf.CreatedAt.Unix())
+	return err
+}
+
+func (db *DB) GetSavedFilters() ([]SavedFilter, error) {
+	rows, err := db.conn.Query(`
+		SELECT id, name, query, created_at
+		FROM saved_filters
+		ORDER BY name
+	`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var filters []SavedFilter
+	for rows.Next() {
+		var sf SavedFilter
+		var createdAt int64
+		if err := rows.Scan(&sf.ID, &sf.Name, &sf.Query, &createdAt); err != nil {
+			return nil, err
+		}
+		sf.CreatedAt = time.Unix(createdAt, 0)
+		filters = append(filters, sf)
+	}
+	return filters, rows.Err()
+}
+
+func (db *DB) DeleteSavedFilter(id string) error {
+	_, err := db.conn.Exec("DELETE FROM saved_filters WHERE id = ?", id)
+	return err
+}
@@ -0,0 +1,74 @@
+package db
+
+import (
+	"database/sql"
+	"time"
+)
+
+// SetFeedFullContent turns full article extraction on or off for a feed
+func (db *DB) SetFeedFullContent(feedID string, enabled bool) error {
+	_, err := db.conn.Exec("UPDATE feeds SET full_content = ? WHERE id = ?", boolToInt(enabled), feedID)
+	return err
+}
+
+// SetItemFullContent stores the article extracted from an item's page.
+// An empty content records that extraction was tried and found nothing,
+// so the item is not retried on every fetch.
+func (db *DB) SetItemFullContent(itemID, content string) error {
+	_, err := db.conn.Exec("UPDATE feed_items SET full_content = ? WHERE id = ?", content, itemID)
+	return err
+}
+
+// GetItemFullContent returns an item's extracted article, or "" if there is none
+func (db *DB) GetItemFullContent(itemID string) (string, error) {
+	var content sql.NullString
+	err := db.conn.QueryRow("SELECT full_content FROM feed_items WHERE id = ?", itemID).Scan(&content)
+	if err == sql.ErrNoRows {
+		return "", nil
+	}
+	return content.String, err
+}
+
+// GetItemsWithoutFullContent returns a feed's newest items that extraction
+// has not been tried on yet. Only ID, URL and title are filled in.
+func (db *DB) GetItemsWithoutFullContent(feedID string, limit int) ([]FeedItem, error) {
+	rows, err := db.conn.Query(`
+		SELECT id, url, title, published_at
+		FROM feed_items
+		WHERE feed_id = ? AND full_content IS NULL AND COALESCE(url, '') != ''
+		ORDER BY published_at DESC
+		LIMIT ?
+	`, feedID, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var items []FeedItem
+	for rows.Next() {
+		item := FeedItem{FeedID: feedID}
+		var publishedAt int64
+		if err := rows.Scan(&item.ID, &item.URL, &item.Title, &publishedAt); err != nil {
+			return nil, err
+		}
+		item.PublishedAt = time.Unix(publishedAt, 0)
+		items = append(items, item)
+	}
+	return items, rows.Err()
+}
+
+// LoadFullContent fills in the extracted article of items loaded by the
+// list queries, which leave it out
+func (db *DB) LoadFullContent(items []FeedItem) error {
+	for i := range items {
+		if items[i].FullContent != "" {
+			continue
+		}
+		content, err := db.GetItemFullContent(items[i].ID)
+		if err != nil {
+			return err
+		}
+		items[i].FullContent = content
+	}
+	return nil
+}
@@ -0,0 +1,142 @@
+package db
+
+import (
+	"database/sql"
+	"time"
+)
+
+// imagePinned holds for cached images (aliased c) that must survive
+// eviction: pinned by an archived item, or referenced by a starred one
+const imagePinned = `(EXISTS (SELECT 1 FROM pinned_images p WHERE p.url = c.url)
+	OR EXISTS (SELECT 1 FROM cached_image_items r JOIN feed_items fi ON fi.id = r.item_id
+	           WHERE r.url = c.url AND fi.is_favorite = 1))`
+
+// SaveCachedImage records a downloaded image, replacing what was known
+// about the URL
+func (db *DB) SaveCachedImage(img *CachedImage) error {
+	_, err := db.conn.Exec(`
+		INSERT INTO cached_images (url, path, size, content_type, last_access, created_at)
+		VALUES (?, ?, ?, ?, ?, ?)
+		ON CONFLICT(url) DO UPDATE SET path = excluded.path, size = excluded.size,
+			content_type = excluded.content_type, last_access = excluded.last_access
+	`, img.URL, img.Path, img.Size, img.ContentType, img.LastAccess.UnixMilli(), img.CreatedAt.Unix())
+	return err
+}
+
+// GetCachedImage returns what is known about a cached image, or nil if the
+// URL is not cached
+func (db *DB) GetCachedImage(url string) (*CachedImage, error) {
+	img := CachedImage{URL: url}
+	var contentType sql.NullString
+	var lastAccess, createdAt int64
+	err := db.conn.QueryRow(`
+		SELECT path, size, content_type, last_access, created_at
+		FROM cached_images WHERE url = ?
+	`, url).Scan(&img.Path, &img.Size, &contentType, &lastAccess, &createdAt)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	img.ContentType = contentType.String
+	img.LastAccess = time.UnixMilli(lastAccess)
+	img.CreatedAt = time.Unix(createdAt, 0)
+
+	rows, err := db.conn.Query("SELECT item_id FROM cached_image_items WHERE url = ? ORDER BY item_id", url)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		img.ItemIDs = append(img.ItemIDs, id)
+	}
+	return &img, rows.Err()
+}
+
+// TouchCachedImage records that a cached image was just used
+func (db *DB) TouchCachedImage(url string) error {
+	_, err := db.conn.Exec("UPDATE cached_images SET last_access = ? WHERE url = ?", time.Now().UnixMilli(), url)
+	return err
+}
+
+// DeleteCachedImage forgets a cached image. Its item references are kept,
+// so a starred item's image is pinned again when downloaded again.
+func (db *DB) DeleteCachedImage(url string) error {
+	_, err := db.conn.Exec("DELETE FROM cached_images WHERE url = ?", url)
+	return err
+}
+
+// AddImageReferences records that an item shows images
+func (db *DB) AddImageReferences(itemID string, urls []string) error {
+	tx, err := db.conn.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	for _, url := range urls {
+		if _, err := tx.Exec("INSERT OR IGNORE INTO cached_image_items (url, item_id) VALUES (?, ?)", url, itemID); err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
+// GetCachedImagesSize returns the total size of the cached images
+func (db *DB) GetCachedImagesSize() (int64, error) {
+	var size int64
+	err := db.conn.QueryRow("SELECT COALESCE(SUM(size), 0) FROM cached_images").Scan(&size)
+	return size, err
+}
+
+// GetCachedImagePaths returns the path of every cached image
+func (db *DB) GetCachedImagePaths() (map[string]bool, error) {
+	rows, err := db.conn.Query("SELECT path FROM cached_images")
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	paths := make(map[string]bool)
+	for rows.Next() {
+		var path string
+		if err := rows.Scan(&path); err != nil {
+			return nil, err
+		}
+		paths[path] = true
+	}
+	return paths, rows.Err()
+}
+
+// GetEvictableImages returns the cached images that are not pinned, least
+// recently used first. Item references are not loaded.
+func (db *DB) GetEvictableImages() ([]CachedImage, error) {
+	rows, err := db.conn.Query(`
+		SELECT c.url, c.path, c.size, COALESCE(c.content_type, ''), c.last_access, c.created_at
+		FROM cached_images c
+		WHERE NOT ` + imagePinned + `
+		ORDER BY c.last_access ASC, c.created_at ASC
+	`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var images []CachedImage
+	for rows.Next() {
+		var img CachedImage
+		var lastAccess, createdAt int64
+		if err := rows.Scan(&img.URL, &img.Path, &img.Size, &img.ContentType, &lastAccess, &createdAt); err != nil {
+			return nil, err
+		}
+		img.LastAccess = time.UnixMilli(lastAccess)
+		img.CreatedAt = time.Unix(createdAt, 0)
+		images = append(images, img)
+	}
+	return images, rows.Err()
+}
@@ -1,10 +1,14 @@
 package db
 
-import "time"
+import (
+	"path"
+	"strings"
+	"time"
+)
 
 type Feed struct {
 	ID             string
-	Type           string // RSS, NOSTR, NOSTR_VIDEO
+	Type           string // rss, youtube (channel or playlist link) or nostr
 	URL            string
 	NPUB           string
 	Title          string
@@ -12,6 +16,8 @@ type Feed struct {
 	LastFetchedAt  *time.Time
 	CategoryID     string
 	CreatedAt      time.Time
+	FullContent    bool // Extract the full article from each item's page
+	Archive        bool // Keep every item for offline reading, see ArchivedItem
 }
 
 type FeedItem struct {
@@ -28,6 +34,15 @@ type FeedItem struct {
 	Thumbnail   string
 	VideoID     string
 	CreatedAt   time.Time
+	// Main content extracted from the item's page; Content keeps what the
+	// feed shipped. List queries leave it empty, see GetItemFullContent.
+	FullContent string
+	// Files attached by the feed, saved by CreateFeedItem. List queries
+	// leave it empty, see GetEnclosures.
+	Enclosures []Enclosure
+	// Media RSS details of a video item, saved by CreateFeedItem. List
+	// queries leave it nil, see GetVideoMeta.
+	Video *VideoMeta
 }
 
 type Tag struct {
@@ -43,7 +58,107 @@ type Category struct {
 	SortOrder int
 }
 
+// SavedFilter is a named item filter query, see ParseItemFilter
+type SavedFilter struct {
+	ID        string
+	Name      string
+	Query     string
+	CreatedAt time.Time
+}
+
+// ArticleSocial is the cached social context of a Nostr article
+type ArticleSocial struct {
+	ItemID    string
+	Reactions int  // Kind 7 reactions other than "-"
+	Liked     bool // We reacted
+	Replies   []SocialReply
+	ZapCount  int
+	ZapSats   int64
+	FetchedAt time.Time
+}
+
+// SocialReply is a kind 1 reply to an article
+type SocialReply struct {
+	ID        string
+	PubKey    string
+	Content   string
+	CreatedAt time.Time
+}
+
+// ReadingProgress is how far an item has been read in the reader
+type ReadingProgress struct {
+	ItemID       string
+	ScrollOffset int // First visible line
+	Percent      int // Share of the article seen, 0-100
+	OpenedAt     time.Time
+}
+
+// InProgress reports whether the item was left partway through
+func (p ReadingProgress) InProgress() bool {
+	return p.ScrollOffset > 0 && p.Percent < 100
+}
+
 type Preference struct {
 	Key   string
 	Value string
 }
+
+// ArchivedItem is an item kept for offline reading: its full article and
+// every image it references, pinned in the image cache
+type ArchivedItem struct {
+	FeedItem
+	FeedTitle  string
+	FeedType   string
+	Images     int // Images downloaded and pinned
+	ArchivedAt time.Time
+}
+
+// CachedImage is a downloaded image in the image cache
+type CachedImage struct {
+	URL         string
+	Path        string
+	Size        int64
+	ContentType string
+	LastAccess  time.Time // Stored in milliseconds, to order the images of one article
+	CreatedAt   time.Time
+	ItemIDs     []string // Items referencing the image
+}
+
+// Enclosure is a file attached to a feed item, such as a podcast episode
+type Enclosure struct {
+	ItemID   string
+	URL      string
+	Type     string        // MIME type as the feed gave it, possibly ""
+	Length   int64         // Bytes, 0 if unknown
+	Duration time.Duration // 0 if unknown
+	Position time.Duration // Where playback was left
+}
+
+// IsAudio reports whether the enclosure is something to listen to
+func (e Enclosure) IsAudio() bool {
+	if strings.Contains(e.Type, "/") {
+		return strings.HasPrefix(e.Type, "audio/")
+	}
+	// No usable MIME type: go by the file name
+	switch strings.ToLower(path.Ext(strings.SplitN(e.URL, "?", 2)[0])) {
+	case ".mp3", ".m4a", ".aac", ".ogg", ".oga", ".opus", ".flac", ".wav":
+		return true
+	}
+	return false
+}
+
+// Episode is a podcast episode in the playback queue
+type Episode struct {
+	Enclosure
+	Title     string
+	FeedTitle string
+}
+
+// VideoMeta is what a video feed such as a YouTube channel's says about a
+// video in its media:group
+type VideoMeta struct {
+	ItemID      string
+	Thumbnail   string
+	Description string // Plain text
+	Views       int64  // 0 if the feed does not count them
+}
@@ -0,0 +1,123 @@
+package db
+
+import (
+	"database/sql"
+	"time"
+)
+
+// saveEnclosures stores an item's enclosures. They are attached to the
+// stored item with the same GUID, which is not item.ID when the item was
+// already known; positions of known enclosures are kept.
+func (db *DB) saveEnclosures(item *FeedItem) error {
+	for _, enc := range item.Enclosures {
+		_, err := db.conn.Exec(`
+			INSERT INTO enclosures (item_id, url, type, length, duration)
+			SELECT id, ?, ?, ?, ? FROM feed_items WHERE feed_id = ? AND guid = ?
+			ON CONFLICT(item_id, url) DO UPDATE SET
+				type = excluded.type, length = excluded.length, duration = excluded.duration
+		`, enc.URL, enc.Type, enc.Length, int64(enc.Duration.Seconds()), item.FeedID, item.GUID)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// GetEnclosures returns the files attached to an item in feed order
+func (db *DB) GetEnclosures(itemID string) ([]Enclosure, error) {
+	rows, err := db.conn.Query(`
+		SELECT url, type, length, duration, position
+		FROM enclosures WHERE item_id = ? ORDER BY rowid
+	`, itemID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var encs []Enclosure
+	for rows.Next() {
+		enc := Enclosure{ItemID: itemID}
+		var encType sql.NullString
+		var duration, position int64
+		if err := rows.Scan(&enc.URL, &encType, &enc.Length, &duration, &position); err != nil {
+			return nil, err
+		}
+		enc.Type = encType.String
+		enc.Duration = time.Duration(duration) * time.Second
+		enc.Position = time.Duration(position) * time.Second
+		encs = append(encs, enc)
+	}
+	return encs, rows.Err()
+}
+
+// GetEpisode returns an item's first audio enclosure, or nil if it has none
+func (db *DB) GetEpisode(itemID string) (*Enclosure, error) {
+	encs, err := db.GetEnclosures(itemID)
+	if err != nil {
+		return nil, err
+	}
+	for _, enc := range encs {
+		if enc.IsAudio() {
+			return &enc, nil
+		}
+	}
+	return nil, nil
+}
+
+// SaveEpisodePosition remembers where playback of an enclosure stopped,
+// and its duration once the player knows it
+func (db *DB) SaveEpisodePosition(itemID, url string, position, duration time.Duration) error {
+	_, err := db.conn.Exec(`
+		UPDATE enclosures SET position = ?,
+			duration = CASE WHEN ? > 0 THEN ? ELSE duration END
+		WHERE item_id = ? AND url = ?
+	`, int64(position.Seconds()), int64(duration.Seconds()), int64(duration.Seconds()), itemID, url)
+	return err
+}
+
+// EnqueueEpisode adds an enclosure to the end of the podcast queue. An
+// item already queued keeps its place.
+func (db *DB) EnqueueEpisode(itemID, url string) error {
+	_, err := db.conn.Exec(`
+		INSERT OR IGNORE INTO podcast_queue (item_id, url, sort_order)
+		SELECT ?, ?, COALESCE(MAX(sort_order), 0) + 1 FROM podcast_queue
+	`, itemID, url)
+	return err
+}
+
+// DequeueEpisode removes an item from the podcast queue
+func (db *DB) DequeueEpisode(itemID string) error {
+	_, err := db.conn.Exec("DELETE FROM podcast_queue WHERE item_id = ?", itemID)
+	return err
+}
+
+// GetPodcastQueue returns the queued episodes in play order
+func (db *DB) GetPodcastQueue() ([]Episode, error) {
+	rows, err := db.conn.Query(`
+		SELECT q.item_id, q.url, COALESCE(e.type, ''), COALESCE(e.length, 0),
+			COALESCE(e.duration, 0), COALESCE(e.position, 0), fi.title, f.title
+		FROM podcast_queue q
+		JOIN feed_items fi ON fi.id = q.item_id
+		JOIN feeds f ON f.id = fi.feed_id
+		LEFT JOIN enclosures e ON e.item_id = q.item_id AND e.url = q.url
+		ORDER BY q.sort_order
+	`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var queue []Episode
+	for rows.Next() {
+		var ep Episode
+		var duration, position int64
+		if err := rows.Scan(&ep.ItemID, &ep.URL, &ep.Type, &ep.Length, &duration, &position,
+			&ep.Title, &ep.FeedTitle); err != nil {
+			return nil, err
+		}
+		ep.Duration = time.Duration(duration) * time.Second
+		ep.Position = time.Duration(position) * time.Second
+		queue = append(queue, ep)
+	}
+	return queue, rows.Err()
+}
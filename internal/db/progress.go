@@ -0,0 +1,82 @@
+package db
+
+import (
+	"database/sql"
+	"strings"
+	"time"
+)
+
+// SaveReadingProgress stores how far an item has been read
+func (db *DB) SaveReadingProgress(p *ReadingProgress) error {
+	_, err := db.conn.Exec(`
+		INSERT INTO reading_progress (item_id, scroll_offset, percent, opened_at)
+		VALUES (?, ?, ?, ?)
+		ON CONFLICT(item_id) DO UPDATE SET
+			scroll_offset = excluded.scroll_offset, percent = excluded.percent,
+			opened_at = excluded.opened_at
+	`, p.ItemID, p.ScrollOffset, p.Percent, p.OpenedAt.Unix())
+	return err
+}
+
+// RecordOpened stores when an item was opened, keeping its scroll position
+func (db *DB) RecordOpened(itemID string, at time.Time) error {
+	_, err := db.conn.Exec(`
+		INSERT INTO reading_progress (item_id, scroll_offset, percent, opened_at)
+		VALUES (?, 0, 0, ?)
+		ON CONFLICT(item_id) DO UPDATE SET opened_at = excluded.opened_at
+	`, itemID, at.Unix())
+	return err
+}
+
+// GetReadingProgress returns an item's reading progress, or nil if it was never opened
+func (db *DB) GetReadingProgress(itemID string) (*ReadingProgress, error) {
+	p := ReadingProgress{ItemID: itemID}
+	var openedAt int64
+	err := db.conn.QueryRow(`
+		SELECT scroll_offset, percent, opened_at
+		FROM reading_progress
+		WHERE item_id = ?
+	`, itemID).Scan(&p.ScrollOffset, &p.Percent, &openedAt)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	p.OpenedAt = time.Unix(openedAt, 0)
+	return &p, nil
+}
+
+// GetReadingProgressFor returns the reading progress of the given items,
+// keyed by item ID. Items that were never opened are left out.
+func (db *DB) GetReadingProgressFor(itemIDs []string) (map[string]ReadingProgress, error) {
+	progress := make(map[string]ReadingProgress)
+	if len(itemIDs) == 0 {
+		return progress, nil
+	}
+
+	args := make([]interface{}, len(itemIDs))
+	for i, id := range itemIDs {
+		args[i] = id
+	}
+	rows, err := db.conn.Query(`
+		SELECT item_id, scroll_offset, percent, opened_at
+		FROM reading_progress
+		WHERE item_id IN (?`+strings.Repeat(", ?", len(itemIDs)-1)+`)
+	`, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var p ReadingProgress
+		var openedAt int64
+		if err := rows.Scan(&p.ItemID, &p.ScrollOffset, &p.Percent, &openedAt); err != nil {
+			return nil, err
+		}
+		p.OpenedAt = time.Unix(openedAt, 0)
+		progress[p.ItemID] = p
+	}
+	return progress, rows.Err()
+}
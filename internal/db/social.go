@@ -0,0 +1,82 @@
+package db
+
+import (
+	"database/sql"
+	"time"
+)
+
+// SaveArticleSocial replaces the cached social context of an item
+func (db *DB) SaveArticleSocial(s *ArticleSocial) error {
+	tx, err := db.conn.Begin()
+	if err != nil {
+		return err
+	}
+
+	if _, err := tx.Exec(`
+		INSERT INTO article_social (item_id, reactions, liked, zap_count, zap_sats, fetched_at)
+		VALUES (?, ?, ?, ?, ?, ?)
+		ON CONFLICT(item_id) DO UPDATE SET
+			reactions = excluded.reactions, liked = excluded.liked,
+			zap_count = excluded.zap_count, zap_sats = excluded.zap_sats,
+			fetched_at = excluded.fetched_at
+	`, s.ItemID, s.Reactions, boolToInt(s.Liked), s.ZapCount, s.ZapSats, s.FetchedAt.Unix()); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	if _, err := tx.Exec("DELETE FROM social_replies WHERE item_id = ?", s.ItemID); err != nil {
+		tx.Rollback()
+		return err
+	}
+	for _, r := range s.Replies {
+		if _, err := tx.Exec(`
+			INSERT OR IGNORE INTO social_replies (id, item_id, pubkey, content, created_at)
+			VALUES (?, ?, ?, ?, ?)
+		`, r.ID, s.ItemID, r.PubKey, r.Content, r.CreatedAt.Unix()); err != nil {
+			tx.Rollback()
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
+// GetArticleSocial returns the cached social context of an item, or nil if
+// it has never been fetched
+func (db *DB) GetArticleSocial(itemID string) (*ArticleSocial, error) {
+	s := ArticleSocial{ItemID: itemID}
+	var liked int
+	var fetchedAt int64
+	err := db.conn.QueryRow(`
+		SELECT reactions, liked, zap_count, zap_sats, fetched_at
+		FROM article_social WHERE item_id = ?
+	`, itemID).Scan(&s.Reactions, &liked, &s.ZapCount, &s.ZapSats, &fetchedAt)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	s.Liked = liked == 1
+	s.FetchedAt = time.Unix(fetchedAt, 0)
+
+	rows, err := db.conn.Query(`
+		SELECT id, pubkey, COALESCE(content, ''), created_at
+		FROM social_replies WHERE item_id = ?
+		ORDER BY created_at
+	`, itemID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var r SocialReply
+		var createdAt int64
+		if err := rows.Scan(&r.ID, &r.PubKey, &r.Content, &createdAt); err != nil {
+			return nil, err
+		}
+		r.CreatedAt = time.Unix(createdAt, 0)
+		s.Replies = append(s.Replies, r)
+	}
+	return &s, rows.Err()
+}
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"strings"
 	"time"
 
 	_ "github.com/mattn/go-sqlite3"
@@ -20,7 +21,9 @@ func New(dbPath string) (*DB, error) {
 		return nil, fmt.Errorf("failed to create database directory: %w", err)
 	}
 
-	conn, err := sql.Open("sqlite3", dbPath)
+	// Background work (archiving, the image cache index) writes alongside
+	// the UI, so wait for locks instead of failing
+	conn, err := sql.Open("sqlite3", dbPath+"?_busy_timeout=5000")
 	if err != nil {
 		return nil, fmt.Errorf("failed to open database: %w", err)
 	}
@@ -104,9 +107,152 @@ func (db *DB) migrate() error {
 		key TEXT PRIMARY KEY,
 		value TEXT NOT NULL
 	);
+
+	CREATE TABLE IF NOT EXISTS saved_filters (
+		id TEXT PRIMARY KEY,
+		name TEXT UNIQUE NOT NULL,
+		query TEXT NOT NULL,
+		created_at INTEGER NOT NULL
+	);
+
+	CREATE TABLE IF NOT EXISTS article_social (
+		item_id TEXT PRIMARY KEY,
+		reactions INTEGER DEFAULT 0,
+		liked INTEGER DEFAULT 0,
+		zap_count INTEGER DEFAULT 0,
+		zap_sats INTEGER DEFAULT 0,
+		fetched_at INTEGER NOT NULL,
+		FOREIGN KEY(item_id) REFERENCES feed_items(id) ON DELETE CASCADE
+	);
+
+	CREATE TABLE IF NOT EXISTS social_replies (
+		id TEXT NOT NULL,
+		item_id TEXT NOT NULL,
+		pubkey TEXT NOT NULL,
+		content TEXT,
+		created_at INTEGER NOT NULL,
+		PRIMARY KEY(item_id, id),
+		FOREIGN KEY(item_id) REFERENCES feed_items(id) ON DELETE CASCADE
+	);
+
+	CREATE TABLE IF NOT EXISTS archived_items (
+		item_id TEXT PRIMARY KEY,
+		images INTEGER DEFAULT 0,
+		archived_at INTEGER NOT NULL,
+		FOREIGN KEY(item_id) REFERENCES feed_items(id) ON DELETE CASCADE
+	);
+
+	CREATE TABLE IF NOT EXISTS pinned_images (
+		url TEXT NOT NULL,
+		item_id TEXT NOT NULL,
+		PRIMARY KEY(url, item_id),
+		FOREIGN KEY(item_id) REFERENCES feed_items(id) ON DELETE CASCADE
+	);
+
+	CREATE TABLE IF NOT EXISTS cached_images (
+		url TEXT PRIMARY KEY,
+		path TEXT NOT NULL,
+		size INTEGER DEFAULT 0,
+		content_type TEXT,
+		last_access INTEGER NOT NULL,
+		created_at INTEGER NOT NULL
+	);
+	CREATE INDEX IF NOT EXISTS idx_cached_images_last_access ON cached_images(last_access);
+
+	CREATE TABLE IF NOT EXISTS cached_image_items (
+		url TEXT NOT NULL,
+		item_id TEXT NOT NULL,
+		PRIMARY KEY(url, item_id),
+		FOREIGN KEY(item_id) REFERENCES feed_items(id) ON DELETE CASCADE
+	);
+
+	CREATE TABLE IF NOT EXISTS reading_progress (
+		item_id TEXT PRIMARY KEY,
+		scroll_offset INTEGER DEFAULT 0,
+		percent INTEGER DEFAULT 0,
+		opened_at INTEGER NOT NULL,
+		FOREIGN KEY(item_id) REFERENCES feed_items(id) ON DELETE CASCADE
+	);
+
+	CREATE TABLE IF NOT EXISTS enclosures (
+		item_id TEXT NOT NULL,
+		url TEXT NOT NULL,
+		type TEXT,
+		length INTEGER DEFAULT 0,
+		duration INTEGER DEFAULT 0,
+		position INTEGER DEFAULT 0,
+		PRIMARY KEY(item_id, url),
+		FOREIGN KEY(item_id) REFERENCES feed_items(id) ON DELETE CASCADE
+	);
+
+	CREATE TABLE IF NOT EXISTS podcast_queue (
+		item_id TEXT PRIMARY KEY,
+		url TEXT NOT NULL,
+		sort_order INTEGER NOT NULL,
+		FOREIGN KEY(item_id) REFERENCES feed_items(id) ON DELETE CASCADE
+	);
+
+	CREATE TABLE IF NOT EXISTS pending_stars (
+		item_id TEXT PRIMARY KEY,
+		starred INTEGER NOT NULL,
+		FOREIGN KEY(item_id) REFERENCES feed_items(id) ON DELETE CASCADE
+	);
+
+	CREATE TABLE IF NOT EXISTS video_meta (
+		item_id TEXT PRIMARY KEY,
+		thumbnail TEXT,
+		description TEXT,
+		views INTEGER DEFAULT 0,
+		FOREIGN KEY(item_id) REFERENCES feed_items(id) ON DELETE CASCADE
+	);
 	`
 
-	_, err := db.conn.Exec(schema)
+	if _, err := db.conn.Exec(schema); err != nil {
+		return err
+	}
+
+	// Columns added after the initial schema
+	if err := db.addColumn("feed_items", "read_at", "INTEGER"); err != nil {
+		return err
+	}
+	if err := db.addColumn("feeds", "full_content", "INTEGER DEFAULT 0"); err != nil {
+		return err
+	}
+	if err := db.addColumn("feed_items", "full_content", "TEXT"); err != nil {
+		return err
+	}
+	if err := db.addColumn("feeds", "archive", "INTEGER DEFAULT 0"); err != nil {
+		return err
+	}
+	_, err := db.conn.Exec("CREATE INDEX IF NOT EXISTS idx_feed_items_read_at ON feed_items(read_at)")
+	return err
+}
+
+// addColumn adds a column to an existing table unless it is already there
+func (db *DB) addColumn(table, column, definition string) error {
+	rows, err := db.conn.Query(fmt.Sprintf("PRAGMA table_info(%s)", table))
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var cid, notNull, pk int
+		var name, colType string
+		var defaultValue sql.NullString
+		if err := rows.Scan(&cid, &name, &colType, &notNull, &defaultValue, &pk); err != nil {
+			return err
+		}
+		if name == column {
+			return nil
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return err
+	}
+	rows.Close()
+
+	_, err = db.conn.Exec(fmt.Sprintf("ALTER TABLE %s ADD COLUMN %s %s", table, column, definition))
 	return err
 }
 
@@ -140,7 +286,8 @@ func (db *DB) CreateFeed(feed *Feed) error {
 
 func (db *DB) GetFeeds() ([]Feed, error) {
 	rows, err := db.conn.Query(`
-		SELECT id, type, url, npub, title, description, last_fetched_at, category_id, created_at
+		SELECT id, type, url, npub, title, description, last_fetched_at, category_id, created_at,
+		       COALESCE(full_content, 0), COALESCE(archive, 0)
 		FROM feeds ORDER BY title
 	`)
 	if err != nil {
@@ -153,7 +300,7 @@ func (db *DB) GetFeeds() ([]Feed, error) {
 		var feed Feed
 		var lastFetched sql.NullInt64
 		err := rows.Scan(&feed.ID, &feed.Type, &feed.URL, &feed.NPUB, &feed.Title,
-			&feed.Description, &lastFetched, &feed.CategoryID, new(int64))
+			&feed.Description, &lastFetched, &feed.CategoryID, new(int64), &feed.FullContent, &feed.Archive)
 		if err != nil {
 			return nil, err
 		}
@@ -167,10 +314,11 @@ func (db *DB) GetFeedByURL(url string) (*Feed, error) {
 	var feed Feed
 	var lastFetched sql.NullInt64
 	err := db.conn.QueryRow(`
-		SELECT id, type, url, npub, title, description, last_fetched_at, category_id, created_at
+		SELECT id, type, url, npub, title, description, last_fetched_at, category_id, created_at,
+		       COALESCE(full_content, 0), COALESCE(archive, 0)
 		FROM feeds WHERE url = ?
 	`, url).Scan(&feed.ID, &feed.Type, &feed.URL, &feed.NPUB, &feed.Title,
-		&feed.Description, &lastFetched, &feed.CategoryID, new(int64))
+		&feed.Description, &lastFetched, &feed.CategoryID, new(int64), &feed.FullContent, &feed.Archive)
 	
 	if err == sql.ErrNoRows {
 		return nil, nil
@@ -206,7 +354,13 @@ func (db *DB) CreateFeedItem(item *FeedItem) error {
 	`, item.ID, item.FeedID, item.GUID, item.Title, item.Content, item.URL, item.Author,
 		item.PublishedAt.Unix(), boolToInt(item.IsRead), boolToInt(item.IsFavorite),
 		item.Thumbnail, item.VideoID, item.CreatedAt.Unix())
-	return err
+	if err != nil {
+		return err
+	}
+	if err := db.saveVideoMeta(item); err != nil {
+		return err
+	}
+	return db.saveEnclosures(item)
 }
 
 func (db *DB) GetFeedItems(feedID string, limit int) ([]FeedItem, error) {
@@ -251,8 +405,13 @@ func (db *DB) GetFeedItems(feedID string, limit int) ([]FeedItem, error) {
 	return items, rows.Err()
 }
 
+// MarkItemRead sets an item's read flag and records when it was read
 func (db *DB) MarkItemRead(itemID string, isRead bool) error {
-	_, err := db.conn.Exec("UPDATE feed_items SET is_read = ? WHERE id = ?", boolToInt(isRead), itemID)
+	var readAt interface{}
+	if isRead {
+		readAt = time.Now().Unix()
+	}
+	_, err := db.conn.Exec("UPDATE feed_items SET is_read = ?, read_at = ? WHERE id = ?", boolToInt(isRead), readAt, itemID)
 	return err
 }
 
@@ -261,9 +420,109 @@ func (db *DB) MarkItemReadByGUID(guid string) error {
 	return err
 }
 
+// MarkItemsRead sets the read flag on many items in a single transaction
+func (db *DB) MarkItemsRead(itemIDs []string, isRead bool) error {
+	if len(itemIDs) == 0 {
+		return nil
+	}
+
+	tx, err := db.conn.Begin()
+	if err != nil {
+		return err
+	}
+	// Bulk marking is not reading, so only clear read_at when marking unread
+	query := "UPDATE feed_items SET is_read = 1 WHERE id = ?"
+	if !isRead {
+		query = "UPDATE feed_items SET is_read = 0, read_at = NULL WHERE id = ?"
+	}
+	stmt, err := tx.Prepare(query)
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+	defer stmt.Close()
+
+	for _, id := range itemIDs {
+		if _, err := stmt.Exec(id); err != nil {
+			tx.Rollback()
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
+// MarkFeedsRead marks every unread item in the given feeds read and returns
+// how many items changed
+func (db *DB) MarkFeedsRead(feedIDs []string) (int64, error) {
+	if len(feedIDs) == 0 {
+		return 0, nil
+	}
+
+	placeholders := make([]string, len(feedIDs))
+	args := make([]interface{}, len(feedIDs))
+	for i, id := range feedIDs {
+		placeholders[i] = "?"
+		args[i] = id
+	}
+
+	result, err := db.conn.Exec(fmt.Sprintf(
+		"UPDATE feed_items SET is_read = 1 WHERE is_read = 0 AND feed_id IN (%s)",
+		strings.Join(placeholders, ", ")), args...)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+// ToggleFavorite stars or unstars an item. The change is kept as pending
+// until it reaches the bookmark list, see GetPendingStars.
 func (db *DB) ToggleFavorite(itemID string) error {
-	_, err := db.conn.Exec("UPDATE feed_items SET is_favorite = NOT is_favorite WHERE id = ?", itemID)
-	return err
+	tx, err := db.conn.Begin()
+	if err != nil {
+		return err
+	}
+	if _, err := tx.Exec("UPDATE feed_items SET is_favorite = NOT is_favorite WHERE id = ?", itemID); err != nil {
+		tx.Rollback()
+		return err
+	}
+	if _, err := tx.Exec(`
+		INSERT OR REPLACE INTO pending_stars (item_id, starred)
+		SELECT id, is_favorite FROM feed_items WHERE id = ?
+	`, itemID); err != nil {
+		tx.Rollback()
+		return err
+	}
+	return tx.Commit()
+}
+
+// SetFavorites stars exactly the items whose GUID is in guids or whose URL
+// is in urls. Items without a URL cannot be bookmarked and keep their star,
+// as do items whose star has not been published yet.
+func (db *DB) SetFavorites(guids, urls []string) error {
+	tx, err := db.conn.Begin()
+	if err != nil {
+		return err
+	}
+	if _, err := tx.Exec(`
+		UPDATE feed_items SET is_favorite = 0
+		WHERE is_favorite = 1 AND COALESCE(url, '') != '' AND id NOT IN (SELECT item_id FROM pending_stars)
+	`); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	for query, values := range map[string][]string{
+		"UPDATE feed_items SET is_favorite = 1 WHERE guid = ? AND id NOT IN (SELECT item_id FROM pending_stars)": guids,
+		"UPDATE feed_items SET is_favorite = 1 WHERE url = ? AND id NOT IN (SELECT item_id FROM pending_stars)":  urls,
+	} {
+		for _, v := range values {
+			if _, err := tx.Exec(query, v); err != nil {
+				tx.Rollback()
+				return err
+			}
+		}
+	}
+	return tx.Commit()
 }
 
 // Helper functions
@@ -396,7 +655,8 @@ return &cat, nil
 func (db *DB) GetFeedsByCategory(categoryID string) ([]Feed, error) {
 rows, err := db.conn.Query(`
 SELECT id, type, COALESCE(url, ''), COALESCE(npub, ''), title, 
-       COALESCE(description, ''), COALESCE(category_id, ''), created_at
+       COALESCE(description, ''), COALESCE(category_id, ''), created_at,
+       COALESCE(full_content, 0), COALESCE(archive, 0)
 FROM feeds
 WHERE category_id = ?
 ORDER BY title
@@ -411,7 +671,7 @@ for rows.Next() {
 var feed Feed
 var createdAt int64
 if err := rows.Scan(&feed.ID, &feed.Type, &feed.URL, &feed.NPUB, &feed.Title,
-&feed.Description, &feed.CategoryID, &createdAt); err != nil {
+&feed.Description, &feed.CategoryID, &createdAt, &feed.FullContent, &feed.Archive); err != nil {
 return nil, err
 }
 feed.CreatedAt = time.Unix(createdAt, 0)
@@ -423,7 +683,8 @@ return feeds, rows.Err()
 func (db *DB) GetFeedsByTag(tagID string) ([]Feed, error) {
 rows, err := db.conn.Query(`
 SELECT f.id, f.type, COALESCE(f.url, ''), COALESCE(f.npub, ''), f.title,
-       COALESCE(f.description, ''), COALESCE(f.category_id, ''), f.created_at
+       COALESCE(f.description, ''), COALESCE(f.category_id, ''), f.created_at,
+       COALESCE(f.full_content, 0), COALESCE(f.archive, 0)
 FROM feeds f
 JOIN feed_tags ft ON f.id = ft.feed_id
 WHERE ft.tag_id = ?
@@ -439,7 +700,7 @@ for rows.Next() {
 var feed Feed
 var createdAt int64
 if err := rows.Scan(&feed.ID, &feed.Type, &feed.URL, &feed.NPUB, &feed.Title,
-&feed.Description, &feed.CategoryID, &createdAt); err != nil {
+&feed.Description, &feed.CategoryID, &createdAt, &feed.FullContent, &feed.Archive); err != nil {
 return nil, err
 }
 feed.CreatedAt = time.Unix(createdAt, 0)
@@ -611,7 +872,8 @@ return counts, rows.Err()
 // GetUncategorizedFeeds returns all feeds without a category
 func (db *DB) GetUncategorizedFeeds() ([]Feed, error) {
 rows, err := db.conn.Query(`
-SELECT id, type, url, npub, title, description, last_fetched_at, category_id, created_at
+SELECT id, type, url, npub, title, description, last_fetched_at, category_id, created_at,
+       COALESCE(full_content, 0), COALESCE(archive, 0)
 FROM feeds
 WHERE category_id IS NULL OR category_id = ''
 ORDER BY title
@@ -637,6 +899,8 @@ err := rows.Scan(
 &lastFetched,
 &categoryID,
 &createdAt,
+&feed.FullContent,
+&feed.Archive,
 )
 if err != nil {
 return nil, err
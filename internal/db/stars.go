@@ -0,0 +1,33 @@
+package db
+
+// GetPendingStars returns the items starred or unstarred since the bookmark
+// list was last published. Only ID, GUID, URL and IsFavorite are filled in.
+func (db *DB) GetPendingStars() ([]FeedItem, error) {
+	rows, err := db.conn.Query(`
+		SELECT fi.id, fi.guid, COALESCE(fi.url, ''), p.starred
+		FROM pending_stars p JOIN feed_items fi ON fi.id = p.item_id
+	`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var items []FeedItem
+	for rows.Next() {
+		var item FeedItem
+		var starred int
+		if err := rows.Scan(&item.ID, &item.GUID, &item.URL, &starred); err != nil {
+			return nil, err
+		}
+		item.IsFavorite = starred == 1
+		items = append(items, item)
+	}
+	return items, rows.Err()
+}
+
+// ClearPendingStar records that an item's star reached the bookmark list.
+// A change made again since then stays pending.
+func (db *DB) ClearPendingStar(itemID string, starred bool) error {
+	_, err := db.conn.Exec("DELETE FROM pending_stars WHERE item_id = ? AND starred = ?", itemID, boolToInt(starred))
+	return err
+}
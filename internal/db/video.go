@@ -0,0 +1,37 @@
+package db
+
+import "database/sql"
+
+// saveVideoMeta stores an item's video details, attached like enclosures
+// to the stored item with the same GUID. Known videos get their view count
+// and description refreshed.
+func (db *DB) saveVideoMeta(item *FeedItem) error {
+	if item.Video == nil {
+		return nil
+	}
+	_, err := db.conn.Exec(`
+		INSERT INTO video_meta (item_id, thumbnail, description, views)
+		SELECT id, ?, ?, ? FROM feed_items WHERE feed_id = ? AND guid = ?
+		ON CONFLICT(item_id) DO UPDATE SET
+			thumbnail = excluded.thumbnail, description = excluded.description, views = excluded.views
+	`, item.Video.Thumbnail, item.Video.Description, item.Video.Views, item.FeedID, item.GUID)
+	return err
+}
+
+// GetVideoMeta returns an item's video details, or nil if it is not a video
+func (db *DB) GetVideoMeta(itemID string) (*VideoMeta, error) {
+	meta := &VideoMeta{ItemID: itemID}
+	var thumbnail, description sql.NullString
+	err := db.conn.QueryRow(`
+		SELECT thumbnail, description, views FROM video_meta WHERE item_id = ?
+	`, itemID).Scan(&thumbnail, &description, &meta.Views)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	meta.Thumbnail = thumbnail.String
+	meta.Description = description.String
+	return meta, nil
+}
@@ -0,0 +1,298 @@
+// Package export writes articles to files for reading elsewhere
+package export
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/plebone/nostrfeedz-cli/internal/db"
+	"github.com/plebone/nostrfeedz-cli/internal/feed"
+	"github.com/yuin/goldmark"
+	"github.com/yuin/goldmark/extension"
+	goldmarkhtml "github.com/yuin/goldmark/renderer/html"
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+)
+
+// Article is one article to export
+type Article struct {
+	Title     string
+	Author    string
+	Feed      string
+	URL       string
+	Published time.Time
+	Content   string // HTML or markdown
+}
+
+// FromItem builds an export article from a feed item, preferring the
+// article extracted from its page over what the feed shipped
+func FromItem(item db.FeedItem, feedTitle string) Article {
+	content := item.Content
+	if item.FullContent != "" {
+		content = item.FullContent
+	}
+	return Article{
+		Title:     item.Title,
+		Author:    item.Author,
+		Feed:      feedTitle,
+		URL:       item.URL,
+		Published: item.PublishedAt,
+		Content:   content,
+	}
+}
+
+// Load builds export articles from feed items, loading what the list
+// queries leave out: extracted articles and feed titles
+func Load(database *db.DB, items []db.FeedItem) ([]Article, error) {
+	if err := database.LoadFullContent(items); err != nil {
+		return nil, err
+	}
+	feeds, err := database.GetFeeds()
+	if err != nil {
+		return nil, err
+	}
+	titles := make(map[string]string, len(feeds))
+	for _, f := range feeds {
+		titles[f.ID] = f.Title
+	}
+
+	articles := make([]Article, len(items))
+	for i, item := range items {
+		articles[i] = FromItem(item, titles[item.FeedID])
+	}
+	return articles, nil
+}
+
+// Images finds downloaded copies of images, see cache.ImageCache
+type Images interface {
+	GetCached(imageURL string) (string, error)
+}
+
+// Feeds mix HTML into markdown and summaries, so raw HTML is kept
+var markdown = goldmark.New(
+	goldmark.WithExtensions(extension.GFM),
+	goldmark.WithRendererOptions(goldmarkhtml.WithUnsafe()),
+)
+
+// body parses an article's content as HTML, converting markdown first, and
+// drops what has no place in a saved copy
+func body(a Article) ([]*html.Node, error) {
+	content := a.Content
+	if !feed.IsHTML(content) {
+		var buf bytes.Buffer
+		if err := markdown.Convert([]byte(content), &buf); err != nil {
+			return nil, fmt.Errorf("failed to convert markdown: %w", err)
+		}
+		content = buf.String()
+	}
+
+	context := &html.Node{Type: html.ElementNode, Data: "div", DataAtom: atom.Div}
+	nodes, err := html.ParseFragment(strings.NewReader(content), context)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse content: %w", err)
+	}
+
+	base, _ := url.Parse(a.URL)
+	for _, n := range nodes {
+		clean(n, base)
+	}
+	return nodes, nil
+}
+
+// clean removes scripts and styles, turns embeds into links and makes
+// links and image sources absolute
+func clean(n *html.Node, base *url.URL) {
+	for c := n.FirstChild; c != nil; {
+		next := c.NextSibling
+		if c.Type == html.ElementNode {
+			switch c.DataAtom {
+			case atom.Script, atom.Style, atom.Noscript, atom.Form, atom.Button, atom.Input:
+				n.RemoveChild(c)
+			case atom.Iframe, atom.Video, atom.Audio, atom.Object, atom.Embed:
+				if src := resolve(base, attr(c, "src")); src != "" {
+					n.InsertBefore(linkParagraph(src), c)
+				}
+				n.RemoveChild(c)
+			default:
+				clean(c, base)
+			}
+		}
+		c = next
+	}
+	if n.Type != html.ElementNode {
+		return
+	}
+
+	for i, a := range n.Attr {
+		if a.Key == "href" || a.Key == "src" {
+			if abs := resolve(base, a.Val); abs != "" {
+				n.Attr[i].Val = abs
+			}
+		}
+	}
+	// Lazy-loaded images keep the real source in a data attribute
+	if n.DataAtom == atom.Img {
+		for _, name := range []string{"data-src", "data-original", "data-lazy-src"} {
+			if src := resolve(base, attr(n, name)); src != "" {
+				setAttr(n, "src", src)
+				break
+			}
+		}
+	}
+	// Classes, styles and handlers belong to the original page
+	attrs := n.Attr[:0]
+	for _, a := range n.Attr {
+		if a.Namespace == "" && !strings.HasPrefix(a.Key, "on") && !strings.HasPrefix(a.Key, "data-") &&
+			a.Key != "class" && a.Key != "style" && a.Key != "id" && !strings.Contains(a.Key, ":") {
+			attrs = append(attrs, a)
+		}
+	}
+	n.Attr = attrs
+}
+
+// ImageURLs returns the absolute http(s) URLs of the images in an
+// article's content, in order and without duplicates
+func ImageURLs(a Article) []string {
+	nodes, err := body(a)
+	if err != nil {
+		return nil
+	}
+	var urls []string
+	seen := make(map[string]bool)
+	for _, n := range nodes {
+		walkImages(n, func(img *html.Node) {
+			src := attr(img, "src")
+			if (strings.HasPrefix(src, "http://") || strings.HasPrefix(src, "https://")) && !seen[src] {
+				seen[src] = true
+				urls = append(urls, src)
+			}
+		})
+	}
+	return urls
+}
+
+func walkImages(n *html.Node, fn func(*html.Node)) {
+	if n.Type == html.ElementNode && n.DataAtom == atom.Img {
+		fn(n)
+	}
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		walkImages(c, fn)
+	}
+}
+
+// image is a cached image ready to embed
+type image struct {
+	name string // File name inside an EPUB
+	mime string
+	data []byte
+}
+
+// loadImage reads the cached copy of an image, or returns nil if there is
+// none or it is not an image
+func loadImage(images Images, src string) *image {
+	if images == nil {
+		return nil
+	}
+	cached, err := images.GetCached(src)
+	if err != nil {
+		return nil
+	}
+	data, err := os.ReadFile(cached)
+	if err != nil {
+		return nil
+	}
+
+	mime := http.DetectContentType(data)
+	ext := ""
+	switch {
+	case strings.HasPrefix(mime, "image/"):
+		ext = "." + strings.TrimPrefix(strings.TrimPrefix(mime, "image/"), "x-")
+	case strings.EqualFold(path.Ext(cached), ".svg") && bytes.Contains(data, []byte("<svg")):
+		mime, ext = "image/svg+xml", ".svg"
+	default:
+		return nil
+	}
+	if ext == ".jpeg" {
+		ext = ".jpg"
+	}
+
+	hash := sha256.Sum256([]byte(src))
+	return &image{name: "images/" + hex.EncodeToString(hash[:8]) + ext, mime: mime, data: data}
+}
+
+// render serializes nodes. The output is also well-formed XHTML: void
+// elements are self-closed and text is escaped with numeric entities.
+func render(nodes []*html.Node) (string, error) {
+	var buf bytes.Buffer
+	for _, n := range nodes {
+		if err := html.Render(&buf, n); err != nil {
+			return "", err
+		}
+	}
+	return buf.String(), nil
+}
+
+// meta is the byline shown under an article's title
+func meta(a Article) string {
+	var parts []string
+	if a.Feed != "" {
+		parts = append(parts, a.Feed)
+	}
+	if a.Author != "" {
+		parts = append(parts, a.Author)
+	}
+	if !a.Published.IsZero() && a.Published.Unix() > 0 {
+		parts = append(parts, a.Published.Format("January 2, 2006"))
+	}
+	return strings.Join(parts, " · ")
+}
+
+func resolve(base *url.URL, ref string) string {
+	ref = strings.TrimSpace(ref)
+	if ref == "" || strings.HasPrefix(ref, "#") || strings.HasPrefix(ref, "data:") {
+		return ""
+	}
+	u, err := url.Parse(ref)
+	if err != nil || u.Scheme == "javascript" {
+		return ""
+	}
+	if base != nil && base.Scheme != "" && !u.IsAbs() {
+		u = base.ResolveReference(u)
+	}
+	return u.String()
+}
+
+func linkParagraph(href string) *html.Node {
+	p := &html.Node{Type: html.ElementNode, Data: "p", DataAtom: atom.P}
+	a := &html.Node{Type: html.ElementNode, Data: "a", DataAtom: atom.A, Attr: []html.Attribute{{Key: "href", Val: href}}}
+	a.AppendChild(&html.Node{Type: html.TextNode, Data: "▶ " + href})
+	p.AppendChild(a)
+	return p
+}
+
+func attr(n *html.Node, key string) string {
+	for _, a := range n.Attr {
+		if a.Key == key {
+			return a.Val
+		}
+	}
+	return ""
+}
+
+func setAttr(n *html.Node, key, val string) {
+	for i, a := range n.Attr {
+		if a.Key == key {
+			n.Attr[i].Val = val
+			return
+		}
+	}
+	n.Attr = append(n.Attr, html.Attribute{Key: key, Val: val})
+}
@@ -0,0 +1,210 @@
+package export
+
+import (
+	"archive/zip"
+	"crypto/rand"
+	"fmt"
+	"html"
+	"io"
+	"strings"
+	"time"
+
+	nethtml "golang.org/x/net/html"
+)
+
+const containerXML = `<?xml version="1.0" encoding="UTF-8"?>
+<container version="1.0" xmlns="urn:oasis:names:tc:opendocument:xmlns:container">
+  <rootfiles>
+    <rootfile full-path="OEBPS/content.opf" media-type="application/oebps-package+xml"/>
+  </rootfiles>
+</container>
+`
+
+// WriteEPUB writes articles as an EPUB 3 book with one chapter per article
+// and a table of contents. Cached images are packaged into the book;
+// images that were never downloaded become links.
+func WriteEPUB(w io.Writer, title string, articles []Article, images Images) error {
+	zw := zip.NewWriter(w)
+
+	// The mimetype must come first, uncompressed
+	mimetype, err := zw.CreateHeader(&zip.FileHeader{Name: "mimetype", Method: zip.Store})
+	if err != nil {
+		return err
+	}
+	if _, err := io.WriteString(mimetype, "application/epub+zip"); err != nil {
+		return err
+	}
+
+	files := map[string]string{
+		"META-INF/container.xml": containerXML,
+		"OEBPS/style.css":        stylesheet,
+	}
+	order := []string{"META-INF/container.xml", "OEBPS/style.css"}
+	add := func(name, content string) {
+		files[name] = content
+		order = append(order, name)
+	}
+
+	var packed []*image
+	seen := make(map[string]bool)
+	chapters := make([]string, len(articles))
+	for i, a := range articles {
+		nodes, err := body(a)
+		if err != nil {
+			return fmt.Errorf("%s: %w", a.Title, err)
+		}
+		for _, n := range nodes {
+			imagesToEPUB(n, images, func(img *image) {
+				if !seen[img.name] {
+					seen[img.name] = true
+					packed = append(packed, img)
+				}
+			})
+		}
+		content, err := render(nodes)
+		if err != nil {
+			return err
+		}
+
+		chapters[i] = fmt.Sprintf("article-%03d.xhtml", i+1)
+		add("OEBPS/"+chapters[i], xhtml(a.Title, fmt.Sprintf("<h1>%s</h1>\n%s%s", html.EscapeString(a.Title), metaLine(a), content)))
+	}
+
+	id := bookID()
+	add("OEBPS/nav.xhtml", navXHTML(title, articles, chapters))
+	add("OEBPS/toc.ncx", tocNCX(id, title, articles, chapters))
+	add("OEBPS/content.opf", contentOPF(id, title, chapters, packed))
+
+	for _, name := range order {
+		f, err := zw.Create(name)
+		if err != nil {
+			return err
+		}
+		if _, err := io.WriteString(f, files[name]); err != nil {
+			return err
+		}
+	}
+	for _, img := range packed {
+		f, err := zw.Create("OEBPS/" + img.name)
+		if err != nil {
+			return err
+		}
+		if _, err := f.Write(img.data); err != nil {
+			return err
+		}
+	}
+	return zw.Close()
+}
+
+// imagesToEPUB points images at their packaged copies. Images that are not
+// cached are replaced by a link, since readers do not load remote images.
+func imagesToEPUB(n *nethtml.Node, images Images, pack func(*image)) {
+	for c := n.FirstChild; c != nil; {
+		next := c.NextSibling
+		imagesToEPUB(c, images, pack)
+		c = next
+	}
+	if n.Type != nethtml.ElementNode || n.Data != "img" {
+		return
+	}
+
+	src := attr(n, "src")
+	if img := loadImage(images, src); img != nil {
+		setAttr(n, "src", img.name)
+		if attr(n, "alt") == "" {
+			setAttr(n, "alt", "")
+		}
+		pack(img)
+		return
+	}
+	if n.Parent != nil {
+		label := attr(n, "alt")
+		if label == "" {
+			label = "image"
+		}
+		link := linkParagraph(src)
+		link.FirstChild.FirstChild.Data = "🖼 " + label
+		if src == "" {
+			link = &nethtml.Node{Type: nethtml.TextNode, Data: label}
+		}
+		n.Parent.InsertBefore(link, n)
+		n.Parent.RemoveChild(n)
+	}
+}
+
+func xhtml(title, content string) string {
+	return `<?xml version="1.0" encoding="UTF-8"?>
+<!DOCTYPE html>
+<html xmlns="http://www.w3.org/1999/xhtml" xmlns:epub="http://www.idpf.org/2007/ops" lang="en" xml:lang="en">
+<head>
+<title>` + html.EscapeString(title) + `</title>
+<link rel="stylesheet" type="text/css" href="style.css"/>
+</head>
+<body>
+` + content + `
+</body>
+</html>
+`
+}
+
+func navXHTML(title string, articles []Article, chapters []string) string {
+	var s strings.Builder
+	fmt.Fprintf(&s, "<nav epub:type=\"toc\" id=\"toc\">\n<h1>%s</h1>\n<ol>\n", html.EscapeString(title))
+	for i, a := range articles {
+		fmt.Fprintf(&s, "<li><a href=\"%s\">%s</a></li>\n", chapters[i], html.EscapeString(a.Title))
+	}
+	s.WriteString("</ol>\n</nav>")
+	return xhtml(title, s.String())
+}
+
+// tocNCX is the EPUB 2 table of contents, still read by older e-readers
+func tocNCX(id, title string, articles []Article, chapters []string) string {
+	var s strings.Builder
+	s.WriteString(`<?xml version="1.0" encoding="UTF-8"?>
+<ncx xmlns="http://www.daisy.org/z3986/2005/ncx/" version="2005-1">
+<head>
+`)
+	fmt.Fprintf(&s, "<meta name=\"dtb:uid\" content=\"%s\"/>\n</head>\n", id)
+	fmt.Fprintf(&s, "<docTitle><text>%s</text></docTitle>\n<navMap>\n", html.EscapeString(title))
+	for i, a := range articles {
+		fmt.Fprintf(&s, "<navPoint id=\"nav-%d\" playOrder=\"%d\"><navLabel><text>%s</text></navLabel><content src=\"%s\"/></navPoint>\n",
+			i+1, i+1, html.EscapeString(a.Title), chapters[i])
+	}
+	s.WriteString("</navMap>\n</ncx>\n")
+	return s.String()
+}
+
+func contentOPF(id, title string, chapters []string, packed []*image) string {
+	var s strings.Builder
+	s.WriteString(`<?xml version="1.0" encoding="UTF-8"?>
+<package xmlns="http://www.idpf.org/2007/opf" version="3.0" unique-identifier="book-id">
+<metadata xmlns:dc="http://purl.org/dc/elements/1.1/">
+`)
+	fmt.Fprintf(&s, "<dc:identifier id=\"book-id\">%s</dc:identifier>\n", id)
+	fmt.Fprintf(&s, "<dc:title>%s</dc:title>\n<dc:language>en</dc:language>\n<dc:creator>NostrFeedz</dc:creator>\n", html.EscapeString(title))
+	fmt.Fprintf(&s, "<meta property=\"dcterms:modified\">%s</meta>\n</metadata>\n<manifest>\n", time.Now().UTC().Format("2006-01-02T15:04:05Z"))
+	s.WriteString("<item id=\"nav\" href=\"nav.xhtml\" media-type=\"application/xhtml+xml\" properties=\"nav\"/>\n")
+	s.WriteString("<item id=\"ncx\" href=\"toc.ncx\" media-type=\"application/x-dtbncx+xml\"/>\n")
+	s.WriteString("<item id=\"css\" href=\"style.css\" media-type=\"text/css\"/>\n")
+	for i, chapter := range chapters {
+		fmt.Fprintf(&s, "<item id=\"article-%d\" href=\"%s\" media-type=\"application/xhtml+xml\"/>\n", i+1, chapter)
+	}
+	for i, img := range packed {
+		fmt.Fprintf(&s, "<item id=\"image-%d\" href=\"%s\" media-type=\"%s\"/>\n", i+1, img.name, img.mime)
+	}
+	s.WriteString("</manifest>\n<spine toc=\"ncx\">\n<itemref idref=\"nav\"/>\n")
+	for i := range chapters {
+		fmt.Fprintf(&s, "<itemref idref=\"article-%d\"/>\n", i+1)
+	}
+	s.WriteString("</spine>\n</package>\n")
+	return s.String()
+}
+
+// bookID is a random urn:uuid identifier for a new book
+func bookID() string {
+	b := make([]byte, 16)
+	rand.Read(b)
+	b[6] = b[6]&0x0f | 0x40
+	b[8] = b[8]&0x3f | 0x80
+	return fmt.Sprintf("urn:uuid:%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:])
+}
@@ -0,0 +1,86 @@
+package export
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+// Format is an export file format
+type Format string
+
+const (
+	Markdown Format = "markdown" // A directory of files with front matter
+	HTML     Format = "html"     // One self-contained page
+	EPUB     Format = "epub"     // A book with a table of contents
+	Text     Format = "text"     // One plain text file
+)
+
+// Formats lists the export formats
+var Formats = []Format{Markdown, HTML, EPUB, Text}
+
+// ParseFormat accepts a format name or its usual file extension
+func ParseFormat(name string) (Format, error) {
+	switch strings.ToLower(strings.TrimPrefix(name, ".")) {
+	case "markdown", "md":
+		return Markdown, nil
+	case "html", "htm":
+		return HTML, nil
+	case "epub":
+		return EPUB, nil
+	case "text", "txt":
+		return Text, nil
+	}
+	return "", fmt.Errorf("unknown export format %q (want markdown, html, epub or text)", name)
+}
+
+// Ext is the extension of the file a format writes, "" for markdown which
+// writes a directory
+func (f Format) Ext() string {
+	switch f {
+	case HTML:
+		return ".html"
+	case EPUB:
+		return ".epub"
+	case Text:
+		return ".txt"
+	}
+	return ""
+}
+
+// Write exports articles to path: a directory of files for markdown, a
+// single file otherwise. It returns the paths written.
+func Write(format Format, path, title string, articles []Article, images Images) ([]string, error) {
+	if len(articles) == 0 {
+		return nil, fmt.Errorf("no articles to export")
+	}
+
+	var write func(io.Writer) error
+	switch format {
+	case Markdown:
+		return WriteMarkdown(path, articles, images)
+	case HTML:
+		write = func(w io.Writer) error { return WriteHTML(w, title, articles, images) }
+	case EPUB:
+		write = func(w io.Writer) error { return WriteEPUB(w, title, articles, images) }
+	case Text:
+		write = func(w io.Writer) error { return WriteText(w, title, articles) }
+	default:
+		return nil, fmt.Errorf("unknown export format %q", format)
+	}
+
+	f, err := os.Create(path)
+	if err != nil {
+		return nil, err
+	}
+	err = write(f)
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		os.Remove(path)
+		return nil, err
+	}
+	return []string{path}, nil
+}
@@ -0,0 +1,80 @@
+package export
+
+import (
+	"encoding/base64"
+	"fmt"
+	"html"
+	"io"
+	"strings"
+
+	nethtml "golang.org/x/net/html"
+)
+
+const stylesheet = `body { font-family: Georgia, serif; max-width: 42em; margin: 2em auto; padding: 0 1em; line-height: 1.6; color: #222; }
+h1, h2 { font-family: sans-serif; line-height: 1.25; }
+img { max-width: 100%; height: auto; }
+pre { overflow-x: auto; padding: 0.75em; background: #f4f4f4; }
+blockquote { margin-left: 0; padding-left: 1em; border-left: 3px solid #ccc; color: #555; }
+.meta { color: #777; font-size: 0.9em; }
+article { margin-bottom: 3em; }
+`
+
+// WriteHTML writes articles as one self-contained HTML page with a table
+// of contents. Cached images are embedded as data URIs; the rest stay
+// remote links.
+func WriteHTML(w io.Writer, title string, articles []Article, images Images) error {
+	var s strings.Builder
+	s.WriteString("<!DOCTYPE html>\n<html lang=\"en\">\n<head>\n<meta charset=\"utf-8\">\n")
+	fmt.Fprintf(&s, "<title>%s</title>\n<style>\n%s</style>\n</head>\n<body>\n", html.EscapeString(title), stylesheet)
+	fmt.Fprintf(&s, "<h1>%s</h1>\n", html.EscapeString(title))
+
+	if len(articles) > 1 {
+		s.WriteString("<nav>\n<ol>\n")
+		for i, a := range articles {
+			fmt.Fprintf(&s, "<li><a href=\"#article-%d\">%s</a></li>\n", i+1, html.EscapeString(a.Title))
+		}
+		s.WriteString("</ol>\n</nav>\n")
+	}
+
+	for i, a := range articles {
+		nodes, err := body(a)
+		if err != nil {
+			return fmt.Errorf("%s: %w", a.Title, err)
+		}
+		for _, n := range nodes {
+			walkImages(n, func(img *nethtml.Node) {
+				if embedded := loadImage(images, attr(img, "src")); embedded != nil {
+					setAttr(img, "src", "data:"+embedded.mime+";base64,"+base64.StdEncoding.EncodeToString(embedded.data))
+				}
+			})
+		}
+		content, err := render(nodes)
+		if err != nil {
+			return err
+		}
+
+		fmt.Fprintf(&s, "<article id=\"article-%d\">\n<h2>%s</h2>\n", i+1, html.EscapeString(a.Title))
+		s.WriteString(metaLine(a))
+		s.WriteString(content)
+		s.WriteString("\n</article>\n")
+	}
+
+	s.WriteString("</body>\n</html>\n")
+	_, err := io.WriteString(w, s.String())
+	return err
+}
+
+// metaLine is the byline paragraph with a link to the original
+func metaLine(a Article) string {
+	parts := []string{}
+	if m := meta(a); m != "" {
+		parts = append(parts, html.EscapeString(m))
+	}
+	if a.URL != "" {
+		parts = append(parts, fmt.Sprintf("<a href=\"%s\">Original</a>", html.EscapeString(a.URL)))
+	}
+	if len(parts) == 0 {
+		return ""
+	}
+	return "<p class=\"meta\">" + strings.Join(parts, " · ") + "</p>\n"
+}
@@ -0,0 +1,115 @@
+package export
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	md "github.com/JohannesKaufmann/html-to-markdown"
+	nethtml "golang.org/x/net/html"
+)
+
+var slugUnsafe = regexp.MustCompile(`[^a-z0-9]+`)
+
+// WriteMarkdown writes each article to its own markdown file with YAML
+// front matter in dir. Cached images are copied to dir/images and linked
+// relatively; the rest stay remote links. It returns the files written.
+func WriteMarkdown(dir string, articles []Article, images Images) ([]string, error) {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, err
+	}
+	converter := md.NewConverter("", true, nil)
+
+	var written []string
+	used := make(map[string]bool)
+	for _, a := range articles {
+		nodes, err := body(a)
+		if err != nil {
+			return written, fmt.Errorf("%s: %w", a.Title, err)
+		}
+		for _, n := range nodes {
+			var werr error
+			walkImages(n, func(img *nethtml.Node) {
+				local := loadImage(images, attr(img, "src"))
+				if local == nil || werr != nil {
+					return
+				}
+				path := filepath.Join(dir, filepath.FromSlash(local.name))
+				if _, err := os.Stat(path); os.IsNotExist(err) {
+					if werr = os.MkdirAll(filepath.Dir(path), 0755); werr != nil {
+						return
+					}
+					if werr = os.WriteFile(path, local.data, 0644); werr != nil {
+						return
+					}
+				}
+				setAttr(img, "src", local.name)
+			})
+			if werr != nil {
+				return written, werr
+			}
+		}
+		content, err := render(nodes)
+		if err != nil {
+			return written, err
+		}
+		text, err := converter.ConvertString(content)
+		if err != nil {
+			return written, fmt.Errorf("%s: failed to convert to markdown: %w", a.Title, err)
+		}
+
+		path := filepath.Join(dir, fileName(a, used)+".md")
+		if err := os.WriteFile(path, []byte(frontMatter(a)+text+"\n"), 0644); err != nil {
+			return written, err
+		}
+		written = append(written, path)
+	}
+	return written, nil
+}
+
+// frontMatter is the YAML header of an exported markdown file
+func frontMatter(a Article) string {
+	var s strings.Builder
+	s.WriteString("---\n")
+	for _, field := range []struct{ key, value string }{
+		{"title", a.Title},
+		{"author", a.Author},
+		{"feed", a.Feed},
+		{"url", a.URL},
+	} {
+		if field.value != "" {
+			// A Go quoted string is a valid YAML double-quoted scalar
+			fmt.Fprintf(&s, "%s: %s\n", field.key, strconv.Quote(field.value))
+		}
+	}
+	if !a.Published.IsZero() && a.Published.Unix() > 0 {
+		fmt.Fprintf(&s, "date: %s\n", a.Published.UTC().Format(time.RFC3339))
+	}
+	s.WriteString("---\n\n")
+	return s.String()
+}
+
+// fileName is a date-prefixed slug of the title, unique among used
+func fileName(a Article, used map[string]bool) string {
+	slug := strings.Trim(slugUnsafe.ReplaceAllString(strings.ToLower(a.Title), "-"), "-")
+	if len(slug) > 60 {
+		slug = strings.TrimRight(slug[:60], "-")
+	}
+	if slug == "" {
+		slug = "article"
+	}
+	if !a.Published.IsZero() && a.Published.Unix() > 0 {
+		slug = a.Published.Format("2006-01-02") + "-" + slug
+	}
+
+	name := slug
+	for i := 2; used[name]; i++ {
+		name = fmt.Sprintf("%s-%d", slug, i)
+	}
+	used[name] = true
+	return name
+}
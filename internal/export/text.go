@@ -0,0 +1,201 @@
+package export
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+)
+
+// textWidth is the column plain text paragraphs are wrapped at
+const textWidth = 78
+
+// WriteText writes articles as one plain text file. Images become their
+// alt text and links keep their URL next to the link text.
+func WriteText(w io.Writer, title string, articles []Article) error {
+	var s strings.Builder
+	if len(articles) > 1 {
+		fmt.Fprintf(&s, "%s\n%s\n\n", title, underline(title, "="))
+	}
+
+	for i, a := range articles {
+		if i > 0 {
+			s.WriteString("\n* * *\n\n")
+		}
+		nodes, err := body(a)
+		if err != nil {
+			return fmt.Errorf("%s: %w", a.Title, err)
+		}
+		fmt.Fprintf(&s, "%s\n%s\n", a.Title, underline(a.Title, "-"))
+		if m := meta(a); m != "" {
+			s.WriteString(m + "\n")
+		}
+		if a.URL != "" {
+			s.WriteString(a.URL + "\n")
+		}
+
+		t := &textWriter{}
+		for _, n := range nodes {
+			t.walk(n)
+		}
+		t.flush()
+		if len(t.blocks) > 0 {
+			s.WriteString("\n" + t.String() + "\n")
+		}
+	}
+
+	_, err := io.WriteString(w, s.String())
+	return err
+}
+
+func underline(s, char string) string {
+	n := len([]rune(s))
+	if n > textWidth {
+		n = textWidth
+	}
+	return strings.Repeat(char, n)
+}
+
+// textWriter collects the paragraphs of an HTML fragment as wrapped text
+type textWriter struct {
+	blocks []string
+	items  []bool // Which blocks are list items, kept together
+	cur    strings.Builder
+	list   string // Marker of the list item being written
+	quote  int    // Blockquote depth
+}
+
+func (t *textWriter) walk(n *html.Node) {
+	switch n.Type {
+	case html.TextNode:
+		t.cur.WriteString(n.Data)
+		return
+	case html.ElementNode:
+	default:
+		for c := n.FirstChild; c != nil; c = c.NextSibling {
+			t.walk(c)
+		}
+		return
+	}
+
+	switch n.DataAtom {
+	case atom.Pre:
+		t.flush()
+		code := strings.Trim(textContent(n), "\n")
+		if code != "" {
+			t.blocks = append(t.blocks, "    "+strings.ReplaceAll(code, "\n", "\n    "))
+			t.items = append(t.items, false)
+		}
+		return
+	case atom.Br, atom.Hr:
+		t.flush()
+		return
+	case atom.Img:
+		if alt := strings.TrimSpace(attr(n, "alt")); alt != "" {
+			fmt.Fprintf(&t.cur, " [image: %s] ", alt)
+		} else {
+			t.cur.WriteString(" [image] ")
+		}
+		return
+	case atom.A:
+		start := t.cur.Len()
+		t.children(n)
+		text := strings.TrimSpace(t.cur.String()[start:])
+		if href := attr(n, "href"); href != "" && href != text && !strings.HasPrefix(href, "mailto:") {
+			fmt.Fprintf(&t.cur, " <%s>", href)
+		}
+		return
+	case atom.Li:
+		t.flush()
+		t.list = "- "
+		t.children(n)
+		t.flush()
+		t.list = ""
+		return
+	case atom.Blockquote:
+		t.flush()
+		t.quote++
+		t.children(n)
+		t.flush()
+		t.quote--
+		return
+	case atom.P, atom.Div, atom.H1, atom.H2, atom.H3, atom.H4, atom.H5, atom.H6, atom.Ul, atom.Ol,
+		atom.Section, atom.Article, atom.Header, atom.Footer, atom.Figure, atom.Figcaption,
+		atom.Table, atom.Tr, atom.Dl, atom.Dt, atom.Dd:
+		t.flush()
+		t.children(n)
+		t.flush()
+		return
+	case atom.Td, atom.Th:
+		t.cur.WriteString(" ")
+	}
+	t.children(n)
+}
+
+func (t *textWriter) children(n *html.Node) {
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		t.walk(c)
+	}
+}
+
+// flush ends the paragraph being collected
+func (t *textWriter) flush() {
+	words := strings.Fields(t.cur.String())
+	t.cur.Reset()
+	if len(words) == 0 {
+		return
+	}
+	// The marker goes on the first paragraph of a list item only
+	list := t.list
+	t.list = ""
+
+	quote := strings.Repeat("> ", t.quote)
+	first := quote + list
+	rest := quote + strings.Repeat(" ", len(list))
+
+	var lines []string
+	line := first
+	for _, word := range words {
+		if len(line) > len(rest) && len(line)+1+len(word) > textWidth && line != first {
+			lines = append(lines, line)
+			line = rest
+		}
+		if line != first && line != rest {
+			line += " "
+		}
+		line += word
+	}
+	lines = append(lines, line)
+	t.blocks = append(t.blocks, strings.Join(lines, "\n"))
+	t.items = append(t.items, list != "")
+}
+
+// String joins the paragraphs with blank lines between them, except
+// between items of the same list
+func (t *textWriter) String() string {
+	var s strings.Builder
+	for i, block := range t.blocks {
+		if i > 0 {
+			if t.items[i] && t.items[i-1] {
+				s.WriteString("\n")
+			} else {
+				s.WriteString("\n\n")
+			}
+		}
+		s.WriteString(block)
+	}
+	return s.String()
+}
+
+func textContent(n *html.Node) string {
+	if n.Type == html.TextNode {
+		return n.Data
+	}
+	var s strings.Builder
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		s.WriteString(textContent(c))
+	}
+	return s.String()
+}
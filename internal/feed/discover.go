@@ -0,0 +1,88 @@
+package feed
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+
+	"github.com/PuerkitoBio/goquery"
+	"github.com/mmcdole/gofeed"
+)
+
+// DiscoveredFeed is a feed found at or advertised by a URL
+type DiscoveredFeed struct {
+	URL   string
+	Title string
+}
+
+// DiscoverFeed returns the feed behind a link: the link itself if it is a
+// feed, otherwise the first RSS, Atom or JSON feed the page advertises with
+// <link rel="alternate">
+func DiscoverFeed(ctx context.Context, link string) (*DiscoveredFeed, error) {
+	body, finalURL, err := fetchBody(ctx, link)
+	if err != nil {
+		return nil, err
+	}
+
+	parser := gofeed.NewParser()
+	if parsed, err := parser.Parse(bytes.NewReader(body)); err == nil {
+		return &DiscoveredFeed{URL: finalURL, Title: parsed.Title}, nil
+	}
+
+	doc, err := goquery.NewDocumentFromReader(bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("not a feed or web page")
+	}
+	base, err := url.Parse(finalURL)
+	if err != nil {
+		return nil, err
+	}
+
+	var candidates []string
+	doc.Find(`link[rel~="alternate"]`).Each(func(_ int, s *goquery.Selection) {
+		switch s.AttrOr("type", "") {
+		case "application/rss+xml", "application/atom+xml", "application/feed+json", "application/json":
+			if ref, err := url.Parse(s.AttrOr("href", "")); err == nil && s.AttrOr("href", "") != "" {
+				candidates = append(candidates, base.ResolveReference(ref).String())
+			}
+		}
+	})
+
+	for _, candidate := range candidates {
+		body, feedURL, err := fetchBody(ctx, candidate)
+		if err != nil {
+			continue
+		}
+		if parsed, err := parser.Parse(bytes.NewReader(body)); err == nil {
+			return &DiscoveredFeed{URL: feedURL, Title: parsed.Title}, nil
+		}
+	}
+	return nil, fmt.Errorf("no feed found at %s", link)
+}
+
+// fetchBody downloads a URL and returns its body and the URL after redirects
+func fetchBody(ctx context.Context, link string) ([]byte, string, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, link, nil)
+	if err != nil {
+		return nil, "", err
+	}
+	req.Header.Set("User-Agent", "Mozilla/5.0 (compatible; nostrfeedz-cli)")
+
+	resp, err := pageClient.Do(req)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to fetch %s: %w", link, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, "", fmt.Errorf("failed to fetch %s: HTTP %d", link, resp.StatusCode)
+	}
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxPageSize))
+	if err != nil {
+		return nil, "", err
+	}
+	return body, resp.Request.URL.String(), nil
+}
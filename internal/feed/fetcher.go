@@ -3,6 +3,9 @@ package feed
 import (
 	"context"
 	"fmt"
+	"strconv"
+	"strings"
+	"sync"
 	"time"
 
 	"github.com/mmcdole/gofeed"
@@ -13,8 +16,11 @@ import (
 
 // Fetcher handles fetching articles from RSS and Nostr feeds
 type Fetcher struct {
+	poolMu     sync.Mutex
 	nostrPool  *nostr.SimplePool
 	nostrRelays []string
+
+	youtubeFeeds sync.Map // YouTube link -> its resolved Atom feed
 }
 
 // NewFetcher creates a new feed fetcher
@@ -25,6 +31,30 @@ func NewFetcher(nostrRelays []string) *Fetcher {
 	}
 }
 
+// SetRelays replaces the relays used for Nostr queries
+func (f *Fetcher) SetRelays(relays []string) {
+	f.nostrRelays = relays
+}
+
+// SetAuthHandler answers NIP-42 AUTH challenges from relays with the given
+// handler. The pool is recreated because handlers are fixed at construction;
+// the old one is closed so its relay connections go with it.
+func (f *Fetcher) SetAuthHandler(handler func(context.Context, nostr.RelayEvent) error) {
+	pool := nostr.NewSimplePool(context.Background(), nostr.WithAuthHandler(handler))
+	f.poolMu.Lock()
+	old := f.nostrPool
+	f.nostrPool = pool
+	f.poolMu.Unlock()
+	old.Close("replaced by a pool that answers AUTH")
+}
+
+// pool returns the current Nostr relay pool
+func (f *Fetcher) pool() *nostr.SimplePool {
+	f.poolMu.Lock()
+	defer f.poolMu.Unlock()
+	return f.nostrPool
+}
+
 // FetchRSSArticles fetches articles from an RSS feed
 func (f *Fetcher) FetchRSSArticles(feedURL string, feedID string) ([]*db.FeedItem, error) {
 	parser := gofeed.NewParser()
@@ -77,27 +107,90 @@ func (f *Fetcher) FetchRSSArticles(feedURL string, feedID string) ([]*db.FeedIte
 			CreatedAt:   time.Now(),
 		}
 
-		// Handle media enclosures (images, videos)
-		if len(item.Enclosures) > 0 {
-			// For now, just store the first enclosure
-			enc := item.Enclosures[0]
-			if enc.Type != "" {
-				if enc.Type[:5] == "image" {
-					article.Thumbnail = enc.URL
-				} else if enc.Type[:5] == "video" {
-					article.Thumbnail = enc.URL
-					// Try to extract video ID from URL
-					article.VideoID = extractVideoID(enc.URL)
+		// Video feeds describe their videos in Media RSS, which news feeds
+		// use for pictures. YouTube entries have no other content than the
+		// description.
+		article.VideoID = youtubeVideoID(item)
+		if _, ok := ParseVideoURL(item.Link); ok || article.VideoID != "" {
+			if meta := parseMediaRSS(item); meta != nil {
+				article.Video = meta
+				article.Thumbnail = meta.Thumbnail
+				if article.Content == "" {
+					article.Content = descriptionContent(meta.Description)
 				}
 			}
 		}
 
+		// Every enclosure is stored; the first image or video also becomes
+		// the thumbnail
+		duration := time.Duration(0)
+		if item.ITunesExt != nil {
+			duration = parseDuration(item.ITunesExt.Duration)
+		}
+		for _, enc := range item.Enclosures {
+			if enc.URL == "" {
+				continue
+			}
+			length, _ := strconv.ParseInt(strings.TrimSpace(enc.Length), 10, 64)
+			article.Enclosures = append(article.Enclosures, db.Enclosure{
+				URL:      enc.URL,
+				Type:     enc.Type,
+				Length:   length,
+				Duration: duration,
+			})
+			if article.Thumbnail != "" {
+				continue
+			}
+			if strings.HasPrefix(enc.Type, "image/") {
+				article.Thumbnail = enc.URL
+			} else if strings.HasPrefix(enc.Type, "video/") {
+				article.Thumbnail = enc.URL
+				if v, ok := ParseVideoURL(enc.URL); ok {
+					article.VideoID = v.ID
+				}
+			}
+		}
+		// Items of video feeds link to the video itself
+		if v, ok := ParseVideoURL(item.Link); ok && article.VideoID == "" {
+			article.VideoID = v.ID
+		}
+
 		articles = append(articles, article)
 	}
 
 	return articles, nil
 }
 
+// FetchYouTubeVideos fetches the videos of a YouTube channel, handle or
+// playlist link. Links are resolved to their feed once per run.
+func (f *Fetcher) FetchYouTubeVideos(link string, feedID string) ([]*db.FeedItem, error) {
+	feedURL, ok := f.youtubeFeeds.Load(link)
+	if !ok {
+		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+		defer cancel()
+		resolved, err := ResolveYouTubeFeed(ctx, link)
+		if err != nil {
+			return nil, fmt.Errorf("failed to resolve YouTube feed: %w", err)
+		}
+		f.youtubeFeeds.Store(link, resolved)
+		feedURL = resolved
+	}
+	return f.FetchRSSArticles(feedURL.(string), feedID)
+}
+
+// parseDuration parses an itunes:duration: seconds, MM:SS or HH:MM:SS
+func parseDuration(value string) time.Duration {
+	var total int64
+	for _, part := range strings.Split(strings.TrimSpace(value), ":") {
+		n, err := strconv.ParseFloat(part, 64)
+		if err != nil || n < 0 {
+			return 0
+		}
+		total = total*60 + int64(n)
+	}
+	return time.Duration(total) * time.Second
+}
+
 // FetchNostrArticles fetches NIP-23 long-form articles from a Nostr user
 func (f *Fetcher) FetchNostrArticles(npub string, feedID string) ([]*db.FeedItem, error) {
 	// Convert npub to hex pubkey
@@ -123,7 +216,7 @@ func (f *Fetcher) FetchNostrArticles(npub string, feedID string) ([]*db.FeedItem
 	}
 
 	var articles []*db.FeedItem
-	for ev := range f.nostrPool.SubManyEose(ctx, f.nostrRelays, nostr.Filters{filter}) {
+	for ev := range f.pool().SubManyEose(ctx, f.nostrRelays, nostr.Filters{filter}) {
 		if ev.Event == nil {
 			continue
 		}
@@ -133,6 +226,7 @@ func (f *Fetcher) FetchNostrArticles(npub string, feedID string) ([]*db.FeedItem
 		// Extract metadata from tags
 		title := ""
 		image := ""
+		identifier := ""
 		publishedAt := time.Unix(int64(event.CreatedAt), 0)
 
 		for _, tag := range event.Tags {
@@ -140,6 +234,8 @@ func (f *Fetcher) FetchNostrArticles(npub string, feedID string) ([]*db.FeedItem
 				continue
 			}
 			switch tag[0] {
+			case "d":
+				identifier = tag[1]
 			case "title":
 				title = tag[1]
 			case "summary":
@@ -158,8 +254,12 @@ func (f *Fetcher) FetchNostrArticles(npub string, feedID string) ([]*db.FeedItem
 		// Get author name from event (we'd need to fetch kind 0 for proper name)
 		author := event.PubKey[:8] + "..." // Short pubkey for now
 		
-		// Encode event ID as note1...
-		noteID, _ := nip19.EncodeNote(event.ID)
+		// Link to the article as naddr1..., which survives edits, falling
+		// back to the event ID as note1...
+		link, err := nip19.EncodeEntity(event.PubKey, event.Kind, identifier, nil)
+		if err != nil || identifier == "" {
+			link, _ = nip19.EncodeNote(event.ID)
+		}
 
 		article := &db.FeedItem{
 			ID:          fmt.Sprintf("item_%d", time.Now().UnixNano()),
@@ -167,7 +267,7 @@ func (f *Fetcher) FetchNostrArticles(npub string, feedID string) ([]*db.FeedItem
 			GUID:        guid,
 			Title:       title,
 			Content:     event.Content,
-			URL:         fmt.Sprintf("nostr:%s", noteID),
+			URL:         fmt.Sprintf("nostr:%s", link),
 			Author:      author,
 			PublishedAt: publishedAt,
 			IsRead:      false,
@@ -181,22 +281,3 @@ func (f *Fetcher) FetchNostrArticles(npub string, feedID string) ([]*db.FeedItem
 
 	return articles, nil
 }
-
-// extractVideoID tries to extract a video ID from common video platforms
-func extractVideoID(url string) string {
-	// YouTube patterns
-	patterns := []string{
-		"youtube.com/watch?v=",
-		"youtu.be/",
-		"youtube.com/embed/",
-	}
-
-	for _, pattern := range patterns {
-		if idx := len(url); idx > 0 {
-			// Simple extraction - could be improved
-			return url[len(pattern):]
-		}
-	}
-
-	return ""
-}
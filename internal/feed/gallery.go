@@ -0,0 +1,91 @@
+package feed
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	_ "image/png" // Thumbnails are PNGs
+	"os"
+
+	"github.com/blacktop/go-termimg"
+	"golang.org/x/image/draw"
+)
+
+const (
+	// GalleryCellWidth and GalleryCellHeight are the size of one gallery
+	// thumbnail in terminal cells
+	GalleryCellWidth  = 24
+	GalleryCellHeight = 9
+
+	// A cell in pixels: a 240x180 thumbnail plus the selection frame,
+	// roughly the shape of 24x9 cells of a typical terminal font
+	galleryCellPixelsX = 256
+	galleryCellPixelsY = 196
+	galleryFrame       = 4
+)
+
+// RenderGallery renders thumbnails as a grid of the given number of
+// columns, framing the selected one. The grid is drawn as one image, so it
+// looks the same with kitty, sixel, iTerm2 and half-block output. Empty
+// paths are thumbnails that failed and are drawn as blank cells.
+func (ir *ImageRenderer) RenderGallery(thumbs []string, selected, columns int, frame color.Color) (string, error) {
+	if len(thumbs) == 0 || columns <= 0 {
+		return "", fmt.Errorf("no images")
+	}
+	rows := (len(thumbs) + columns - 1) / columns
+	cols := min(columns, len(thumbs))
+	sheet := image.NewRGBA(image.Rect(0, 0, cols*galleryCellPixelsX, rows*galleryCellPixelsY))
+
+	blank := image.NewUniform(color.RGBA{0x40, 0x40, 0x40, 0xff})
+	for i, path := range thumbs {
+		cell := image.Rect(0, 0, galleryCellPixelsX, galleryCellPixelsY).
+			Add(image.Pt(i%columns*galleryCellPixelsX, i/columns*galleryCellPixelsY))
+		if i == selected {
+			draw.Draw(sheet, cell, image.NewUniform(frame), image.Point{}, draw.Src)
+			draw.Draw(sheet, cell.Inset(galleryFrame), image.Transparent, image.Point{}, draw.Src)
+		}
+		inner := cell.Inset(2 * galleryFrame)
+
+		thumb, err := loadThumbnail(path)
+		if err != nil {
+			draw.Draw(sheet, inner, blank, image.Point{}, draw.Src)
+			continue
+		}
+		// Center the thumbnail, scaling it down if it was made larger
+		b := thumb.Bounds()
+		w, h := b.Dx(), b.Dy()
+		if w > inner.Dx() || h > inner.Dy() {
+			if w*inner.Dy() > h*inner.Dx() {
+				w, h = inner.Dx(), h*inner.Dx()/w
+			} else {
+				w, h = w*inner.Dy()/h, inner.Dy()
+			}
+		}
+		at := image.Rect(0, 0, w, h).Add(inner.Min).
+			Add(image.Pt((inner.Dx()-w)/2, (inner.Dy()-h)/2))
+		draw.ApproxBiLinear.Scale(sheet, at, thumb, b, draw.Over, nil)
+	}
+
+	rendered, err := termimg.New(sheet).
+		Protocol(ir.Protocol()).
+		Size(cols*GalleryCellWidth, rows*GalleryCellHeight).
+		Render()
+	if err != nil {
+		return "", fmt.Errorf("failed to render gallery: %w", err)
+	}
+	return rendered, nil
+}
+
+// loadThumbnail decodes a thumbnail file
+func loadThumbnail(path string) (image.Image, error) {
+	if path == "" {
+		return nil, fmt.Errorf("no thumbnail")
+	}
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	img, _, err := image.Decode(f)
+	return img, err
+}
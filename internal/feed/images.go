@@ -0,0 +1,144 @@
+package feed
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/blacktop/go-termimg"
+)
+
+// ImageProtocols lists the accepted display.image_protocol values
+var ImageProtocols = []string{"auto", "kitty", "sixel", "iterm2", "halfblocks"}
+
+// maxMemoBytes bounds the rendered images kept in memory; kitty and
+// iTerm2 output is the base64 of the whole image
+const maxMemoBytes = 64 * 1024 * 1024
+
+// ImageSource gives the local file of an image, downloading it if needed
+type ImageSource interface {
+	Download(imageURL string) (string, error)
+}
+
+// ImageRenderer draws images in the terminal with one protocol, reading
+// them from an image source and remembering what it rendered. It outlives
+// Renderer, which is rebuilt on every resize.
+type ImageRenderer struct {
+	source ImageSource
+	forced termimg.Protocol // Auto to detect the terminal's
+
+	detect   sync.Once
+	protocol termimg.Protocol
+
+	mu        sync.Mutex
+	memo      map[imageKey]string
+	order     []imageKey // Oldest first, for eviction
+	memoBytes int
+}
+
+// imageKey identifies one rendering of an image
+type imageKey struct {
+	url           string
+	width, height int
+	protocol      termimg.Protocol
+}
+
+// NewImageRenderer creates an image renderer. protocol is one of
+// ImageProtocols; "" means auto.
+func NewImageRenderer(source ImageSource, protocol string) (*ImageRenderer, error) {
+	forced, err := ParseImageProtocol(protocol)
+	if err != nil {
+		return nil, err
+	}
+	return &ImageRenderer{
+		source: source,
+		forced: forced,
+		memo:   make(map[imageKey]string),
+	}, nil
+}
+
+// ParseImageProtocol parses a display.image_protocol value
+func ParseImageProtocol(name string) (termimg.Protocol, error) {
+	switch strings.ToLower(name) {
+	case "", "auto":
+		return termimg.Auto, nil
+	case "kitty":
+		return termimg.Kitty, nil
+	case "sixel":
+		return termimg.Sixel, nil
+	case "iterm2":
+		return termimg.ITerm2, nil
+	case "halfblocks":
+		return termimg.Halfblocks, nil
+	}
+	return termimg.Unsupported, fmt.Errorf("unknown image protocol %q (want one of %v)", name, ImageProtocols)
+}
+
+// Protocol returns the protocol images are drawn with, detecting the
+// terminal's on first use unless one is configured
+func (ir *ImageRenderer) Protocol() termimg.Protocol {
+	ir.detect.Do(func() {
+		ir.protocol = ir.forced
+		if ir.protocol == termimg.Auto {
+			ir.protocol = termimg.DetectProtocol()
+		}
+	})
+	return ir.protocol
+}
+
+// Render draws an image to fit width x height cells
+func (ir *ImageRenderer) Render(imageURL string, width, height int) (string, error) {
+	key := imageKey{url: imageURL, width: width, height: height, protocol: ir.Protocol()}
+	ir.mu.Lock()
+	rendered, ok := ir.memo[key]
+	ir.mu.Unlock()
+	if ok {
+		return rendered, nil
+	}
+
+	if ir.source == nil {
+		return "", fmt.Errorf("image cache unavailable")
+	}
+	path, err := ir.source.Download(imageURL)
+	if err != nil {
+		return "", err
+	}
+	img, err := termimg.Open(path)
+	if err != nil {
+		return "", fmt.Errorf("failed to open cached image: %w", err)
+	}
+	rendered, err = img.Protocol(key.protocol).Size(width, height).Render()
+	if err != nil {
+		return "", fmt.Errorf("failed to render image: %w", err)
+	}
+
+	ir.remember(key, rendered)
+	return rendered, nil
+}
+
+// remember memoizes a rendering, dropping the oldest ones over the limit
+func (ir *ImageRenderer) remember(key imageKey, rendered string) {
+	if len(rendered) > maxMemoBytes {
+		return
+	}
+	ir.mu.Lock()
+	defer ir.mu.Unlock()
+	if _, ok := ir.memo[key]; ok {
+		return
+	}
+	ir.memo[key] = rendered
+	ir.order = append(ir.order, key)
+	ir.memoBytes += len(rendered)
+	for ir.memoBytes > maxMemoBytes {
+		oldest := ir.order[0]
+		ir.order = ir.order[1:]
+		ir.memoBytes -= len(ir.memo[oldest])
+		delete(ir.memo, oldest)
+	}
+}
+
+// SupportsInlineImages reports whether the terminal has a graphics
+// protocol. It is detected once.
+var SupportsInlineImages = sync.OnceValue(func() bool {
+	return termimg.KittySupported() || termimg.SixelSupported() || termimg.ITerm2Supported()
+})
@@ -0,0 +1,156 @@
+package feed
+
+import (
+	"fmt"
+	"net/url"
+	"regexp"
+	"strings"
+)
+
+// Link is a numbered link reference in an article
+type Link struct {
+	Number int
+	Text   string
+	URL    string
+}
+
+// linkPattern matches, in order of preference: a linked image, an image,
+// a link, an autolink and a bare URL
+var linkPattern = regexp.MustCompile(
+	`\[!\[([^\]]*)\]\(([^)\s]+)[^)]*\)\]\(([^)\s]+)[^)]*\)` +
+		`|!\[[^\]]*\]\([^)]*\)` +
+		`|\[([^\]]*)\]\(([^)\s]+)[^)]*\)` +
+		`|<(https?://[^>\s]+)>` +
+		`|https?://[^\s<>()\[\]]+`)
+
+// ExtractLinks returns the links of an article, numbered as RenderContent
+// shows them. Relative URLs are resolved against baseURL.
+func (r *Renderer) ExtractLinks(content string, isHTML bool, baseURL string) ([]Link, error) {
+	if isHTML {
+		markdown, err := r.htmlConverter.ConvertString(content)
+		if err != nil {
+			return nil, fmt.Errorf("failed to convert HTML: %w", err)
+		}
+		content = markdown
+	}
+	_, links := numberLinks(content, baseURL)
+	return links, nil
+}
+
+// numberLinks replaces markdown links with their text and a [n] reference
+// and returns the links in order. A URL keeps the number it got first.
+// Fenced code blocks are left alone.
+func numberLinks(markdown, baseURL string) (string, []Link) {
+	base, err := url.Parse(baseURL)
+	if err != nil || base.Scheme == "" {
+		base = nil
+	}
+
+	var links []Link
+	numbers := make(map[string]int)
+	number := func(text, href string) (int, bool) {
+		href = resolveLink(base, href)
+		if href == "" {
+			return 0, false
+		}
+		if n, ok := numbers[href]; ok {
+			return n, true
+		}
+		if text == "" {
+			text = href
+		}
+		n := len(links) + 1
+		numbers[href] = n
+		links = append(links, Link{Number: n, Text: text, URL: href})
+		return n, true
+	}
+
+	lines := strings.Split(markdown, "\n")
+	inFence := false
+	for i, line := range lines {
+		if strings.HasPrefix(strings.TrimSpace(line), "```") {
+			inFence = !inFence
+			continue
+		}
+		if inFence {
+			continue
+		}
+		lines[i] = replaceLinks(line, number)
+	}
+	return strings.Join(lines, "\n"), links
+}
+
+// replaceLinks rewrites the links on one line
+func replaceLinks(line string, number func(text, href string) (int, bool)) string {
+	var out strings.Builder
+	last := 0
+	for _, m := range linkPattern.FindAllStringSubmatchIndex(line, -1) {
+		out.WriteString(line[last:m[0]])
+		last = m[1]
+		match := line[m[0]:m[1]]
+		group := func(i int) string {
+			if m[2*i] < 0 {
+				return ""
+			}
+			return line[m[2*i]:m[2*i+1]]
+		}
+
+		switch {
+		case m[2] >= 0: // Linked image: keep the image, reference the link
+			out.WriteString(fmt.Sprintf("![%s](%s)", group(1), group(2)))
+			if n, ok := number(group(1), group(3)); ok {
+				out.WriteString(fmt.Sprintf(" \\[%d\\]", n))
+			}
+		case strings.HasPrefix(match, "!"): // Image
+			out.WriteString(match)
+		case m[8] >= 0: // Link
+			text := group(4)
+			n, ok := number(stripMarkdown(text), group(5))
+			if text == "" {
+				text = group(5)
+			}
+			out.WriteString(text)
+			if ok {
+				out.WriteString(fmt.Sprintf(" \\[%d\\]", n))
+			}
+		case m[12] >= 0: // Autolink
+			out.WriteString(group(6))
+			if n, ok := number("", group(6)); ok {
+				out.WriteString(fmt.Sprintf(" \\[%d\\]", n))
+			}
+		default: // Bare URL, minus trailing punctuation
+			href := strings.TrimRight(match, ".,;:!?'\"")
+			out.WriteString(href)
+			if n, ok := number("", href); ok {
+				out.WriteString(fmt.Sprintf(" \\[%d\\]", n))
+			}
+			out.WriteString(match[len(href):])
+		}
+	}
+	out.WriteString(line[last:])
+	return out.String()
+}
+
+// resolveLink makes a link absolute, returning "" for links that lead
+// nowhere useful (in-page anchors and scripts)
+func resolveLink(base *url.URL, href string) string {
+	if href == "" || strings.HasPrefix(href, "#") {
+		return ""
+	}
+	u, err := url.Parse(href)
+	if err != nil {
+		return href
+	}
+	if u.Scheme == "javascript" {
+		return ""
+	}
+	if base != nil && !u.IsAbs() {
+		u = base.ResolveReference(u)
+	}
+	return u.String()
+}
+
+// stripMarkdown removes emphasis markers from link text
+func stripMarkdown(text string) string {
+	return strings.Trim(strings.NewReplacer("**", "", "__", "", "`", "").Replace(text), "*_ ")
+}
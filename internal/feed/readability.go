@@ -0,0 +1,332 @@
+package feed
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/PuerkitoBio/goquery"
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/charset"
+)
+
+// Article is the main content extracted from a web page
+type Article struct {
+	URL     string
+	Title   string
+	Byline  string
+	Content string // Cleaned HTML of the main content
+}
+
+// maxPageSize caps how much of a page is read
+const maxPageSize = 5 << 20
+
+var pageClient = &http.Client{Timeout: 30 * time.Second}
+
+var (
+	// Class and id hints, as in Arc90's readability
+	unlikelyCandidates = regexp.MustCompile(`(?i)banner|breadcrumb|combx|comment|community|cookie|disqus|extra|footer|gdpr|header|legends|menu|modal|nav|newsletter|pager|pagination|popup|promo|related|remark|replies|rss|share|shoutbox|sidebar|skyscraper|social|sponsor|subscribe|tweet|ad-break|agegate`)
+	maybeCandidate     = regexp.MustCompile(`(?i)and|article|body|column|content|main|shadow`)
+	positiveHints      = regexp.MustCompile(`(?i)article|body|content|entry|hentry|h-entry|main|page|post|text|blog|story`)
+	negativeHints      = regexp.MustCompile(`(?i)-ad-|hidden|^hid$| hid$| hid |^hid |banner|combx|comment|com-|contact|foot|footer|footnote|gdpr|masthead|media|meta|outbrain|promo|related|scroll|share|shoutbox|sidebar|skyscraper|sponsor|shopping|tags|tool|widget`)
+)
+
+// FetchArticle downloads a web page and extracts its main content
+func FetchArticle(ctx context.Context, pageURL string) (*Article, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, pageURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("User-Agent", "Mozilla/5.0 (compatible; nostrfeedz-cli)")
+	req.Header.Set("Accept", "text/html,application/xhtml+xml")
+
+	resp, err := pageClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch page: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to fetch page: HTTP %d", resp.StatusCode)
+	}
+	contentType := resp.Header.Get("Content-Type")
+	if contentType != "" && !strings.Contains(contentType, "html") {
+		return nil, fmt.Errorf("not a web page (%s)", contentType)
+	}
+
+	body, err := charset.NewReader(io.LimitReader(resp.Body, maxPageSize), contentType)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode page: %w", err)
+	}
+	// Redirects change the base for relative links
+	return ExtractArticle(body, resp.Request.URL.String())
+}
+
+// ExtractArticle finds the main content of an HTML page, readability style:
+// boilerplate is dropped, paragraphs are scored by length and commas, and
+// the container collecting the best score is kept along with related
+// siblings. Relative links and images are made absolute against pageURL.
+func ExtractArticle(page io.Reader, pageURL string) (*Article, error) {
+	doc, err := goquery.NewDocumentFromReader(page)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse page: %w", err)
+	}
+
+	article := &Article{
+		URL:    pageURL,
+		Title:  pageTitle(doc),
+		Byline: pageByline(doc),
+	}
+
+	prepareDocument(doc)
+	top, scores := topCandidate(doc)
+	if top == nil {
+		return nil, fmt.Errorf("no readable content found")
+	}
+
+	content := collectContent(top, scores)
+	cleanContent(content)
+	if base, err := url.Parse(pageURL); err == nil {
+		absolutize(content, base)
+	}
+
+	html, err := content.Html()
+	if err != nil {
+		return nil, err
+	}
+	article.Content = "<div>" + strings.TrimSpace(html) + "</div>"
+	return article, nil
+}
+
+func pageTitle(doc *goquery.Document) string {
+	for _, sel := range []string{`meta[property="og:title"]`, `meta[name="twitter:title"]`} {
+		if title, ok := doc.Find(sel).Attr("content"); ok && strings.TrimSpace(title) != "" {
+			return strings.TrimSpace(title)
+		}
+	}
+	title := strings.TrimSpace(doc.Find("title").First().Text())
+	if title == "" {
+		title = strings.TrimSpace(doc.Find("h1").First().Text())
+	}
+	return title
+}
+
+func pageByline(doc *goquery.Document) string {
+	if author, ok := doc.Find(`meta[name="author"]`).Attr("content"); ok && strings.TrimSpace(author) != "" {
+		return strings.TrimSpace(author)
+	}
+	for _, sel := range []string{`[rel="author"]`, `[itemprop="author"]`, `.byline`, `.author`, `.author-name`} {
+		if text := normalizeSpace(doc.Find(sel).First().Text()); text != "" && len(text) < 100 {
+			return text
+		}
+	}
+	return ""
+}
+
+// prepareDocument removes elements that are never content
+func prepareDocument(doc *goquery.Document) {
+	doc.Find("script, style, noscript, template, link, meta, form, button, input, select, textarea, svg, canvas, nav, aside").Remove()
+
+	doc.Find("body *").Each(func(_ int, s *goquery.Selection) {
+		if s.Is("body, article, main") {
+			return
+		}
+		hints := classAndID(s)
+		if hints != "" && unlikelyCandidates.MatchString(hints) && !maybeCandidate.MatchString(hints) &&
+			s.Closest("article, main, [itemprop=articleBody]").Length() == 0 {
+			s.Remove()
+			return
+		}
+		if style, _ := s.Attr("style"); strings.Contains(strings.ReplaceAll(style, " ", ""), "display:none") {
+			s.Remove()
+		}
+	})
+
+	// header and footer are only boilerplate outside of the article itself
+	doc.Find("header, footer").Each(func(_ int, s *goquery.Selection) {
+		if s.Closest("article").Length() == 0 {
+			s.Remove()
+		}
+	})
+}
+
+// topCandidate scores containers by the paragraphs inside them and returns
+// the best one with the scores of all candidates
+func topCandidate(doc *goquery.Document) (*goquery.Selection, map[*html.Node]float64) {
+	scores := make(map[*html.Node]float64)
+	var order []*goquery.Selection
+
+	addScore := func(s *goquery.Selection, score float64) {
+		if s.Length() == 0 || s.Get(0).Type != html.ElementNode || s.Is("html") {
+			return
+		}
+		node := s.Get(0)
+		if _, ok := scores[node]; !ok {
+			scores[node] = initialScore(s)
+			order = append(order, s)
+		}
+		scores[node] += score
+	}
+
+	doc.Find("p, pre, td, blockquote, section > div, article > div").Each(func(_ int, s *goquery.Selection) {
+		text := normalizeSpace(s.Text())
+		if len(text) < 25 {
+			return
+		}
+		score := 1 + float64(strings.Count(text, ",")) + math.Min(float64(len(text))/100, 3)
+		addScore(s.Parent(), score)
+		addScore(s.Parent().Parent(), score/2)
+	})
+
+	var top *goquery.Selection
+	best := 0.0
+	for _, s := range order {
+		score := scores[s.Get(0)] * (1 - linkDensity(s))
+		scores[s.Get(0)] = score
+		if score > best {
+			best, top = score, s
+		}
+	}
+	if top == nil {
+		// No paragraphs, e.g. a page of divs: use the body
+		if body := doc.Find("body"); len(normalizeSpace(body.Text())) > 0 {
+			return body, scores
+		}
+		return nil, scores
+	}
+	return top, scores
+}
+
+// initialScore weighs a container by its tag and class hints
+func initialScore(s *goquery.Selection) float64 {
+	score := 0.0
+	switch goquery.NodeName(s) {
+	case "article":
+		score += 10
+	case "div", "main", "section":
+		score += 5
+	case "pre", "td", "blockquote":
+		score += 3
+	case "ol", "ul", "dl", "dd", "dt", "li", "form":
+		score -= 3
+	case "h1", "h2", "h3", "h4", "h5", "h6", "th":
+		score -= 5
+	}
+
+	hints := classAndID(s)
+	if negativeHints.MatchString(hints) {
+		score -= 25
+	}
+	if positiveHints.MatchString(hints) {
+		score += 25
+	}
+	return score
+}
+
+// collectContent returns the top candidate plus siblings that look like
+// part of the same article, such as a lead paragraph outside its container
+func collectContent(top *goquery.Selection, scores map[*html.Node]float64) *goquery.Selection {
+	if top.Is("body") || top.Parent().Length() == 0 {
+		return top
+	}
+
+	threshold := math.Max(10, scores[top.Get(0)]*0.2)
+	wrapper, _ := goquery.NewDocumentFromReader(strings.NewReader("<div></div>"))
+	container := wrapper.Find("div").First()
+
+	top.Parent().Children().Each(func(_ int, s *goquery.Selection) {
+		keep := s.Get(0) == top.Get(0)
+		if !keep {
+			if s.Is("p") {
+				text := normalizeSpace(s.Text())
+				density := linkDensity(s)
+				keep = (len(text) > 80 && density < 0.25) ||
+					(len(text) > 0 && density == 0 && strings.Contains(text, ". "))
+			} else if classAndID(s) != "" && classAndID(s) == classAndID(top) {
+				keep = true
+			}
+		}
+		if !keep && scores[s.Get(0)] >= threshold {
+			keep = true
+		}
+		if keep {
+			container.AppendSelection(s.Clone())
+		}
+	})
+	return container
+}
+
+// cleanContent drops leftovers that are mostly links or empty
+func cleanContent(content *goquery.Selection) {
+	content.Find("div, section, ul, ol, table").Each(func(_ int, s *goquery.Selection) {
+		text := normalizeSpace(s.Text())
+		if s.Find("img, iframe, video, pre").Length() > 0 {
+			return
+		}
+		if len(text) == 0 || (linkDensity(s) > 0.5 && len(text) < 500) || negativeHints.MatchString(classAndID(s)) && len(text) < 200 {
+			s.Remove()
+		}
+	})
+	content.Find("h1").First().Each(func(_ int, s *goquery.Selection) {
+		// The title is shown separately
+		s.Remove()
+	})
+	content.Find("*").Each(func(_ int, s *goquery.Selection) {
+		s.RemoveAttr("style")
+		s.RemoveAttr("class")
+		s.RemoveAttr("id")
+	})
+}
+
+// absolutize rewrites relative links and image sources against the page URL
+func absolutize(content *goquery.Selection, base *url.URL) {
+	for _, attr := range []struct{ sel, name string }{{"a[href]", "href"}, {"img[src]", "src"}, {"iframe[src]", "src"}} {
+		content.Find(attr.sel).Each(func(_ int, s *goquery.Selection) {
+			value, _ := s.Attr(attr.name)
+			if u, err := url.Parse(strings.TrimSpace(value)); err == nil && !strings.HasPrefix(value, "#") {
+				s.SetAttr(attr.name, base.ResolveReference(u).String())
+			}
+		})
+	}
+
+	// Lazy-loaded images keep the real source in a data attribute
+	content.Find("img").Each(func(_ int, s *goquery.Selection) {
+		for _, name := range []string{"data-src", "data-original", "data-lazy-src"} {
+			if value, ok := s.Attr(name); ok && value != "" {
+				if u, err := url.Parse(value); err == nil {
+					s.SetAttr("src", base.ResolveReference(u).String())
+				}
+				break
+			}
+		}
+	})
+}
+
+// linkDensity is the share of an element's text that is inside links
+func linkDensity(s *goquery.Selection) float64 {
+	text := len(normalizeSpace(s.Text()))
+	if text == 0 {
+		return 0
+	}
+	links := 0
+	s.Find("a").Each(func(_ int, a *goquery.Selection) {
+		links += len(normalizeSpace(a.Text()))
+	})
+	return float64(links) / float64(text)
+}
+
+func classAndID(s *goquery.Selection) string {
+	class, _ := s.Attr("class")
+	id, _ := s.Attr("id")
+	return strings.TrimSpace(class + " " + id)
+}
+
+func normalizeSpace(text string) string {
+	return strings.Join(strings.Fields(text), " ")
+}
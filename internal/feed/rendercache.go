@@ -0,0 +1,90 @@
+package feed
+
+import (
+	"container/list"
+	"sync"
+)
+
+// DefaultRenderCacheBytes bounds the rendered articles kept in memory
+const DefaultRenderCacheBytes = 32 * 1024 * 1024
+
+// lineOverhead approximates the memory of a line beyond its text
+const lineOverhead = 16
+
+// RenderKey identifies one rendering of an article
+type RenderKey struct {
+	ItemID string // The URL for pages that are not stored items
+	Full   bool   // The extracted article rather than the feed's content
+	Width  int
+	Theme  string
+}
+
+// RenderCache keeps rendered articles split into lines, dropping the least
+// recently used ones beyond a memory bound. It is safe for concurrent use.
+type RenderCache struct {
+	mu       sync.Mutex
+	maxBytes int
+	size     int
+	lru      *list.List // Most recently used first
+	entries  map[RenderKey]*list.Element
+}
+
+type renderEntry struct {
+	key   RenderKey
+	lines []string
+	size  int
+}
+
+// NewRenderCache creates a render cache holding up to maxBytes
+func NewRenderCache(maxBytes int) *RenderCache {
+	return &RenderCache{
+		maxBytes: maxBytes,
+		lru:      list.New(),
+		entries:  make(map[RenderKey]*list.Element),
+	}
+}
+
+// Get returns the lines of a rendering, if cached
+func (c *RenderCache) Get(key RenderKey) ([]string, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	el, ok := c.entries[key]
+	if !ok {
+		return nil, false
+	}
+	c.lru.MoveToFront(el)
+	return el.Value.(*renderEntry).lines, true
+}
+
+// Put caches a rendering. Renderings larger than the whole cache are not
+// kept.
+func (c *RenderCache) Put(key RenderKey, lines []string) {
+	size := 0
+	for _, line := range lines {
+		size += len(line) + lineOverhead
+	}
+	if size > c.maxBytes {
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if el, ok := c.entries[key]; ok {
+		c.size -= el.Value.(*renderEntry).size
+		c.lru.Remove(el)
+	}
+	c.entries[key] = c.lru.PushFront(&renderEntry{key: key, lines: lines, size: size})
+	c.size += size
+	for c.size > c.maxBytes {
+		oldest := c.lru.Remove(c.lru.Back()).(*renderEntry)
+		delete(c.entries, oldest.key)
+		c.size -= oldest.size
+	}
+}
+
+// Size returns the approximate memory held by the cache in bytes
+func (c *RenderCache) Size() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.size
+}
@@ -2,12 +2,10 @@ package feed
 
 import (
 	"fmt"
-	"net/http"
 	"regexp"
 	"strings"
 
 	"github.com/JohannesKaufmann/html-to-markdown"
-	"github.com/blacktop/go-termimg"
 	"github.com/charmbracelet/glamour"
 	"github.com/charmbracelet/lipgloss"
 )
@@ -19,11 +17,16 @@ type Renderer struct {
 	width           int
 }
 
-// NewRenderer creates a new content renderer
-func NewRenderer(width int) (*Renderer, error) {
+// NewRenderer creates a new content renderer. style is a glamour style
+// name ("auto", "dark", "light", ...) or the path of a JSON style file.
+func NewRenderer(width int, style string) (*Renderer, error) {
+	if style == "" {
+		style = "auto"
+	}
+
 	// Create glamour renderer for markdown
 	gr, err := glamour.NewTermRenderer(
-		glamour.WithAutoStyle(),
+		glamour.WithStylePath(style),
 		glamour.WithWordWrap(width-4), // Leave margin
 	)
 	if err != nil {
@@ -40,28 +43,14 @@ func NewRenderer(width int) (*Renderer, error) {
 	}, nil
 }
 
-// RenderContent renders article content (HTML or Markdown) to terminal
-func (r *Renderer) RenderContent(content string, isHTML bool) (string, error) {
-	// Convert HTML to markdown if needed
-	if isHTML {
-		markdown, err := r.htmlConverter.ConvertString(content)
-		if err != nil {
-			return "", fmt.Errorf("failed to convert HTML: %w", err)
-		}
-		content = markdown
-	}
-
-	// Render markdown with glamour
-	rendered, err := r.glamourRenderer.Render(content)
-	if err != nil {
-		return "", fmt.Errorf("failed to render markdown: %w", err)
-	}
-
-	return rendered, nil
+// IsHTML reports whether article content is HTML rather than markdown
+func IsHTML(content string) bool {
+	return strings.Contains(content, "<html") ||
+		strings.Contains(content, "<div")
 }
 
-// RenderContentWithInlineImages renders article content with images embedded inline
-func (r *Renderer) RenderContentWithInlineImages(content string, isHTML bool) (string, error) {
+// RenderContent renders article content (HTML or Markdown) to terminal
+func (r *Renderer) RenderContent(content string, isHTML bool) (string, error) {
 	// Convert HTML to markdown if needed
 	if isHTML {
 		markdown, err := r.htmlConverter.ConvertString(content)
@@ -71,32 +60,8 @@ func (r *Renderer) RenderContentWithInlineImages(content string, isHTML bool) (s
 		content = markdown
 	}
 
-	// Only embed images if terminal supports it
-	if SupportsInlineImages() {
-		// Extract image URLs
-		imgRegex := regexp.MustCompile(`!\[([^\]]*)\]\((https?://[^\)]+)\)`)
-		matches := imgRegex.FindAllStringSubmatch(content, -1)
-		
-		// Replace each image with inline terminal image
-		for _, match := range matches {
-			if len(match) >= 3 {
-				imageURL := match[2]
-				altText := match[1]
-				
-				// Try to render inline (constrained size for inline display)
-				inlineImg, err := r.RenderImageInline(imageURL, r.width-4, 20)
-				if err == nil && inlineImg != "" {
-					// Replace the markdown image with the terminal image
-					replacement := fmt.Sprintf("\n%s\n", inlineImg)
-					if altText != "" {
-						replacement += fmt.Sprintf("  %s\n", altText)
-					}
-					content = strings.Replace(content, match[0], replacement, 1)
-				}
-				// If error, leave the markdown as-is (glamour will handle it)
-			}
-		}
-	}
+	// Links become numbered references, see ExtractLinks
+	content, _ = numberLinks(content, "")
 
 	// Render markdown with glamour
 	rendered, err := r.glamourRenderer.Render(content)
@@ -114,35 +79,12 @@ func (r *Renderer) ExtractMedia(content string, articleURL string) *MediaLinks {
 		Videos: []VideoInfo{},
 	}
 
-	// First check if the article URL itself is a YouTube video (including Shorts)
-	if strings.Contains(articleURL, "youtube.com/watch") || strings.Contains(articleURL, "youtu.be/") || strings.Contains(articleURL, "youtube.com/shorts/") {
-		// Extract video ID from URL
-		var videoID string
-		if strings.Contains(articleURL, "youtube.com/watch?v=") {
-			parts := strings.Split(articleURL, "v=")
-			if len(parts) > 1 {
-				videoID = strings.Split(parts[1], "&")[0]
-			}
-		} else if strings.Contains(articleURL, "youtu.be/") {
-			parts := strings.Split(articleURL, "youtu.be/")
-			if len(parts) > 1 {
-				videoID = strings.Split(parts[1], "?")[0]
-			}
-		} else if strings.Contains(articleURL, "youtube.com/shorts/") {
-			parts := strings.Split(articleURL, "youtube.com/shorts/")
-			if len(parts) > 1 {
-				videoID = strings.Split(parts[1], "?")[0]
-				// Remove any trailing path
-				videoID = strings.Split(videoID, "/")[0]
-			}
-		}
-		
-		if videoID != "" {
-			media.Videos = append(media.Videos, VideoInfo{
-				URL:   fmt.Sprintf("https://www.youtube.com/watch?v=%s", videoID),
-				Title: "Video from feed",
-			})
-		}
+	// First check if the article URL itself is a video
+	if v, ok := ParseVideoURL(articleURL); ok {
+		media.Videos = append(media.Videos, VideoInfo{
+			URL:   v.URL,
+			Title: "Video from feed",
+		})
 	}
 
 	// Extract image URLs
@@ -163,92 +105,12 @@ func (r *Renderer) ExtractMedia(content string, articleURL string) *MediaLinks {
 		}
 	}
 
-	// Extract video URLs with metadata (YouTube, etc.)
-	// Try to extract YouTube embeds with title
-	youtubeEmbedRegex := regexp.MustCompile(`<iframe[^>]*src="https?://(?:www\.)?youtube\.com/embed/([\w-]+)"[^>]*(?:title="([^"]*)")?[^>]*>`)
-	youtubeMatches := youtubeEmbedRegex.FindAllStringSubmatch(content, -1)
-	for _, match := range youtubeMatches {
-		if len(match) > 1 {
-			videoID := match[1]
-			title := ""
-			if len(match) > 2 {
-				title = match[2]
-			}
-			url := fmt.Sprintf("https://www.youtube.com/watch?v=%s", videoID)
-			// Check if we already have this video
-			found := false
-			for _, v := range media.Videos {
-				if v.URL == url {
-					found = true
-					break
-				}
-			}
-			if !found {
-				media.Videos = append(media.Videos, VideoInfo{
-					URL:   url,
-					Title: title,
-				})
-			}
-		}
-	}
-
-	// Look for YouTube links in <a> tags
-	youtubeLinkRegex := regexp.MustCompile(`<a[^>]*href="(https?://(?:www\.)?(?:youtube\.com/watch\?v=|youtu\.be/)([\w-]+)[^"]*)"`)
-	linkMatches := youtubeLinkRegex.FindAllStringSubmatch(content, -1)
-	for _, match := range linkMatches {
-		if len(match) > 2 {
-			videoID := match[2]
-			url := fmt.Sprintf("https://www.youtube.com/watch?v=%s", videoID)
-			// Check if we already have this video
-			found := false
-			for _, v := range media.Videos {
-				if v.URL == url {
-					found = true
-					break
-				}
-			}
-			if !found {
-				media.Videos = append(media.Videos, VideoInfo{
-					URL: url,
-				})
-			}
-		}
-	}
-
-	// Extract plain YouTube URLs from text
-	videoPatterns := []struct {
-		pattern string
-		urlFunc func(string) string
-	}{
-		{`https?://(?:www\.)?youtube\.com/watch\?v=([\w-]+)`, func(id string) string { return fmt.Sprintf("https://www.youtube.com/watch?v=%s", id) }},
-		{`https?://(?:www\.)?youtube\.com/shorts/([\w-]+)`, func(id string) string { return fmt.Sprintf("https://www.youtube.com/watch?v=%s", id) }},
-		{`https?://youtu\.be/([\w-]+)`, func(id string) string { return fmt.Sprintf("https://www.youtube.com/watch?v=%s", id) }},
-		{`https?://(?:www\.)?vimeo\.com/(\d+)`, func(id string) string { return fmt.Sprintf("https://vimeo.com/%s", id) }},
-		{`(https?://[^\s]+\.mp4)`, func(url string) string { return url }},
-		{`(https?://[^\s]+\.webm)`, func(url string) string { return url }},
-	}
-
-	for _, p := range videoPatterns {
-		regex := regexp.MustCompile(p.pattern)
-		matches := regex.FindAllStringSubmatch(content, -1)
-		for _, match := range matches {
-			if len(match) > 1 {
-				// Check if we already have this video from embed
-				url := p.urlFunc(match[1])
-				found := false
-				for _, v := range media.Videos {
-					if v.URL == url {
-						found = true
-						break
-					}
-				}
-				if !found {
-					media.Videos = append(media.Videos, VideoInfo{
-						URL: url,
-					})
-				}
-			}
+	// Videos embedded or linked in the content
+	for _, v := range FindVideos(content) {
+		if len(media.Videos) > 0 && media.Videos[0].URL == v.URL {
+			continue // The article itself
 		}
+		media.Videos = append(media.Videos, v)
 	}
 
 	return media
@@ -323,72 +185,3 @@ type VideoInfo struct {
 	Title       string
 	Description string
 }
-
-// RenderImageInline attempts to display an image inline in the terminal
-// Returns the rendered image string if successful, empty string if terminal doesn't support it
-func (r *Renderer) RenderImageInline(imageURL string, maxWidth, maxHeight int) (string, error) {
-	// Download the image
-	resp, err := http.Get(imageURL)
-	if err != nil {
-		return "", fmt.Errorf("failed to download image: %w", err)
-	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode != http.StatusOK {
-		return "", fmt.Errorf("failed to download image: status %d", resp.StatusCode)
-	}
-
-	// Create image from reader
-	img, err := termimg.From(resp.Body)
-	if err != nil {
-		return "", fmt.Errorf("failed to parse image: %w", err)
-	}
-
-	// Set size constraints
-	if maxWidth > 0 {
-		img = img.Width(maxWidth)
-	}
-	if maxHeight > 0 {
-		img = img.Height(maxHeight)
-	}
-
-	// Render to string (auto-detects best protocol)
-	rendered, err := img.Render()
-	if err != nil {
-		return "", fmt.Errorf("failed to render image: %w", err)
-	}
-
-	return rendered, nil
-}
-
-// SupportsInlineImages checks if the terminal supports inline images
-func SupportsInlineImages() bool {
-	// termimg will auto-detect and fallback, but we can check
-	// if any of the protocols are available
-	return termimg.KittySupported() || termimg.SixelSupported() || termimg.ITerm2Supported()
-}
-
-// RenderImageInlineFromFile attempts to display a cached image inline in the terminal
-func (r *Renderer) RenderImageInlineFromFile(imagePath string, maxWidth, maxHeight int) (string, error) {
-// Open cached image file
-img, err := termimg.Open(imagePath)
-if err != nil {
-return "", fmt.Errorf("failed to open cached image: %w", err)
-}
-
-// Set size constraints
-if maxWidth > 0 {
-img = img.Width(maxWidth)
-}
-if maxHeight > 0 {
-img = img.Height(maxHeight)
-}
-
-// Render to string (auto-detects best protocol)
-rendered, err := img.Render()
-if err != nil {
-return "", fmt.Errorf("failed to render image: %w", err)
-}
-
-return rendered, nil
-}
@@ -0,0 +1,271 @@
+package feed
+
+import (
+	"fmt"
+	"html"
+	"net/url"
+	"path"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// VideoPlatform names where a video is hosted
+type VideoPlatform string
+
+const (
+	YouTube  VideoPlatform = "youtube"
+	Vimeo    VideoPlatform = "vimeo"
+	PeerTube VideoPlatform = "peertube"
+	Odysee   VideoPlatform = "odysee"
+	Rumble   VideoPlatform = "rumble"
+	Direct   VideoPlatform = "direct" // A video file or HLS stream
+)
+
+// VideoURL is a link recognized as a video
+type VideoURL struct {
+	Platform VideoPlatform
+	ID       string        // The platform's video ID, "" for playlists and direct files
+	Playlist string        // YouTube playlist ID
+	Start    time.Duration // Where to start playing, 0 for the beginning
+	URL      string        // Canonical URL to hand a player
+}
+
+var (
+	youtubeID = regexp.MustCompile(`^[\w-]{11}$`)
+	vimeoID   = regexp.MustCompile(`^\d+$`)
+	rumbleID  = regexp.MustCompile(`^(v[0-9a-z]*[0-9][0-9a-z]*)(?:-.*)?(?:\.html)?$`)
+	// Base58 short UUIDs or dashed UUIDs, so /w/ slugs of other sites are
+	// not taken for videos
+	peertubeID = regexp.MustCompile(`^(?:[1-9A-HJ-NP-Za-km-z]{22}|[0-9a-f]{8}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{12})$`)
+)
+
+// directVideoExts are files players can open without a site extractor
+var directVideoExts = map[string]bool{
+	".mp4": true, ".m4v": true, ".webm": true, ".mov": true, ".mkv": true, ".m3u8": true,
+}
+
+// ParseVideoURL recognizes video links on YouTube (watch, shorts, embed,
+// live, youtu.be and playlists), Vimeo, PeerTube instances, Odysee, Rumble,
+// and direct mp4/webm/HLS files. Timestamps (t=, start=, #t=) are kept.
+func ParseVideoURL(raw string) (*VideoURL, bool) {
+	u, err := url.Parse(strings.TrimSpace(raw))
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return nil, false
+	}
+	host := strings.TrimPrefix(strings.ToLower(u.Hostname()), "www.")
+	segments := strings.FieldsFunc(u.Path, func(r rune) bool { return r == '/' })
+
+	var v *VideoURL
+	switch host {
+	case "youtube.com", "m.youtube.com", "music.youtube.com", "youtube-nocookie.com":
+		v = parseYouTube(u, segments)
+	case "youtu.be":
+		if len(segments) == 1 && youtubeID.MatchString(segments[0]) {
+			v = &VideoURL{Platform: YouTube, ID: segments[0], Playlist: u.Query().Get("list")}
+		}
+	case "vimeo.com", "player.vimeo.com":
+		if n := len(segments); n > 0 && vimeoID.MatchString(segments[n-1]) {
+			v = &VideoURL{Platform: Vimeo, ID: segments[n-1]}
+		}
+	case "odysee.com":
+		v = parseOdysee(segments)
+	case "rumble.com":
+		v = parseRumble(segments)
+	default:
+		v = parsePeerTube(segments)
+	}
+	if v == nil {
+		if !directVideoExts[strings.ToLower(path.Ext(u.Path))] {
+			return nil, false
+		}
+		u.Fragment = ""
+		return &VideoURL{Platform: Direct, URL: u.String()}, true
+	}
+
+	v.Start = videoStart(u)
+	v.URL = v.canonical(u)
+	return v, true
+}
+
+func parseYouTube(u *url.URL, segments []string) *VideoURL {
+	query := u.Query()
+	if len(segments) == 1 && segments[0] == "playlist" {
+		if list := query.Get("list"); list != "" {
+			return &VideoURL{Platform: YouTube, Playlist: list}
+		}
+		return nil
+	}
+
+	var id string
+	switch {
+	case len(segments) == 1 && segments[0] == "watch":
+		id = query.Get("v")
+	case len(segments) >= 2 && (segments[0] == "shorts" || segments[0] == "embed" ||
+		segments[0] == "live" || segments[0] == "v"):
+		id = segments[1]
+	}
+	if !youtubeID.MatchString(id) {
+		return nil
+	}
+	return &VideoURL{Platform: YouTube, ID: id, Playlist: query.Get("list")}
+}
+
+// parseOdysee takes "@channel:c/name:claim" pages and "$/embed/name/claim"
+// players; channel pages alone are not videos
+func parseOdysee(segments []string) *VideoURL {
+	switch {
+	case len(segments) == 2 && strings.HasPrefix(segments[0], "@"):
+		return &VideoURL{Platform: Odysee, ID: segments[0] + "/" + segments[1]}
+	case len(segments) == 4 && segments[0] == "$" && segments[1] == "embed":
+		return &VideoURL{Platform: Odysee, ID: segments[2] + ":" + segments[3]}
+	case len(segments) == 1 && !strings.HasPrefix(segments[0], "@") && strings.Contains(segments[0], ":"):
+		return &VideoURL{Platform: Odysee, ID: segments[0]}
+	}
+	return nil
+}
+
+// parseRumble takes "v4abc-some-title.html" pages and "embed/v4xyz"
+// players. Pages and players number videos differently, so each keeps
+// its own kind of URL.
+func parseRumble(segments []string) *VideoURL {
+	switch {
+	case len(segments) == 1:
+		if m := rumbleID.FindStringSubmatch(segments[0]); m != nil {
+			return &VideoURL{Platform: Rumble, ID: m[1], URL: "https://rumble.com/" + segments[0]}
+		}
+	case len(segments) == 2 && segments[0] == "embed":
+		if m := rumbleID.FindStringSubmatch(segments[1]); m != nil {
+			return &VideoURL{Platform: Rumble, ID: m[1], URL: "https://rumble.com/embed/" + m[1] + "/"}
+		}
+	}
+	return nil
+}
+
+// parsePeerTube recognizes the paths every PeerTube instance uses, since
+// instances can be on any host
+func parsePeerTube(segments []string) *VideoURL {
+	var id string
+	switch {
+	case len(segments) == 2 && segments[0] == "w":
+		id = segments[1]
+	case len(segments) == 3 && segments[0] == "videos" && (segments[1] == "watch" || segments[1] == "embed"):
+		id = segments[2]
+	}
+	if !peertubeID.MatchString(id) {
+		return nil
+	}
+	return &VideoURL{Platform: PeerTube, ID: id}
+}
+
+// canonical builds the URL to play, dropping tracking parameters
+func (v *VideoURL) canonical(u *url.URL) string {
+	switch v.Platform {
+	case YouTube:
+		if v.ID == "" {
+			return "https://www.youtube.com/playlist?list=" + url.QueryEscape(v.Playlist)
+		}
+		s := "https://www.youtube.com/watch?v=" + v.ID
+		if v.Playlist != "" {
+			s += "&list=" + url.QueryEscape(v.Playlist)
+		}
+		if v.Start > 0 {
+			s += fmt.Sprintf("&t=%ds", int(v.Start.Seconds()))
+		}
+		return s
+	case Vimeo:
+		s := "https://vimeo.com/" + v.ID
+		if v.Start > 0 {
+			s += fmt.Sprintf("#t=%ds", int(v.Start.Seconds()))
+		}
+		return s
+	case PeerTube:
+		s := u.Scheme + "://" + u.Host + "/w/" + v.ID
+		if v.Start > 0 {
+			s += fmt.Sprintf("?start=%ds", int(v.Start.Seconds()))
+		}
+		return s
+	case Odysee:
+		return "https://odysee.com/" + v.ID
+	case Rumble:
+		return v.URL // Set by parseRumble
+	}
+	return u.String()
+}
+
+// videoStart reads a timestamp from t= or start= in the query or fragment
+func videoStart(u *url.URL) time.Duration {
+	fragment, _ := url.ParseQuery(u.Fragment)
+	query := u.Query()
+	for _, value := range []string{query.Get("t"), query.Get("start"), fragment.Get("t")} {
+		if d := parseTimestamp(value); d > 0 {
+			return d
+		}
+	}
+	return 0
+}
+
+var timestampPart = regexp.MustCompile(`(\d+)([hms])`)
+
+// parseTimestamp parses "90", "90s", "1m30s" and "1h2m3s"
+func parseTimestamp(value string) time.Duration {
+	if value == "" {
+		return 0
+	}
+	if seconds, err := strconv.Atoi(value); err == nil {
+		return time.Duration(seconds) * time.Second
+	}
+	if timestampPart.ReplaceAllString(value, "") != "" {
+		return 0
+	}
+	var d time.Duration
+	for _, m := range timestampPart.FindAllStringSubmatch(value, -1) {
+		n, _ := strconv.Atoi(m[1])
+		switch m[2] {
+		case "h":
+			d += time.Duration(n) * time.Hour
+		case "m":
+			d += time.Duration(n) * time.Minute
+		case "s":
+			d += time.Duration(n) * time.Second
+		}
+	}
+	return d
+}
+
+var (
+	urlPattern    = regexp.MustCompile(`https?://[^\s"'<>()\[\]]+`)
+	iframePattern = regexp.MustCompile(`(?is)<iframe\b[^>]*>`)
+	srcAttr       = regexp.MustCompile(`(?i)\bsrc="([^"]+)"`)
+	titleAttr     = regexp.MustCompile(`(?i)\btitle="([^"]*)"`)
+)
+
+// FindVideos returns the videos linked or embedded in content, once each
+// in order of appearance. Embedded players come with their titles.
+func FindVideos(content string) []VideoInfo {
+	var videos []VideoInfo
+	seen := make(map[string]bool)
+	add := func(raw, title string) {
+		v, ok := ParseVideoURL(html.UnescapeString(strings.TrimRight(raw, ".,;:!?")))
+		if !ok || seen[v.URL] {
+			return
+		}
+		seen[v.URL] = true
+		videos = append(videos, VideoInfo{URL: v.URL, Title: title})
+	}
+
+	for _, tag := range iframePattern.FindAllString(content, -1) {
+		if src := srcAttr.FindStringSubmatch(tag); src != nil {
+			title := ""
+			if t := titleAttr.FindStringSubmatch(tag); t != nil {
+				title = html.UnescapeString(t[1])
+			}
+			add(src[1], title)
+		}
+	}
+	for _, raw := range urlPattern.FindAllString(content, -1) {
+		add(raw, "")
+	}
+	return videos
+}
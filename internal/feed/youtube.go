@@ -0,0 +1,163 @@
+package feed
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/mmcdole/gofeed"
+	ext "github.com/mmcdole/gofeed/extensions"
+	"github.com/plebone/nostrfeedz-cli/internal/db"
+)
+
+// youtubeFeedBase is where YouTube serves the Atom feeds of channels and
+// playlists
+const youtubeFeedBase = "https://www.youtube.com/feeds/videos.xml"
+
+var (
+	youtubeChannelID = regexp.MustCompile(`^UC[\w-]{22}$`)
+	// Where a channel page names its own channel: the canonical link, the
+	// RSS link, or the page data
+	youtubePageChannel = []*regexp.Regexp{
+		regexp.MustCompile(`<link rel="canonical" href="https://www\.youtube\.com/channel/(UC[\w-]{22})"`),
+		regexp.MustCompile(`feeds/videos\.xml\?channel_id=(UC[\w-]{22})`),
+		regexp.MustCompile(`"externalId":"(UC[\w-]{22})"`),
+	}
+)
+
+// IsYouTubeFeed reports whether a link is a YouTube channel (by ID, handle
+// or legacy name), a playlist, or one of their Atom feeds
+func IsYouTubeFeed(link string) bool {
+	u, segments, ok := youtubeLink(link)
+	if !ok {
+		return false
+	}
+	if _, ok := youtubeFeedURL(u, segments); ok {
+		return true
+	}
+	return youtubeChannelPath(segments) != ""
+}
+
+// ResolveYouTubeFeed returns the Atom feed of a YouTube channel, handle or
+// playlist link. Channel IDs and playlists map to their feed directly;
+// handles and legacy names take fetching the channel page.
+func ResolveYouTubeFeed(ctx context.Context, link string) (string, error) {
+	u, segments, ok := youtubeLink(link)
+	if !ok {
+		return "", fmt.Errorf("not a YouTube link: %s", link)
+	}
+	if feedURL, ok := youtubeFeedURL(u, segments); ok {
+		return feedURL, nil
+	}
+	channel := youtubeChannelPath(segments)
+	if channel == "" {
+		return "", fmt.Errorf("not a YouTube channel or playlist: %s", link)
+	}
+
+	page := "https://www.youtube.com/" + channel
+	body, _, err := fetchBody(ctx, page)
+	if err != nil {
+		return "", err
+	}
+	for _, pattern := range youtubePageChannel {
+		if m := pattern.FindSubmatch(body); m != nil {
+			return youtubeFeedBase + "?channel_id=" + string(m[1]), nil
+		}
+	}
+	return "", fmt.Errorf("no channel found at %s", page)
+}
+
+// youtubeLink splits a link on a YouTube host into its path segments
+func youtubeLink(link string) (*url.URL, []string, bool) {
+	u, err := url.Parse(strings.TrimSpace(link))
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") {
+		return nil, nil, false
+	}
+	switch strings.TrimPrefix(strings.ToLower(u.Hostname()), "www.") {
+	case "youtube.com", "m.youtube.com":
+	default:
+		return nil, nil, false
+	}
+	return u, strings.FieldsFunc(u.Path, func(r rune) bool { return r == '/' }), true
+}
+
+// youtubeFeedURL maps feeds, channel IDs and playlists to their feed
+// without fetching anything
+func youtubeFeedURL(u *url.URL, segments []string) (string, bool) {
+	query := u.Query()
+	switch {
+	case len(segments) == 2 && segments[0] == "feeds" && segments[1] == "videos.xml":
+		if id := query.Get("channel_id"); youtubeChannelID.MatchString(id) {
+			return youtubeFeedBase + "?channel_id=" + id, true
+		}
+		if list := query.Get("playlist_id"); list != "" {
+			return youtubeFeedBase + "?playlist_id=" + url.QueryEscape(list), true
+		}
+	case len(segments) >= 2 && segments[0] == "channel" && youtubeChannelID.MatchString(segments[1]):
+		return youtubeFeedBase + "?channel_id=" + segments[1], true
+	case len(segments) == 1 && segments[0] == "playlist" && query.Get("list") != "":
+		return youtubeFeedBase + "?playlist_id=" + url.QueryEscape(query.Get("list")), true
+	}
+	return "", false
+}
+
+// youtubeChannelPath returns the path of a channel known by its handle
+// (@name) or legacy name (c/name, user/name) without tabs such as /videos,
+// or "" if the path is not one
+func youtubeChannelPath(segments []string) string {
+	switch {
+	case len(segments) >= 1 && len(segments[0]) > 1 && strings.HasPrefix(segments[0], "@"):
+		return segments[0]
+	case len(segments) >= 2 && (segments[0] == "c" || segments[0] == "user"):
+		return segments[0] + "/" + segments[1]
+	}
+	return ""
+}
+
+// parseMediaRSS reads the Media RSS details of a video item: the
+// media:group YouTube and PeerTube put in each entry, or media elements
+// straight in the item. It returns nil when the item has none.
+func parseMediaRSS(item *gofeed.Item) *db.VideoMeta {
+	media := item.Extensions["media"]
+	if media == nil {
+		return nil
+	}
+	elements := map[string][]ext.Extension(media)
+	if groups := media["group"]; len(groups) > 0 {
+		elements = groups[0].Children
+	}
+
+	meta := &db.VideoMeta{}
+	if thumbnails := elements["thumbnail"]; len(thumbnails) > 0 {
+		meta.Thumbnail = thumbnails[0].Attrs["url"]
+	}
+	if descriptions := elements["description"]; len(descriptions) > 0 {
+		meta.Description = strings.TrimSpace(descriptions[0].Value)
+	}
+	if communities := elements["community"]; len(communities) > 0 {
+		if stats := communities[0].Children["statistics"]; len(stats) > 0 {
+			meta.Views, _ = strconv.ParseInt(stats[0].Attrs["views"], 10, 64)
+		}
+	}
+	if *meta == (db.VideoMeta{}) {
+		return nil
+	}
+	return meta
+}
+
+// youtubeVideoID returns the yt:videoId of a YouTube feed entry
+func youtubeVideoID(item *gofeed.Item) string {
+	if ids := item.Extensions["yt"]["videoId"]; len(ids) > 0 {
+		return ids[0].Value
+	}
+	return ""
+}
+
+// descriptionContent turns a plain text video description into markdown
+// that keeps its line breaks
+func descriptionContent(description string) string {
+	return strings.ReplaceAll(description, "\n", "  \n")
+}
@@ -0,0 +1,305 @@
+// Package keymap maps key presses to named actions per view. Bindings start
+// from a preset and can be overridden from the keys section of config.yaml.
+package keymap
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Context is the view a binding applies to
+type Context string
+
+const (
+	Global   Context = "global"
+	Feeds    Context = "feeds"
+	Articles Context = "articles"
+	Reader   Context = "reader"
+	Relays   Context = "relays"
+	Logs     Context = "logs"
+	Compose  Context = "compose"
+	Links    Context = "links"
+	Gallery  Context = "gallery"
+	Podcasts Context = "podcasts"
+)
+
+// Contexts lists every context in help order
+var Contexts = []Context{Global, Feeds, Articles, Reader, Relays, Logs, Compose, Links, Gallery, Podcasts}
+
+// Action is a named command that keys are bound to
+type Action string
+
+const (
+	// Global
+	Quit       Action = "quit"
+	Help       Action = "help"
+	CycleTheme Action = "theme"
+
+	// Shared navigation
+	Up       Action = "up"
+	Down     Action = "down"
+	PageUp   Action = "page_up"
+	PageDown Action = "page_down"
+	Open     Action = "open"
+	Back     Action = "back"
+
+	// Feeds
+	SwitchView   Action = "switch_view"
+	Sync         Action = "sync"
+	ShowRelays   Action = "relays"
+	ShowLogs     Action = "logs"
+	NewFilter    Action = "new_filter"
+	DeleteFilter Action = "delete_filter"
+	FullContent  Action = "full_content"
+	ArchiveFeed  Action = "archive"
+	Export       Action = "export"
+	ShowPodcasts Action = "podcasts"
+
+	// Articles
+	Refresh    Action = "refresh"
+	MarkToggle Action = "mark_toggle"
+	MarkAbove  Action = "mark_above_read"
+	MarkAll    Action = "mark_all_read"
+	ToggleStar Action = "toggle_star"
+	Select     Action = "select"
+	Enqueue    Action = "enqueue"
+
+	// Reader
+	OpenBrowser   Action = "open_browser"
+	ViewImage     Action = "view_image"
+	ExternalImage Action = "external_image"
+	CloseImage    Action = "close_image"
+	PrevImage     Action = "prev_image"
+	NextImage     Action = "next_image"
+	PlayVideo     Action = "play_video"
+	PrevVideo     Action = "prev_video"
+	NextVideo     Action = "next_video"
+	Share         Action = "share"
+	Like          Action = "like"
+	Reply         Action = "reply"
+	Thread        Action = "thread"
+	Zap           Action = "zap"
+	ShowLinks     Action = "links"
+	FullArticle   Action = "full_article"
+	ShowGallery   Action = "gallery"
+
+	// Relays
+	AddRelay    Action = "add"
+	RemoveRelay Action = "remove"
+	ToggleRead  Action = "toggle_read"
+	ToggleWrite Action = "toggle_write"
+	AuthPolicy  Action = "auth_policy"
+	CheckRelays Action = "check"
+	PublishList Action = "publish"
+
+	// Logs
+	Follow   Action = "follow"
+	LogLevel Action = "level"
+
+	// Compose
+	ToggleRelay Action = "toggle_relay"
+	EditNote    Action = "edit"
+
+	// Links
+	Subscribe Action = "subscribe"
+
+	// Podcasts
+	PlayPause   Action = "play_pause"
+	SeekBack    Action = "seek_back"
+	SeekForward Action = "seek_forward"
+	SlowDown    Action = "slower"
+	SpeedUp     Action = "faster"
+	Dequeue     Action = "dequeue"
+	StopPlaying Action = "stop"
+)
+
+// Binding is the set of keys for one action
+type Binding struct {
+	Action Action
+	Keys   []string
+	Help   string
+}
+
+// Conflict is a key bound to more than one action where both can fire
+type Conflict struct {
+	Context Context
+	Key     string
+	Actions []string // "context.action" for each clashing binding
+}
+
+func (c Conflict) Error() string {
+	return fmt.Sprintf("key %q in %s is bound to %s", c.Key, c.Context, strings.Join(c.Actions, " and "))
+}
+
+// Keymap resolves key presses to actions
+type Keymap struct {
+	bindings map[Context][]Binding
+	lookup   map[Context]map[string]Action
+}
+
+// New builds a keymap from a preset plus per-context overrides, keyed by
+// context name then action name. Overrides replace the preset's keys for
+// that action. Conflicts are returned alongside a usable keymap; where keys
+// clash the context binding wins over global and the first action listed
+// wins within a context.
+func New(preset string, overrides map[string]map[string][]string) (*Keymap, []Conflict, error) {
+	bindings, err := presetBindings(preset)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	for ctxName, actions := range overrides {
+		ctx := Context(ctxName)
+		list, ok := bindings[ctx]
+		if !ok {
+			return nil, nil, fmt.Errorf("unknown key context %q", ctxName)
+		}
+		for actionName, keys := range actions {
+			idx := -1
+			for i, b := range list {
+				if string(b.Action) == actionName {
+					idx = i
+					break
+				}
+			}
+			if idx < 0 {
+				return nil, nil, fmt.Errorf("unknown action %q in key context %s", actionName, ctxName)
+			}
+			list[idx].Keys = normalizeKeys(keys)
+		}
+	}
+
+	k := &Keymap{bindings: bindings, lookup: make(map[Context]map[string]Action)}
+	for _, ctx := range Contexts {
+		k.lookup[ctx] = make(map[string]Action)
+		for _, b := range bindings[ctx] {
+			for _, key := range b.Keys {
+				if _, taken := k.lookup[ctx][key]; !taken {
+					k.lookup[ctx][key] = b.Action
+				}
+			}
+		}
+	}
+
+	return k, k.conflicts(), nil
+}
+
+// Default returns the default preset with no overrides
+func Default() *Keymap {
+	k, _, _ := New("default", nil)
+	return k
+}
+
+// Action returns the action for a key in a context, falling back to global
+// bindings. It returns "" when the key is unbound.
+func (k *Keymap) Action(ctx Context, key string) Action {
+	if action, ok := k.lookup[ctx][key]; ok {
+		return action
+	}
+	return k.lookup[Global][key]
+}
+
+// Bindings returns the bindings of a context in display order
+func (k *Keymap) Bindings(ctx Context) []Binding {
+	return k.bindings[ctx]
+}
+
+// Keys returns a short label for an action's keys, e.g. "k/↑"
+func (k *Keymap) Keys(ctx Context, action Action) string {
+	for _, c := range []Context{ctx, Global} {
+		for _, b := range k.bindings[c] {
+			if b.Action == action {
+				labels := make([]string, len(b.Keys))
+				for i, key := range b.Keys {
+					labels[i] = Label(key)
+				}
+				return strings.Join(labels, "/")
+			}
+		}
+	}
+	return ""
+}
+
+// Label returns a compact display form of a key
+func Label(key string) string {
+	switch key {
+	case "up":
+		return "↑"
+	case "down":
+		return "↓"
+	case "left":
+		return "←"
+	case "right":
+		return "→"
+	case "shift+left":
+		return "⇧←"
+	case "shift+right":
+		return "⇧→"
+	case " ":
+		return "space"
+	}
+	return key
+}
+
+// conflicts finds keys bound twice within a context, and context keys that
+// shadow a global binding
+func (k *Keymap) conflicts() []Conflict {
+	var conflicts []Conflict
+	for _, ctx := range Contexts {
+		owners := make(map[string][]string)
+		var order []string
+		add := func(c Context, b Binding) {
+			for _, key := range b.Keys {
+				if _, seen := owners[key]; !seen {
+					order = append(order, key)
+				}
+				owners[key] = append(owners[key], string(c)+"."+string(b.Action))
+			}
+		}
+		if ctx != Global {
+			for _, b := range k.bindings[Global] {
+				add(Global, b)
+			}
+		}
+		for _, b := range k.bindings[ctx] {
+			add(ctx, b)
+		}
+
+		for _, key := range order {
+			if len(owners[key]) > 1 {
+				conflicts = append(conflicts, Conflict{Context: ctx, Key: key, Actions: owners[key]})
+			}
+		}
+	}
+
+	// Global clashes are found once per context; keep the first
+	seen := make(map[string]bool)
+	var unique []Conflict
+	for _, c := range conflicts {
+		id := c.Key + "|" + strings.Join(c.Actions, ",")
+		if !seen[id] {
+			seen[id] = true
+			unique = append(unique, c)
+		}
+	}
+	sort.SliceStable(unique, func(i, j int) bool {
+		return unique[i].Context < unique[j].Context
+	})
+	return unique
+}
+
+func normalizeKeys(keys []string) []string {
+	out := make([]string, 0, len(keys))
+	for _, key := range keys {
+		if key == "space" {
+			key = " "
+		} else {
+			key = strings.TrimSpace(key)
+		}
+		if key != "" {
+			out = append(out, key)
+		}
+	}
+	return out
+}
@@ -0,0 +1,185 @@
+package keymap
+
+import "fmt"
+
+// Presets lists the available preset names
+var Presets = []string{"default", "vim", "emacs", "arrows"}
+
+// navKeys holds the movement keys that differ between presets
+type navKeys struct {
+	up, down, pageUp, pageDown []string
+	left, right                []string
+	shiftLeft, shiftRight      []string
+	back, follow               []string
+}
+
+var presetNav = map[string]navKeys{
+	// Vim motions plus arrow keys, the original bindings
+	"default": {
+		up: []string{"up", "k"}, down: []string{"down", "j"},
+		pageUp: []string{"pgup"}, pageDown: []string{"pgdown", " "},
+		left: []string{"left", "h"}, right: []string{"right", "l"},
+		shiftLeft: []string{"shift+left", "H"}, shiftRight: []string{"shift+right", "L"},
+		back: []string{"esc"}, follow: []string{"G", "end"},
+	},
+	"vim": {
+		up: []string{"k"}, down: []string{"j"},
+		pageUp: []string{"ctrl+b", "ctrl+u"}, pageDown: []string{"ctrl+f", "ctrl+d"},
+		left: []string{"h"}, right: []string{"l"},
+		shiftLeft: []string{"H"}, shiftRight: []string{"L"},
+		back: []string{"esc"}, follow: []string{"G"},
+	},
+	"emacs": {
+		up: []string{"ctrl+p", "up"}, down: []string{"ctrl+n", "down"},
+		pageUp: []string{"alt+v", "pgup"}, pageDown: []string{"ctrl+v", "pgdown"},
+		left: []string{"ctrl+b", "left"}, right: []string{"ctrl+f", "right"},
+		shiftLeft: []string{"alt+b", "shift+left"}, shiftRight: []string{"alt+f", "shift+right"},
+		back: []string{"ctrl+g", "esc"}, follow: []string{"alt+>", "end"},
+	},
+	"arrows": {
+		up: []string{"up"}, down: []string{"down"},
+		pageUp: []string{"pgup"}, pageDown: []string{"pgdown", " "},
+		left: []string{"left"}, right: []string{"right"},
+		shiftLeft: []string{"shift+left"}, shiftRight: []string{"shift+right"},
+		back: []string{"esc", "backspace"}, follow: []string{"end"},
+	},
+}
+
+// presetBindings builds a fresh copy of a preset's bindings
+func presetBindings(name string) (map[Context][]Binding, error) {
+	if name == "" {
+		name = "default"
+	}
+	nav, ok := presetNav[name]
+	if !ok {
+		return nil, fmt.Errorf("unknown key preset %q (want one of %v)", name, Presets)
+	}
+
+	keys := func(k ...string) []string { return k }
+
+	return map[Context][]Binding{
+		Global: {
+			{Quit, keys("q", "ctrl+c"), "Quit"},
+			{Help, keys("?"), "Toggle this help"},
+			{CycleTheme, keys("T"), "Switch to the next theme"},
+		},
+		Feeds: {
+			{Up, nav.up, "Previous item"},
+			{Down, nav.down, "Next item"},
+			{Open, keys("enter"), "Open feed, tag or category"},
+			{SwitchView, keys("tab"), "Cycle feeds / tags / categories / views"},
+			{Sync, keys("s"), "Sync from Nostr"},
+			{ShowRelays, keys("R"), "Relay panel"},
+			{ShowLogs, keys("L"), "Log viewer"},
+			{MarkAll, keys("M"), "Mark feed, tag, category or view read"},
+			{NewFilter, keys("n"), "Save a new filter view"},
+			{DeleteFilter, keys("x"), "Delete saved filter view"},
+			{FullContent, keys("F"), "Toggle full article extraction for a feed"},
+			{ArchiveFeed, keys("A"), "Toggle archiving all articles of a feed"},
+			{Export, keys("E"), "Export the offline archive"},
+			{ShowPodcasts, keys("P"), "Podcast queue"},
+		},
+		Articles: {
+			{Up, nav.up, "Previous article"},
+			{Down, nav.down, "Next article"},
+			{Open, keys("enter"), "Read article"},
+			{Refresh, keys("r"), "Fetch new articles"},
+			{MarkToggle, keys("m"), "Toggle read / unread"},
+			{MarkAbove, keys("K"), "Mark everything above read"},
+			{MarkAll, keys("M"), "Mark all read"},
+			{ToggleStar, keys("f"), "Star / unstar"},
+			{Select, keys(" "), "Select / deselect for export"},
+			{Export, keys("E"), "Export the selection, or every listed article"},
+			{Enqueue, keys("a"), "Add the episode to the podcast queue"},
+			{Back, nav.back, "Back to feeds"},
+		},
+		Reader: {
+			{Up, nav.up, "Scroll up"},
+			{Down, nav.down, "Scroll down"},
+			{PageUp, nav.pageUp, "Page up"},
+			{PageDown, nav.pageDown, "Page down"},
+			{OpenBrowser, keys("o"), "Open in browser"},
+			{MarkToggle, keys("m"), "Toggle read / unread"},
+			{ToggleStar, keys("f"), "Star / unstar"},
+			{ViewImage, keys("i"), "View image"},
+			{ExternalImage, keys("I"), "Open image in external viewer"},
+			{CloseImage, keys("x"), "Close image"},
+			{PrevImage, nav.left, "Previous image"},
+			{NextImage, nav.right, "Next image"},
+			{PlayVideo, keys("v"), "Play video"},
+			{PrevVideo, nav.shiftLeft, "Previous video"},
+			{NextVideo, nav.shiftRight, "Next video"},
+			{Share, keys("s"), "Share to Nostr"},
+			{Like, keys("+"), "Like (Nostr articles)"},
+			{Reply, keys("r"), "Reply (Nostr articles)"},
+			{Thread, keys("t"), "Show / hide replies"},
+			{Zap, keys("z"), "Zap the author (Nostr articles)"},
+			{ShowLinks, keys("g"), "Links in this article"},
+			{ShowGallery, keys("p"), "Image gallery"},
+			{FullArticle, keys("e"), "Fetch the full article / show the summary"},
+			{Export, keys("E"), "Export this article"},
+			{Enqueue, keys("a"), "Add the episode to the podcast queue"},
+			{Back, nav.back, "Back to articles"},
+		},
+		Relays: {
+			{Up, nav.up, "Previous relay"},
+			{Down, nav.down, "Next relay"},
+			{AddRelay, keys("a"), "Add relay"},
+			{RemoveRelay, keys("d"), "Remove relay"},
+			{ToggleRead, keys("r"), "Toggle read"},
+			{ToggleWrite, keys("w"), "Toggle write"},
+			{AuthPolicy, keys("A"), "Cycle NIP-42 auth policy"},
+			{CheckRelays, keys("c"), "Check relays"},
+			{PublishList, keys("p"), "Publish relay list (kind 10002)"},
+			{Back, nav.back, "Back to feeds"},
+		},
+		Logs: {
+			{Up, nav.up, "Scroll back"},
+			{Down, nav.down, "Scroll forward"},
+			{PageUp, nav.pageUp, "Page back"},
+			{PageDown, nav.pageDown, "Page forward"},
+			{Follow, nav.follow, "Follow newest entries"},
+			{LogLevel, keys("l"), "Cycle minimum level"},
+			{Back, nav.back, "Back to feeds"},
+		},
+		Compose: {
+			{Up, nav.up, "Previous relay"},
+			{Down, nav.down, "Next relay"},
+			{ToggleRelay, keys(" ", "x"), "Toggle relay"},
+			{PublishList, keys("p", "enter"), "Sign and publish"},
+			{EditNote, keys("e"), "Edit comment"},
+			{Back, nav.back, "Cancel / back to article"},
+		},
+		Links: {
+			{Up, nav.up, "Previous link"},
+			{Down, nav.down, "Next link"},
+			{Open, keys("enter"), "Read the link in the reader"},
+			{OpenBrowser, keys("o"), "Open externally"},
+			{Subscribe, keys("a"), "Subscribe if it is a feed"},
+			{Back, nav.back, "Back to article"},
+		},
+		Gallery: {
+			{Up, nav.up, "Image above"},
+			{Down, nav.down, "Image below"},
+			{PrevImage, nav.left, "Previous image"},
+			{NextImage, nav.right, "Next image"},
+			{Open, keys("enter"), "Show full size"},
+			{ExternalImage, keys("o", "I"), "Open in external viewer"},
+			{CloseImage, keys("x"), "Close full size image"},
+			{Back, nav.back, "Back to grid / article"},
+		},
+		Podcasts: {
+			{Up, nav.up, "Previous episode"},
+			{Down, nav.down, "Next episode"},
+			{Open, keys("enter"), "Play episode"},
+			{PlayPause, keys(" ", "p"), "Pause / resume"},
+			{SeekBack, nav.left, "Back 15 seconds"},
+			{SeekForward, nav.right, "Forward 30 seconds"},
+			{SlowDown, keys("-"), "Slower"},
+			{SpeedUp, keys("+", "="), "Faster"},
+			{Dequeue, keys("d"), "Remove from the queue"},
+			{StopPlaying, keys("s"), "Stop playback"},
+			{Back, nav.back, "Back to feeds"},
+		},
+	}, nil
+}
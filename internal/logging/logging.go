@@ -0,0 +1,173 @@
+// Package logging sets up the application's structured logger. Output goes
+// to a size-rotated file (stdout and stderr belong to the TUI) and the most
+// recent entries are kept in memory for the in-app log viewer.
+package logging
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ringSize is how many entries the log viewer can show
+const ringSize = 500
+
+// Options configures Setup
+type Options struct {
+	Path       string     // Log file path
+	Level      slog.Level // Minimum level written
+	MaxSize    int64      // Rotate when the file grows past this many bytes
+	MaxBackups int        // Number of rotated files to keep
+}
+
+// Entry is a log record as shown in the log viewer
+type Entry struct {
+	Time    time.Time
+	Level   slog.Level
+	Message string
+	Attrs   string // Attributes formatted as key=value pairs
+}
+
+var recent = &ring{entries: make([]Entry, ringSize)}
+
+// ParseLevel converts a config level name to a slog level, defaulting to info
+func ParseLevel(name string) slog.Level {
+	switch strings.ToLower(name) {
+	case "debug":
+		return slog.LevelDebug
+	case "warn", "warning":
+		return slog.LevelWarn
+	case "error":
+		return slog.LevelError
+	default:
+		return slog.LevelInfo
+	}
+}
+
+// Setup installs the default slog logger. The returned closer flushes and
+// closes the log file.
+func Setup(opts Options) (io.Closer, error) {
+	if err := os.MkdirAll(filepath.Dir(opts.Path), 0755); err != nil {
+		return nil, err
+	}
+
+	file, err := openRotating(opts.Path, opts.MaxSize, opts.MaxBackups)
+	if err != nil {
+		return nil, err
+	}
+
+	text := slog.NewTextHandler(file, &slog.HandlerOptions{Level: opts.Level})
+	slog.SetDefault(slog.New(&handler{next: text, level: opts.Level}))
+	return file, nil
+}
+
+// Recent returns up to n of the newest entries, oldest first
+func Recent(n int) []Entry {
+	return recent.last(n)
+}
+
+// handler writes records to the file handler and the in-memory ring
+type handler struct {
+	next   slog.Handler
+	level  slog.Level
+	attrs  string // Preformatted attributes from WithAttrs
+	prefix string // Group prefix from WithGroup
+}
+
+func (h *handler) Enabled(_ context.Context, level slog.Level) bool {
+	return level >= h.level
+}
+
+func (h *handler) Handle(ctx context.Context, r slog.Record) error {
+	var attrs strings.Builder
+	attrs.WriteString(h.attrs)
+	r.Attrs(func(a slog.Attr) bool {
+		writeAttr(&attrs, h.prefix, a)
+		return true
+	})
+
+	recent.add(Entry{
+		Time:    r.Time,
+		Level:   r.Level,
+		Message: r.Message,
+		Attrs:   strings.TrimSpace(attrs.String()),
+	})
+	return h.next.Handle(ctx, r)
+}
+
+func (h *handler) WithAttrs(as []slog.Attr) slog.Handler {
+	var attrs strings.Builder
+	attrs.WriteString(h.attrs)
+	for _, a := range as {
+		writeAttr(&attrs, h.prefix, a)
+	}
+	return &handler{next: h.next.WithAttrs(as), level: h.level, attrs: attrs.String(), prefix: h.prefix}
+}
+
+func (h *handler) WithGroup(name string) slog.Handler {
+	if name == "" {
+		return h
+	}
+	return &handler{next: h.next.WithGroup(name), level: h.level, attrs: h.attrs, prefix: h.prefix + name + "."}
+}
+
+func writeAttr(b *strings.Builder, prefix string, a slog.Attr) {
+	a.Value = a.Value.Resolve()
+	if a.Equal(slog.Attr{}) {
+		return
+	}
+	if a.Value.Kind() == slog.KindGroup {
+		for _, ga := range a.Value.Group() {
+			writeAttr(b, prefix+a.Key+".", ga)
+		}
+		return
+	}
+	fmt.Fprintf(b, " %s%s=%v", prefix, a.Key, a.Value.Any())
+}
+
+// ring is a fixed-size buffer of the newest entries
+type ring struct {
+	mu      sync.Mutex
+	entries []Entry
+	next    int
+	full    bool
+}
+
+func (r *ring) add(e Entry) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.entries[r.next] = e
+	r.next = (r.next + 1) % len(r.entries)
+	if r.next == 0 {
+		r.full = true
+	}
+}
+
+func (r *ring) last(n int) []Entry {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	count := r.next
+	if r.full {
+		count = len(r.entries)
+	}
+	if n <= 0 || n > count {
+		n = count
+	}
+
+	out := make([]Entry, n)
+	start := r.next - n
+	if start < 0 {
+		start += len(r.entries)
+	}
+	for i := range out {
+		out[i] = r.entries[(start+i)%len(r.entries)]
+	}
+	return out
+}
@@ -0,0 +1,227 @@
+// Package media opens images and videos in external programs. The
+// programs are command templates tried in order; the installed ones are
+// found once. One viewer and one player are kept open at a time, and every
+// process started is waited for so none are left as zombies.
+package media
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/plebone/nostrfeedz-cli/internal/player"
+)
+
+// Placeholder is replaced by the file or URL to open in a command
+// template. Templates without it get the target appended.
+const Placeholder = "{url}"
+
+// ErrNoProgram is returned when none of the configured programs is
+// installed or starts
+var ErrNoProgram = errors.New("no program found")
+
+// Launcher opens media in external programs
+type Launcher struct {
+	imageTemplates []string
+	videoTemplates []string
+	socket         string // mpv IPC socket for the video player
+
+	detect  sync.Once
+	viewers []command // Installed, in order of preference
+	players []command
+
+	mu    sync.Mutex
+	image *process
+	video *process
+}
+
+// command is a template whose program was found
+type command struct {
+	path string
+	args []string
+}
+
+// mpv reports whether the program is mpv, which is driven over IPC
+func (c command) mpv() bool {
+	return filepath.Base(c.path) == "mpv"
+}
+
+// process is a started program, reaped by a goroutine once it exits
+type process struct {
+	cmd  *exec.Cmd
+	ipc  bool // Listening on the launcher's mpv socket
+	done chan struct{}
+}
+
+// NewLauncher creates a launcher for the given image viewer and video
+// player templates, e.g. "feh --scale-down {url}". socket is where an mpv
+// video player listens for commands.
+func NewLauncher(imageViewers, videoPlayers []string, socket string) *Launcher {
+	return &Launcher{imageTemplates: imageViewers, videoTemplates: videoPlayers, socket: socket}
+}
+
+// Detect finds the installed programs, if not done yet, and returns their
+// names in order of preference
+func (l *Launcher) Detect() (viewers, players []string) {
+	l.detect.Do(func() {
+		l.viewers = findCommands(l.imageTemplates)
+		l.players = findCommands(l.videoTemplates)
+		slog.Debug("detected media programs", "viewers", len(l.viewers), "players", len(l.players))
+	})
+	for _, c := range l.viewers {
+		viewers = append(viewers, filepath.Base(c.path))
+	}
+	for _, c := range l.players {
+		players = append(players, filepath.Base(c.path))
+	}
+	return viewers, players
+}
+
+// findCommands keeps the templates whose program is installed
+func findCommands(templates []string) []command {
+	var found []command
+	for _, template := range templates {
+		fields := strings.Fields(template)
+		if len(fields) == 0 {
+			continue
+		}
+		path, err := exec.LookPath(fields[0])
+		if err != nil {
+			continue
+		}
+		found = append(found, command{path: path, args: fields[1:]})
+	}
+	return found
+}
+
+// OpenImage shows an image file, closing the previous viewer
+func (l *Launcher) OpenImage(path string) error {
+	l.Detect()
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.image.stop()
+	l.image = nil
+	p, err := startFirst(l.viewers, path, "")
+	if err != nil {
+		return fmt.Errorf("image viewer: %w", err)
+	}
+	l.image = p
+	return nil
+}
+
+// OpenVideo plays a video. An mpv still open from the previous video
+// loads it in the same window; any other player is replaced.
+func (l *Launcher) OpenVideo(url string) error {
+	l.Detect()
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.video.running() && l.video.ipc {
+		err := l.load(url)
+		if err == nil {
+			return nil
+		}
+		slog.Debug("could not reuse the video player", "err", err)
+	}
+
+	l.video.stop()
+	l.video = nil
+	p, err := startFirst(l.players, url, l.socket)
+	if err != nil {
+		return fmt.Errorf("video player: %w", err)
+	}
+	l.video = p
+	return nil
+}
+
+// load replaces the video playing in mpv
+func (l *Launcher) load(url string) error {
+	mpv, err := player.Dial(l.socket)
+	if err != nil {
+		return err
+	}
+	defer mpv.Close()
+	return mpv.Load(url)
+}
+
+// Close closes the viewer and player
+func (l *Launcher) Close() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.image.stop()
+	l.video.stop()
+	l.image, l.video = nil, nil
+}
+
+// startFirst starts the first of the commands that launches. mpv gets an
+// IPC socket when one is given.
+func startFirst(commands []command, target, socket string) (*process, error) {
+	if len(commands) == 0 {
+		return nil, ErrNoProgram
+	}
+	var err error
+	for _, c := range commands {
+		args := expand(c.args, target)
+		ipc := socket != "" && c.mpv()
+		if ipc {
+			os.Remove(socket) // Left by a player that was killed
+			args = append([]string{"--input-ipc-server=" + socket}, args...)
+		}
+		cmd := exec.Command(c.path, args...)
+		if err = cmd.Start(); err != nil {
+			slog.Debug("failed to start media program", "program", c.path, "err", err)
+			continue
+		}
+		p := &process{cmd: cmd, ipc: ipc, done: make(chan struct{})}
+		go func() {
+			cmd.Wait()
+			close(p.done)
+		}()
+		return p, nil
+	}
+	return nil, err
+}
+
+// expand fills in a template's placeholder
+func expand(args []string, target string) []string {
+	expanded := make([]string, 0, len(args)+1)
+	found := false
+	for _, arg := range args {
+		if strings.Contains(arg, Placeholder) {
+			arg = strings.ReplaceAll(arg, Placeholder, target)
+			found = true
+		}
+		expanded = append(expanded, arg)
+	}
+	if !found {
+		expanded = append(expanded, target)
+	}
+	return expanded
+}
+
+func (p *process) running() bool {
+	if p == nil {
+		return false
+	}
+	select {
+	case <-p.done:
+		return false
+	default:
+		return true
+	}
+}
+
+// stop kills the program and waits until it has been reaped
+func (p *process) stop() {
+	if !p.running() {
+		return
+	}
+	p.cmd.Process.Kill()
+	<-p.done
+}
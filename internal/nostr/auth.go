@@ -0,0 +1,165 @@
+package nostr
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/nbd-wtf/go-nostr"
+)
+
+// AuthPolicy controls how NIP-42 AUTH challenges from a relay are answered
+type AuthPolicy string
+
+const (
+	AuthAlways AuthPolicy = "always"
+	AuthAsk    AuthPolicy = "ask"
+	AuthNever  AuthPolicy = "never"
+)
+
+// authPromptTimeout is how long an "ask" challenge waits for the user
+const authPromptTimeout = 60 * time.Second
+
+// AuthRequest asks the user whether to authenticate to a relay.
+// At most one value may be sent on Reply; Done is closed once the request
+// is answered, times out or is abandoned by the relay.
+type AuthRequest struct {
+	Relay string
+	Reply chan bool
+	Done  <-chan struct{}
+}
+
+// AuthResult reports the outcome of answering an AUTH challenge
+type AuthResult struct {
+	Relay string
+	Err   error
+}
+
+// NormalizeRelayURL returns the canonical form used for relay lookups
+func NormalizeRelayURL(url string) string {
+	return nostr.NormalizeURL(url)
+}
+
+// SetAuthPolicies sets the per-relay AUTH policies. Relays without an
+// entry use AuthAsk.
+func (c *Client) SetAuthPolicies(policies map[string]AuthPolicy) {
+	normalized := make(map[string]AuthPolicy, len(policies))
+	for url, policy := range policies {
+		normalized[nostr.NormalizeURL(url)] = policy
+	}
+
+	c.authMu.Lock()
+	defer c.authMu.Unlock()
+	c.authPolicies = normalized
+}
+
+// AuthRequests returns the channel of relays waiting for an "ask" decision
+func (c *Client) AuthRequests() <-chan AuthRequest {
+	return c.authRequests
+}
+
+// AuthResults returns the channel of AUTH outcomes
+func (c *Client) AuthResults() <-chan AuthResult {
+	return c.authResults
+}
+
+func (c *Client) authPolicy(url string) AuthPolicy {
+	c.authMu.Lock()
+	defer c.authMu.Unlock()
+	if policy, ok := c.authPolicies[url]; ok && policy != "" {
+		return policy
+	}
+	return AuthAsk
+}
+
+// HandleAuth signs a kind 22242 AUTH event according to the relay's policy.
+// It is installed on the client's pool and can be shared with other pools.
+func (c *Client) HandleAuth(ctx context.Context, authEvent nostr.RelayEvent) error {
+	url := nostr.NormalizeURL(authEvent.Relay.URL)
+
+	err := c.authorize(ctx, url)
+	if err == nil {
+		err = c.SignEvent(authEvent.Event)
+	}
+
+	c.reportAuth(url, err)
+	return err
+}
+
+// authorize decides whether we may authenticate to a relay
+func (c *Client) authorize(ctx context.Context, url string) error {
+	switch c.authPolicy(url) {
+	case AuthAlways:
+		return nil
+	case AuthNever:
+		return fmt.Errorf("authentication disabled for %s", url)
+	}
+
+	// Remember "ask" answers for the rest of the session
+	c.authMu.Lock()
+	approved, asked := c.authApproved[url]
+	c.authMu.Unlock()
+	if asked {
+		if approved {
+			return nil
+		}
+		return fmt.Errorf("authentication declined for %s", url)
+	}
+
+	done := make(chan struct{})
+	defer close(done)
+	req := AuthRequest{Relay: url, Reply: make(chan bool, 1), Done: done}
+	select {
+	case c.authRequests <- req:
+	default:
+		return fmt.Errorf("authentication prompt unavailable for %s", url)
+	}
+
+	timeout := time.NewTimer(authPromptTimeout)
+	defer timeout.Stop()
+
+	select {
+	case approved := <-req.Reply:
+		c.authMu.Lock()
+		c.authApproved[url] = approved
+		c.authMu.Unlock()
+		if !approved {
+			return fmt.Errorf("authentication declined for %s", url)
+		}
+		return nil
+	case <-timeout.C:
+		return fmt.Errorf("no answer to authentication prompt for %s", url)
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// reportAuth publishes an AUTH outcome without blocking the relay goroutine
+func (c *Client) reportAuth(url string, err error) {
+	if err != nil {
+		slog.Warn("relay authentication failed", "relay", url, "err", err)
+	} else {
+		slog.Info("authenticated to relay", "relay", url)
+	}
+
+	select {
+	case c.authResults <- AuthResult{Relay: url, Err: err}:
+	default:
+	}
+}
+
+// authenticate performs AUTH on a single relay and waits for its OK
+func (c *Client) authenticate(ctx context.Context, relay *nostr.Relay) error {
+	var signErr error
+	err := relay.Auth(ctx, func(event *nostr.Event) error {
+		signErr = c.HandleAuth(ctx, nostr.RelayEvent{Event: event, Relay: relay})
+		return signErr
+	})
+	if err != nil && signErr == nil {
+		// Signing worked, so the relay itself refused us
+		err = fmt.Errorf("relay rejected AUTH: %w", err)
+		c.reportAuth(nostr.NormalizeURL(relay.URL), err)
+	}
+	return err
+}
@@ -0,0 +1,175 @@
+package nostr
+
+import (
+	"context"
+	"strings"
+
+	"github.com/nbd-wtf/go-nostr"
+	"github.com/nbd-wtf/go-nostr/nip19"
+)
+
+// NIP-51 bookmark kinds
+const (
+	BookmarkListKind = 10003 // The user's single bookmark list
+	BookmarkSetKind  = 30003 // Named bookmark sets, one per d tag
+)
+
+// BookmarkList is a NIP-51 bookmark list or set. Tags and Content are kept
+// as fetched so entries added by other clients, including encrypted private
+// ones, survive a republish.
+type BookmarkList struct {
+	Identifier string // d tag of a kind 30003 set, "" for the kind 10003 list
+	Tags       nostr.Tags
+	Content    string
+	CreatedAt  nostr.Timestamp
+}
+
+// EventBookmark references a Nostr event by ID
+func EventBookmark(id string) nostr.Tag {
+	return nostr.Tag{"e", id}
+}
+
+// ArticleBookmark references every version of a NIP-23 article by its
+// "30023:<pubkey>:<d>" coordinate
+func ArticleBookmark(coordinate string) nostr.Tag {
+	return nostr.Tag{"a", coordinate}
+}
+
+// URLBookmark references a web page
+func URLBookmark(url string) nostr.Tag {
+	return nostr.Tag{"r", url}
+}
+
+// EventIDs returns the bookmarked event IDs
+func (b *BookmarkList) EventIDs() []string {
+	return b.values("e")
+}
+
+// URLs returns the bookmarked URLs
+func (b *BookmarkList) URLs() []string {
+	return b.values("r")
+}
+
+// ArticleLinks returns the bookmarked NIP-23 articles as the nostr:naddr1...
+// links their feed items have
+func (b *BookmarkList) ArticleLinks() []string {
+	var links []string
+	for _, coordinate := range b.values("a") {
+		parts := strings.SplitN(coordinate, ":", 3)
+		if len(parts) != 3 || parts[0] != "30023" {
+			continue
+		}
+		if naddr, err := nip19.EncodeEntity(parts[1], LongFormKind, parts[2], nil); err == nil {
+			links = append(links, "nostr:"+naddr)
+		}
+	}
+	return links
+}
+
+func (b *BookmarkList) values(name string) []string {
+	var values []string
+	for _, tag := range b.Tags {
+		if len(tag) >= 2 && tag[0] == name {
+			values = append(values, tag[1])
+		}
+	}
+	return values
+}
+
+// Contains reports whether a bookmark with the same name and value exists
+func (b *BookmarkList) Contains(ref nostr.Tag) bool {
+	return b.index(ref) >= 0
+}
+
+// Add appends a bookmark, returning false if it was already present
+func (b *BookmarkList) Add(ref nostr.Tag) bool {
+	if b.Contains(ref) {
+		return false
+	}
+	b.Tags = append(b.Tags, ref)
+	return true
+}
+
+// Remove deletes a bookmark, returning false if it was not present
+func (b *BookmarkList) Remove(ref nostr.Tag) bool {
+	i := b.index(ref)
+	if i < 0 {
+		return false
+	}
+	b.Tags = append(b.Tags[:i], b.Tags[i+1:]...)
+	return true
+}
+
+func (b *BookmarkList) index(ref nostr.Tag) int {
+	for i, tag := range b.Tags {
+		if len(tag) >= 2 && tag[0] == ref[0] && tag[1] == ref[1] {
+			return i
+		}
+	}
+	return -1
+}
+
+// FetchBookmarks fetches the newest bookmark list, or the named set when
+// identifier is not empty. It returns nil when the relays that answered have
+// none, and an error when no relay answered.
+func (c *Client) FetchBookmarks(pubkey, identifier string) (*BookmarkList, error) {
+	filter := nostr.Filter{
+		Kinds:   []int{BookmarkListKind},
+		Authors: []string{pubkey},
+		Limit:   1,
+	}
+	if identifier != "" {
+		filter.Kinds = []int{BookmarkSetKind}
+		filter.Tags = nostr.TagMap{"d": []string{identifier}}
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), listQueryTimeout)
+	defer cancel()
+	events, err := c.QueryStoredEvents(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	// Each relay answers with its own copy; keep the newest
+	var newest *nostr.Event
+	for _, ev := range events {
+		if newest == nil || ev.CreatedAt > newest.CreatedAt {
+			newest = ev
+		}
+	}
+	if newest == nil {
+		return nil, nil
+	}
+
+	list := &BookmarkList{
+		Identifier: identifier,
+		Content:    newest.Content,
+		CreatedAt:  newest.CreatedAt,
+	}
+	for _, tag := range newest.Tags {
+		if len(tag) > 0 && tag[0] != "d" {
+			list.Tags = append(list.Tags, tag)
+		}
+	}
+	return list, nil
+}
+
+// PublishBookmarks replaces the bookmark list (or set) on the write relays
+func (c *Client) PublishBookmarks(list *BookmarkList) error {
+	event := &nostr.Event{
+		Kind:      BookmarkListKind,
+		CreatedAt: nostr.Now(),
+		Content:   list.Content,
+	}
+	if list.Identifier != "" {
+		event.Kind = BookmarkSetKind
+		event.Tags = nostr.Tags{{"d", list.Identifier}}
+	}
+	event.Tags = append(event.Tags, list.Tags...)
+
+	if err := c.PublishEvent(event); err != nil {
+		return err
+	}
+	list.CreatedAt = event.CreatedAt
+	return nil
+}
@@ -2,29 +2,56 @@ package nostr
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"log/slog"
+	"strings"
+	"sync"
 	"time"
 
 	"github.com/nbd-wtf/go-nostr"
 	"github.com/nbd-wtf/go-nostr/nip19"
 )
 
+// listQueryTimeout bounds fetching a list that is about to be replaced
+const listQueryTimeout = 15 * time.Second
+
 type Client struct {
 	pool         *nostr.SimplePool
-	relays       []string
+	relays       []string // Relays used for queries
+	writeRelays  []string // Relays events are published to
 	secretKey    string
 	pubkey       string
 	plebSigner   *PlebSignerClient
 	signerType   string // "nsec", "plebsigner", or empty
+
+	// NIP-42 authentication
+	authMu       sync.Mutex
+	authPolicies map[string]AuthPolicy
+	authApproved map[string]bool // Session answers for AuthAsk relays
+	authRequests chan AuthRequest
+	authResults  chan AuthResult
 }
 
 // NewClient creates a new Nostr client with the given relays
 func NewClient(relays []string) *Client {
 	ctx := context.Background()
-	return &Client{
-		pool:   nostr.NewSimplePool(ctx),
-		relays: relays,
+	c := &Client{
+		relays:       relays,
+		writeRelays:  relays,
+		authPolicies: make(map[string]AuthPolicy),
+		authApproved: make(map[string]bool),
+		authRequests: make(chan AuthRequest, 8),
+		authResults:  make(chan AuthResult, 16),
 	}
+	c.pool = nostr.NewSimplePool(ctx, nostr.WithAuthHandler(c.HandleAuth))
+	return c
+}
+
+// SetRelays sets separate read and write relay lists
+func (c *Client) SetRelays(read, write []string) {
+	c.relays = read
+	c.writeRelays = write
 }
 
 // SetPrivateKeySigner sets up signing using an nsec (private key)
@@ -128,32 +155,66 @@ func (c *Client) SignEvent(event *nostr.Event) error {
 	}
 }
 
-// PublishEvent publishes a signed event to all relays
+// PublishResult is the outcome of publishing an event to one relay
+type PublishResult struct {
+	Relay string
+	Err   error
+}
+
+// PublishEvent publishes a signed event to all write relays. It fails
+// unless at least one relay accepted the event.
 func (c *Client) PublishEvent(event *nostr.Event) error {
-	if c.secretKey == "" {
-		return fmt.Errorf("no signer configured")
+	results, err := c.PublishEventTo(event, c.writeRelays)
+	if err != nil {
+		return err
+	}
+	if len(results) == 0 {
+		return fmt.Errorf("no write relays configured")
+	}
+	var errs []error
+	for _, result := range results {
+		if result.Err == nil {
+			return nil
+		}
+		errs = append(errs, fmt.Errorf("%s: %w", result.Relay, result.Err))
+	}
+	return fmt.Errorf("no relay accepted the event: %w", errors.Join(errs...))
+}
+
+// PublishEventTo signs an event and publishes it to the given relays,
+// returning the result from each. Failures on individual relays are
+// reported in the results, not as an error.
+func (c *Client) PublishEventTo(event *nostr.Event, relays []string) ([]PublishResult, error) {
+	if c.signerType == "" {
+		return nil, fmt.Errorf("no signer configured")
 	}
 	
 	// Sign the event
 	if err := c.SignEvent(event); err != nil {
-		return err
+		return nil, err
 	}
 	
-	// Publish to all relays
-	ctx := context.Background()
-	for _, relayURL := range c.relays {
+	results := make([]PublishResult, 0, len(relays))
+	for _, relayURL := range relays {
+		result := PublishResult{Relay: relayURL}
 		relay, err := c.pool.EnsureRelay(relayURL)
 		if err != nil {
-			fmt.Printf("Failed to connect to relay %s: %v\n", relayURL, err)
+			slog.Warn("failed to connect to relay", "relay", relayURL, "err", err)
+			result.Err = err
+			results = append(results, result)
 			continue
 		}
 		
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 		if err := relay.Publish(ctx, *event); err != nil {
-			fmt.Printf("Failed to publish to %s: %v\n", relayURL, err)
+			slog.Warn("failed to publish event", "relay", relayURL, "kind", event.Kind, "err", err)
+			result.Err = err
 		}
+		cancel()
+		results = append(results, result)
 	}
 	
-	return nil
+	return results, nil
 }
 
 // QueryEvents queries events from relays based on filters
@@ -169,6 +230,93 @@ func (c *Client) QueryEvents(ctx context.Context, filter nostr.Filter) ([]*nostr
 	return events, nil
 }
 
+// QueryStoredEvents queries the read relays like QueryEvents, but fails
+// unless at least one relay sent all its stored events (EOSE). An empty
+// result then means the relays have none, not that none could be asked,
+// which matters before replacing an event with an edited copy.
+func (c *Client) QueryStoredEvents(ctx context.Context, filter nostr.Filter) ([]*nostr.Event, error) {
+	type answer struct {
+		events []*nostr.Event
+		err    error
+	}
+	answers := make(chan answer, len(c.relays))
+	for _, url := range c.relays {
+		go func(url string) {
+			events, err := c.queryRelay(ctx, url, filter)
+			answers <- answer{events, err}
+		}(url)
+	}
+
+	var events []*nostr.Event
+	seen := make(map[string]bool)
+	answered := false
+	var errs []error
+	for range c.relays {
+		a := <-answers
+		if a.err != nil {
+			errs = append(errs, a.err)
+			continue
+		}
+		answered = true
+		for _, ev := range a.events {
+			if !seen[ev.ID] {
+				seen[ev.ID] = true
+				events = append(events, ev)
+			}
+		}
+	}
+	if !answered {
+		if len(errs) == 0 {
+			return nil, fmt.Errorf("no read relays configured")
+		}
+		return nil, fmt.Errorf("no relay answered: %w", errors.Join(errs...))
+	}
+	return events, nil
+}
+
+// queryRelay returns a relay's stored events for a filter, answering an
+// AUTH challenge once if the relay asks for one
+func (c *Client) queryRelay(ctx context.Context, url string, filter nostr.Filter) ([]*nostr.Event, error) {
+	relay, err := c.pool.EnsureRelay(url)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", url, err)
+	}
+
+	var events []*nostr.Event
+	authed := false
+subscribe:
+	sub, err := relay.Subscribe(ctx, nostr.Filters{filter})
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", url, err)
+	}
+	defer sub.Unsub()
+
+	for {
+		select {
+		case ev, ok := <-sub.Events:
+			if !ok {
+				return nil, fmt.Errorf("%s: subscription ended before EOSE", url)
+			}
+			events = append(events, ev)
+		case <-sub.EndOfStoredEvents:
+			return events, nil
+		case reason := <-sub.ClosedReason:
+			if strings.HasPrefix(reason, "auth-required:") && !authed {
+				if err := c.authenticate(ctx, relay); err != nil {
+					return nil, fmt.Errorf("%s: auth failed: %w", url, err)
+				}
+				authed = true
+				sub.Unsub()
+				events = nil
+				goto subscribe
+			}
+			return nil, fmt.Errorf("%s: closed: %s", url, reason)
+		case <-ctx.Done():
+			return nil, fmt.Errorf("%s: %w", url, ctx.Err())
+		}
+	}
+}
+
 // Close closes connections
 func (c *Client) Close() {
 	if c.plebSigner != nil {
@@ -0,0 +1,163 @@
+package nostr
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/nbd-wtf/go-nostr"
+	"github.com/nbd-wtf/go-nostr/nip04"
+)
+
+// NIP-47 kinds
+const (
+	NWCRequestKind  = 23194
+	NWCResponseKind = 23195
+)
+
+// WalletConnect is a parsed nostr+walletconnect:// URI (NIP-47)
+type WalletConnect struct {
+	WalletPubkey string
+	Relays       []string
+	Secret       string // Hex key that signs and encrypts requests
+	Lud16        string // Optional lightning address of the wallet
+}
+
+// ParseWalletConnect parses a nostr+walletconnect://<pubkey>?relay=...&secret=... URI
+func ParseWalletConnect(uri string) (*WalletConnect, error) {
+	u, err := url.Parse(uri)
+	if err != nil {
+		return nil, fmt.Errorf("invalid wallet connect URI: %w", err)
+	}
+	if u.Scheme != "nostr+walletconnect" && u.Scheme != "nostrwalletconnect" {
+		return nil, fmt.Errorf("wallet connect URI must start with nostr+walletconnect://")
+	}
+
+	// The pubkey is the host, or the opaque part for URIs without //
+	pubkey := u.Host
+	if pubkey == "" {
+		pubkey = strings.TrimPrefix(u.Opaque, "//")
+	}
+	query := u.Query()
+	wc := &WalletConnect{
+		WalletPubkey: pubkey,
+		Relays:       query["relay"],
+		Secret:       query.Get("secret"),
+		Lud16:        query.Get("lud16"),
+	}
+
+	if !nostr.IsValidPublicKey(wc.WalletPubkey) {
+		return nil, fmt.Errorf("wallet connect URI has an invalid wallet pubkey")
+	}
+	if len(wc.Relays) == 0 {
+		return nil, fmt.Errorf("wallet connect URI has no relay")
+	}
+	if _, err := nostr.GetPublicKey(wc.Secret); err != nil || len(wc.Secret) != 64 {
+		return nil, fmt.Errorf("wallet connect URI has an invalid secret")
+	}
+	return wc, nil
+}
+
+type nwcRequest struct {
+	Method string         `json:"method"`
+	Params map[string]any `json:"params"`
+}
+
+type nwcResponse struct {
+	ResultType string `json:"result_type"`
+	Error      *struct {
+		Code    string `json:"code"`
+		Message string `json:"message"`
+	} `json:"error"`
+	Result json.RawMessage `json:"result"`
+}
+
+// PayInvoice asks the wallet to pay a BOLT11 invoice and returns the preimage
+func (c *Client) PayInvoice(ctx context.Context, wc *WalletConnect, invoice string) (string, error) {
+	var result struct {
+		Preimage string `json:"preimage"`
+	}
+	if err := c.walletRequest(ctx, wc, "pay_invoice", map[string]any{"invoice": invoice}, &result); err != nil {
+		return "", err
+	}
+	return result.Preimage, nil
+}
+
+// walletRequest sends one encrypted NIP-47 request and waits for its response
+func (c *Client) walletRequest(ctx context.Context, wc *WalletConnect, method string, params map[string]any, result any) error {
+	sharedSecret, err := nip04.ComputeSharedSecret(wc.WalletPubkey, wc.Secret)
+	if err != nil {
+		return fmt.Errorf("failed to derive wallet key: %w", err)
+	}
+	payload, err := json.Marshal(nwcRequest{Method: method, Params: params})
+	if err != nil {
+		return err
+	}
+	content, err := nip04.Encrypt(string(payload), sharedSecret)
+	if err != nil {
+		return fmt.Errorf("failed to encrypt wallet request: %w", err)
+	}
+
+	// Requests are signed with the connection secret, not the user's key
+	request := nostr.Event{
+		Kind:      NWCRequestKind,
+		CreatedAt: nostr.Now(),
+		Tags:      nostr.Tags{{"p", wc.WalletPubkey}},
+		Content:   content,
+	}
+	if err := request.Sign(wc.Secret); err != nil {
+		return err
+	}
+
+	var lastErr error
+	for _, relayURL := range wc.Relays {
+		relay, err := c.pool.EnsureRelay(relayURL)
+		if err != nil {
+			lastErr = err
+			continue
+		}
+
+		// Subscribe before publishing so the response cannot be missed
+		sub, err := relay.Subscribe(ctx, nostr.Filters{{
+			Kinds:   []int{NWCResponseKind},
+			Authors: []string{wc.WalletPubkey},
+			Tags:    nostr.TagMap{"e": []string{request.ID}},
+		}})
+		if err != nil {
+			lastErr = err
+			continue
+		}
+		if err := relay.Publish(ctx, request); err != nil {
+			sub.Unsub()
+			lastErr = err
+			continue
+		}
+
+		select {
+		case ev := <-sub.Events:
+			sub.Unsub()
+			return decodeWalletResponse(ev, sharedSecret, result)
+		case <-ctx.Done():
+			sub.Unsub()
+			return fmt.Errorf("wallet did not respond: %w", ctx.Err())
+		}
+	}
+	return fmt.Errorf("failed to reach wallet relay: %w", lastErr)
+}
+
+func decodeWalletResponse(ev *nostr.Event, sharedSecret []byte, result any) error {
+	plain, err := nip04.Decrypt(ev.Content, sharedSecret)
+	if err != nil {
+		return fmt.Errorf("failed to decrypt wallet response: %w", err)
+	}
+	var resp nwcResponse
+	if err := json.Unmarshal([]byte(plain), &resp); err != nil {
+		return fmt.Errorf("invalid wallet response: %w", err)
+	}
+	if resp.Error != nil {
+		return fmt.Errorf("wallet error %s: %s", resp.Error.Code, resp.Error.Message)
+	}
+	return json.Unmarshal(resp.Result, result)
+}
@@ -3,6 +3,7 @@ package nostr
 import (
 	"encoding/json"
 	"fmt"
+	"log/slog"
 
 	"github.com/godbus/dbus/v5"
 	"github.com/nbd-wtf/go-nostr"
@@ -117,9 +118,7 @@ func (c *PlebSignerClient) SignEvent(event *nostr.Event, keyID string) error {
 		return fmt.Errorf("failed to sign event: %w", err)
 	}
 
-	// Debug: log the raw response
-	// Don't log the full response - it's working fine
-	// fmt.Printf("DEBUG: Pleb_Signer response: %s\n", result)
+	slog.Debug("pleb_signer response", "kind", event.Kind, "bytes", len(result))
 
 	// Check for error response
 	var errorResp struct {
@@ -0,0 +1,150 @@
+package nostr
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/nbd-wtf/go-nostr"
+	"github.com/nbd-wtf/go-nostr/nip11"
+)
+
+const RelayListKind = 10002
+
+// RelayStatus describes the health of a single relay
+type RelayStatus struct {
+	URL             string
+	Connected       bool
+	Latency         time.Duration // Round-trip time of a small REQ
+	LastError       string
+	Info            *nip11.RelayInformationDocument
+	AuthRequired    bool
+	PaymentRequired bool
+	CheckedAt       time.Time
+}
+
+// RelayListEntry is a relay with its NIP-65 read/write markers
+type RelayListEntry struct {
+	URL   string
+	Read  bool
+	Write bool
+}
+
+// CheckRelay connects to a relay, measures a REQ round trip and fetches its NIP-11 document
+func (c *Client) CheckRelay(ctx context.Context, url string) RelayStatus {
+	status := RelayStatus{URL: url, CheckedAt: time.Now()}
+
+	// NIP-11 info is independent of the websocket connection
+	infoCtx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	info, err := nip11.Fetch(infoCtx, url)
+	cancel()
+	if err == nil {
+		status.Info = &info
+		if info.Limitation != nil {
+			status.AuthRequired = info.Limitation.AuthRequired
+			status.PaymentRequired = info.Limitation.PaymentRequired
+		}
+	}
+
+	relay, err := c.pool.EnsureRelay(url)
+	if err != nil {
+		status.LastError = err.Error()
+		return status
+	}
+	status.Connected = true
+
+	// Time a tiny query that every relay can answer with EOSE
+	reqCtx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	filter := nostr.Filter{Kinds: []int{0}, Limit: 1}
+	if c.pubkey != "" {
+		filter.Authors = []string{c.pubkey}
+	}
+
+	start := time.Now()
+	authed := false
+subscribe:
+	sub, err := relay.Subscribe(reqCtx, nostr.Filters{filter})
+	if err != nil {
+		status.LastError = err.Error()
+		return status
+	}
+	defer sub.Unsub()
+
+	events := sub.Events
+	for {
+		select {
+		case _, ok := <-events:
+			// Drain events until EOSE
+			if !ok {
+				events = nil
+			}
+		case <-sub.EndOfStoredEvents:
+			status.Latency = time.Since(start)
+			return status
+		case reason := <-sub.ClosedReason:
+			if strings.HasPrefix(reason, "auth-required:") && !authed {
+				status.AuthRequired = true
+				if err := c.authenticate(reqCtx, relay); err != nil {
+					status.LastError = "auth failed: " + err.Error()
+					return status
+				}
+				authed = true
+				goto subscribe
+			}
+			status.LastError = "closed: " + reason
+			return status
+		case <-reqCtx.Done():
+			status.LastError = "timed out waiting for EOSE"
+			return status
+		}
+	}
+}
+
+// CheckRelays checks all given relays concurrently, preserving order
+func (c *Client) CheckRelays(ctx context.Context, urls []string) []RelayStatus {
+	results := make([]RelayStatus, len(urls))
+
+	var wg sync.WaitGroup
+	for i, url := range urls {
+		wg.Add(1)
+		go func(i int, url string) {
+			defer wg.Done()
+			results[i] = c.CheckRelay(ctx, url)
+		}(i, url)
+	}
+	wg.Wait()
+
+	return results
+}
+
+// PublishRelayList publishes the relay list as a NIP-65 kind 10002 event
+func (c *Client) PublishRelayList(relays []RelayListEntry) error {
+	tags := nostr.Tags{}
+	for _, r := range relays {
+		switch {
+		case r.Read && r.Write:
+			tags = append(tags, nostr.Tag{"r", r.URL})
+		case r.Read:
+			tags = append(tags, nostr.Tag{"r", r.URL, "read"})
+		case r.Write:
+			tags = append(tags, nostr.Tag{"r", r.URL, "write"})
+		}
+	}
+
+	if len(tags) == 0 {
+		return fmt.Errorf("no relays to publish")
+	}
+
+	event := &nostr.Event{
+		Kind:      RelayListKind,
+		CreatedAt: nostr.Now(),
+		Tags:      tags,
+		Content:   "",
+	}
+
+	return c.PublishEvent(event)
+}
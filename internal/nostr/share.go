@@ -0,0 +1,56 @@
+package nostr
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/nbd-wtf/go-nostr"
+	"github.com/nbd-wtf/go-nostr/nip19"
+)
+
+// NewShareNote builds an unsigned kind 1 note sharing an article. Nostr
+// articles (nostr:naddr1..., nostr:nevent1... or nostr:note1... links)
+// become NIP-18 quote reposts; anything else is shared as title and URL.
+func NewShareNote(comment, title, link string) (*nostr.Event, error) {
+	event := &nostr.Event{
+		Kind:      nostr.KindTextNote,
+		CreatedAt: nostr.Now(),
+		Tags:      nostr.Tags{},
+	}
+
+	var body string
+	if code, ok := strings.CutPrefix(link, "nostr:"); ok {
+		prefix, value, err := nip19.Decode(code)
+		if err != nil {
+			return nil, fmt.Errorf("invalid nostr link %q: %w", link, err)
+		}
+		switch v := value.(type) {
+		case nostr.EntityPointer:
+			event.Tags = append(event.Tags,
+				nostr.Tag{"q", v.AsTagReference()},
+				nostr.Tag{"p", v.PublicKey})
+		case nostr.EventPointer:
+			event.Tags = append(event.Tags, nostr.Tag{"q", v.ID})
+			if v.Author != "" {
+				event.Tags = append(event.Tags, nostr.Tag{"p", v.Author})
+			}
+		case string:
+			if prefix != "note" {
+				return nil, fmt.Errorf("cannot quote a %s link", prefix)
+			}
+			event.Tags = append(event.Tags, nostr.Tag{"q", v})
+		default:
+			return nil, fmt.Errorf("cannot quote a %s link", prefix)
+		}
+		body = link
+	} else {
+		body = strings.TrimSpace(title + "\n" + link)
+	}
+
+	if comment = strings.TrimSpace(comment); comment != "" {
+		event.Content = comment + "\n\n" + body
+	} else {
+		event.Content = body
+	}
+	return event, nil
+}
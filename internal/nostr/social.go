@@ -0,0 +1,204 @@
+package nostr
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/nbd-wtf/go-nostr"
+	"github.com/nbd-wtf/go-nostr/nip19"
+	"github.com/plebone/nostrfeedz-cli/internal/db"
+)
+
+// LongFormKind is a NIP-23 article
+const LongFormKind = 30023
+
+// ArticleRef points at a NIP-23 article for reactions, replies and zaps
+type ArticleRef struct {
+	EventID    string // ID of the version we have
+	Coordinate string // "30023:<pubkey>:<d>", empty for note1 links
+	Author     string // Hex pubkey, empty when unknown
+}
+
+// ArticleRefFor returns the reference for an item's nostr: link and event
+// ID. It returns false for anything that is not a Nostr article.
+func ArticleRefFor(link, eventID string) (ArticleRef, bool) {
+	code, ok := strings.CutPrefix(link, "nostr:")
+	if !ok {
+		return ArticleRef{}, false
+	}
+	ref := ArticleRef{EventID: eventID}
+
+	_, value, err := nip19.Decode(code)
+	if err != nil {
+		return ArticleRef{}, false
+	}
+	switch v := value.(type) {
+	case nostr.EntityPointer:
+		if v.Kind != LongFormKind {
+			return ArticleRef{}, false
+		}
+		ref.Coordinate = v.AsTagReference()
+		ref.Author = v.PublicKey
+	case nostr.EventPointer:
+		ref.Author = v.Author
+	case string:
+		// note1 links only carry the event ID
+	default:
+		return ArticleRef{}, false
+	}
+	return ref, true
+}
+
+// tags references the article from a reaction or reply
+func (r ArticleRef) tags() nostr.Tags {
+	tags := nostr.Tags{}
+	if r.Coordinate != "" {
+		tags = append(tags, nostr.Tag{"a", r.Coordinate})
+	}
+	if r.EventID != "" {
+		tags = append(tags, nostr.Tag{"e", r.EventID, "", "root"})
+	}
+	if r.Author != "" {
+		tags = append(tags, nostr.Tag{"p", r.Author})
+	}
+	return tags
+}
+
+// FetchSocial fetches reactions (kind 7), replies (kind 1) and zap receipts
+// (kind 9735) that reference an article by address or event ID
+func (c *Client) FetchSocial(ref ArticleRef) (*db.ArticleSocial, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+	defer cancel()
+
+	kinds := []int{nostr.KindReaction, nostr.KindTextNote, nostr.KindZap}
+	var filters []nostr.Filter
+	if ref.Coordinate != "" {
+		filters = append(filters, nostr.Filter{Kinds: kinds, Tags: nostr.TagMap{"a": []string{ref.Coordinate}}})
+	}
+	if ref.EventID != "" {
+		filters = append(filters, nostr.Filter{Kinds: kinds, Tags: nostr.TagMap{"e": []string{ref.EventID}}})
+	}
+
+	// The same event comes back from several relays and both filters
+	seen := make(map[string]bool)
+	social := &db.ArticleSocial{FetchedAt: time.Now()}
+	for _, filter := range filters {
+		events, err := c.QueryEvents(ctx, filter)
+		if err != nil {
+			return nil, err
+		}
+		for _, ev := range events {
+			if seen[ev.ID] {
+				continue
+			}
+			seen[ev.ID] = true
+
+			switch ev.Kind {
+			case nostr.KindReaction:
+				if ev.Content == "-" {
+					continue
+				}
+				social.Reactions++
+				if ev.PubKey == c.pubkey {
+					social.Liked = true
+				}
+			case nostr.KindTextNote:
+				social.Replies = append(social.Replies, db.SocialReply{
+					ID:        ev.ID,
+					PubKey:    ev.PubKey,
+					Content:   ev.Content,
+					CreatedAt: ev.CreatedAt.Time(),
+				})
+			case nostr.KindZap:
+				bolt11 := ev.Tags.GetFirst([]string{"bolt11"})
+				if bolt11 == nil {
+					continue
+				}
+				msats, err := Bolt11Amount((*bolt11)[1])
+				if err != nil {
+					continue
+				}
+				social.ZapCount++
+				social.ZapSats += msats / 1000
+			}
+		}
+	}
+
+	sort.Slice(social.Replies, func(i, j int) bool {
+		return social.Replies[i].CreatedAt.Before(social.Replies[j].CreatedAt)
+	})
+	return social, nil
+}
+
+// React publishes a kind 7 "+" reaction to an article
+func (c *Client) React(ref ArticleRef) error {
+	event := &nostr.Event{
+		Kind:      nostr.KindReaction,
+		CreatedAt: nostr.Now(),
+		Tags:      append(ref.tags(), nostr.Tag{"k", strconv.Itoa(LongFormKind)}),
+		Content:   "+",
+	}
+	return c.PublishEvent(event)
+}
+
+// NewReplyNote builds an unsigned kind 1 reply to an article
+func NewReplyNote(ref ArticleRef, content string) (*nostr.Event, error) {
+	content = strings.TrimSpace(content)
+	if content == "" {
+		return nil, fmt.Errorf("reply is empty")
+	}
+	return &nostr.Event{
+		Kind:      nostr.KindTextNote,
+		CreatedAt: nostr.Now(),
+		Tags:      ref.tags(),
+		Content:   content,
+	}, nil
+}
+
+// Bolt11Amount returns the amount of a BOLT11 invoice in millisatoshis
+func Bolt11Amount(invoice string) (int64, error) {
+	invoice = strings.ToLower(invoice)
+	sep := strings.LastIndex(invoice, "1")
+	if !strings.HasPrefix(invoice, "ln") || sep < 0 {
+		return 0, fmt.Errorf("not a bolt11 invoice")
+	}
+	hrp := invoice[2:sep]
+
+	// Skip the network prefix (bc, tb, bcrt, ...) up to the amount
+	start := strings.IndexAny(hrp, "0123456789")
+	if start < 0 {
+		return 0, fmt.Errorf("invoice has no amount")
+	}
+	amount := hrp[start:]
+
+	multiplier := amount[len(amount)-1]
+	digits := amount
+	if multiplier >= 'a' && multiplier <= 'z' {
+		digits = amount[:len(amount)-1]
+	}
+	n, err := strconv.ParseInt(digits, 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid invoice amount %q", amount)
+	}
+
+	// 1 BTC = 100,000,000,000 msat
+	switch multiplier {
+	case 'm':
+		return n * 100_000_000, nil
+	case 'u':
+		return n * 100_000, nil
+	case 'n':
+		return n * 100, nil
+	case 'p':
+		return n / 10, nil
+	default:
+		if multiplier >= 'a' && multiplier <= 'z' {
+			return 0, fmt.Errorf("invalid invoice multiplier %q", multiplier)
+		}
+		return n * 100_000_000_000, nil
+	}
+}
@@ -55,9 +55,12 @@ func (c *Client) PublishSubscriptions(list *SubscriptionList) error {
 	return c.PublishEvent(event)
 }
 
-// FetchSubscriptions fetches the subscription list from Nostr
+// FetchSubscriptions fetches the newest subscription list from Nostr. It
+// returns nil when the relays that answered have none, and an error when no
+// relay answered.
 func (c *Client) FetchSubscriptions(pubkey string) (*SubscriptionList, error) {
-	ctx := context.Background()
+	ctx, cancel := context.WithTimeout(context.Background(), listQueryTimeout)
+	defer cancel()
 	filter := nostr.Filter{
 		Kinds:   []int{SubscriptionListKind},
 		Authors: []string{pubkey},
@@ -65,17 +68,24 @@ func (c *Client) FetchSubscriptions(pubkey string) (*SubscriptionList, error) {
 		Limit:   1,
 	}
 
-	events, err := c.QueryEvents(ctx, filter)
+	events, err := c.QueryStoredEvents(ctx, filter)
 	if err != nil {
 		return nil, err
 	}
 
-	if len(events) == 0 {
+	// Each relay answers with its own copy; keep the newest
+	var newest *nostr.Event
+	for _, ev := range events {
+		if newest == nil || ev.CreatedAt > newest.CreatedAt {
+			newest = ev
+		}
+	}
+	if newest == nil {
 		return nil, nil
 	}
 
 	var list SubscriptionList
-	if err := json.Unmarshal([]byte(events[0].Content), &list); err != nil {
+	if err := json.Unmarshal([]byte(newest.Content), &list); err != nil {
 		return nil, fmt.Errorf("failed to unmarshal subscriptions: %w", err)
 	}
 
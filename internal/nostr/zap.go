@@ -0,0 +1,208 @@
+package nostr
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/nbd-wtf/go-nostr"
+)
+
+// ZapRequestKind is a NIP-57 zap request, sent to the LNURL server
+const ZapRequestKind = 9734
+
+var lnurlClient = &http.Client{Timeout: 15 * time.Second}
+
+// LNURLPay is the LNURL-pay endpoint behind a lightning address (LUD-06/16)
+type LNURLPay struct {
+	Callback    string `json:"callback"`
+	MinSendable int64  `json:"minSendable"` // msats
+	MaxSendable int64  `json:"maxSendable"` // msats
+	AllowsNostr bool   `json:"allowsNostr"`
+	NostrPubkey string `json:"nostrPubkey"`
+}
+
+// FetchLightningAddress returns the lud16 from a user's kind 0 profile
+func (c *Client) FetchLightningAddress(ctx context.Context, pubkey string) (string, error) {
+	events, err := c.QueryEvents(ctx, nostr.Filter{Kinds: []int{nostr.KindProfileMetadata}, Authors: []string{pubkey}})
+	if err != nil {
+		return "", err
+	}
+
+	var newest *nostr.Event
+	for _, ev := range events {
+		if newest == nil || ev.CreatedAt > newest.CreatedAt {
+			newest = ev
+		}
+	}
+	if newest == nil {
+		return "", fmt.Errorf("author profile not found")
+	}
+
+	var profile struct {
+		Lud16 string `json:"lud16"`
+	}
+	if err := json.Unmarshal([]byte(newest.Content), &profile); err != nil {
+		return "", fmt.Errorf("invalid author profile: %w", err)
+	}
+	if profile.Lud16 == "" {
+		return "", fmt.Errorf("author has no lightning address")
+	}
+	return profile.Lud16, nil
+}
+
+// FetchLNURLPay resolves a lightning address to its LNURL-pay endpoint
+func FetchLNURLPay(ctx context.Context, lud16 string) (*LNURLPay, error) {
+	name, domain, ok := strings.Cut(strings.TrimSpace(lud16), "@")
+	if !ok || name == "" || domain == "" {
+		return nil, fmt.Errorf("invalid lightning address %q", lud16)
+	}
+
+	// Plain http is only used for local servers
+	scheme := "https"
+	if host, _, _ := strings.Cut(domain, ":"); host == "localhost" || host == "127.0.0.1" {
+		scheme = "http"
+	}
+
+	var pay LNURLPay
+	if err := getLNURL(ctx, fmt.Sprintf("%s://%s/.well-known/lnurlp/%s", scheme, domain, url.PathEscape(name)), &pay); err != nil {
+		return nil, err
+	}
+	if pay.Callback == "" {
+		return nil, fmt.Errorf("lightning address has no callback")
+	}
+	return &pay, nil
+}
+
+// NewZapRequest builds and signs a kind 9734 zap request for an article.
+// It is sent to the LNURL server, not published.
+func (c *Client) NewZapRequest(ref ArticleRef, msats int64, comment string) (*nostr.Event, error) {
+	if ref.Author == "" {
+		return nil, fmt.Errorf("article author is unknown")
+	}
+
+	tags := nostr.Tags{
+		append(nostr.Tag{"relays"}, c.relays...),
+		{"amount", strconv.FormatInt(msats, 10)},
+		{"p", ref.Author},
+	}
+	if ref.EventID != "" {
+		tags = append(tags, nostr.Tag{"e", ref.EventID})
+	}
+	if ref.Coordinate != "" {
+		tags = append(tags, nostr.Tag{"a", ref.Coordinate})
+	}
+
+	event := &nostr.Event{
+		Kind:      ZapRequestKind,
+		CreatedAt: nostr.Now(),
+		Tags:      tags,
+		Content:   comment,
+	}
+	if err := c.SignEvent(event); err != nil {
+		return nil, err
+	}
+	return event, nil
+}
+
+// RequestZapInvoice asks the LNURL callback for an invoice carrying the zap
+// request and checks that it is for the requested amount
+func RequestZapInvoice(ctx context.Context, pay *LNURLPay, zapRequest *nostr.Event, msats int64) (string, error) {
+	callback, err := url.Parse(pay.Callback)
+	if err != nil {
+		return "", fmt.Errorf("invalid callback URL: %w", err)
+	}
+	query := callback.Query()
+	query.Set("amount", strconv.FormatInt(msats, 10))
+	query.Set("nostr", zapRequest.String())
+	callback.RawQuery = query.Encode()
+
+	var resp struct {
+		PR string `json:"pr"`
+	}
+	if err := getLNURL(ctx, callback.String(), &resp); err != nil {
+		return "", err
+	}
+	if resp.PR == "" {
+		return "", fmt.Errorf("LNURL server returned no invoice")
+	}
+
+	amount, err := Bolt11Amount(resp.PR)
+	if err != nil {
+		return "", err
+	}
+	if amount != msats {
+		return "", fmt.Errorf("invoice is for %d msats, expected %d", amount, msats)
+	}
+	return resp.PR, nil
+}
+
+// Zap pays an article's author through the wallet: it looks up their
+// lightning address, gets an invoice for a zap request and pays it over NWC.
+// It returns the payment preimage.
+func (c *Client) Zap(ctx context.Context, wc *WalletConnect, ref ArticleRef, sats int64, comment string) (string, error) {
+	if sats <= 0 {
+		return "", fmt.Errorf("zap amount must be positive")
+	}
+	msats := sats * 1000
+
+	lud16, err := c.FetchLightningAddress(ctx, ref.Author)
+	if err != nil {
+		return "", err
+	}
+	pay, err := FetchLNURLPay(ctx, lud16)
+	if err != nil {
+		return "", err
+	}
+	if !pay.AllowsNostr || !nostr.IsValidPublicKey(pay.NostrPubkey) {
+		return "", fmt.Errorf("%s does not support zaps", lud16)
+	}
+	if msats < pay.MinSendable || (pay.MaxSendable > 0 && msats > pay.MaxSendable) {
+		return "", fmt.Errorf("%s accepts %d to %d sats", lud16, pay.MinSendable/1000, pay.MaxSendable/1000)
+	}
+
+	zapRequest, err := c.NewZapRequest(ref, msats, comment)
+	if err != nil {
+		return "", err
+	}
+	invoice, err := RequestZapInvoice(ctx, pay, zapRequest, msats)
+	if err != nil {
+		return "", err
+	}
+	return c.PayInvoice(ctx, wc, invoice)
+}
+
+// getLNURL fetches an LNURL JSON response, turning {"status":"ERROR"} into an error
+func getLNURL(ctx context.Context, endpoint string, v any) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return err
+	}
+	resp, err := lnurlClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("LNURL request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	var body json.RawMessage
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return fmt.Errorf("invalid LNURL response (HTTP %d): %w", resp.StatusCode, err)
+	}
+	var status struct {
+		Status string `json:"status"`
+		Reason string `json:"reason"`
+	}
+	json.Unmarshal(body, &status)
+	if strings.EqualFold(status.Status, "ERROR") {
+		return fmt.Errorf("LNURL error: %s", status.Reason)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("LNURL request failed: HTTP %d", resp.StatusCode)
+	}
+	return json.Unmarshal(body, v)
+}
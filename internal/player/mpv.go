@@ -0,0 +1,277 @@
+// Package player controls mpv through its JSON IPC socket
+// (https://mpv.io/manual/stable/#json-ipc): podcast episodes it starts
+// itself, and video windows started by the media launcher.
+package player
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"os/exec"
+	"sync"
+	"time"
+)
+
+// startTimeout bounds the wait for mpv to open its IPC socket
+const startTimeout = 5 * time.Second
+
+// ErrClosed is returned for commands sent after the player went away
+var ErrClosed = errors.New("player closed")
+
+// Status is a snapshot of playback
+type Status struct {
+	Position time.Duration
+	Duration time.Duration // 0 until mpv knows it
+	Speed    float64
+	Paused   bool
+}
+
+// Player is a running mpv instance
+type Player struct {
+	conn net.Conn
+	cmd  *exec.Cmd // nil when attached with Dial
+
+	mu       sync.Mutex
+	nextID   int
+	pending  map[int]chan response
+	closed   bool // read has stopped: no response will come
+	finished bool // The file played to its end
+
+	done chan struct{}
+}
+
+type response struct {
+	RequestID int             `json:"request_id"`
+	Error     string          `json:"error"`
+	Data      json.RawMessage `json:"data"`
+	Event     string          `json:"event"`
+	Reason    string          `json:"reason"`
+}
+
+// Start plays url in a new audio-only mpv from the given position, with
+// its IPC socket at socketPath
+func Start(url string, start time.Duration, socketPath string) (*Player, error) {
+	os.Remove(socketPath) // Left over from a player that crashed
+	cmd := exec.Command("mpv", "--no-video", "--no-terminal",
+		"--input-ipc-server="+socketPath,
+		fmt.Sprintf("--start=%d", int(start.Seconds())),
+		url)
+	if err := cmd.Start(); err != nil {
+		return nil, fmt.Errorf("failed to start mpv: %w", err)
+	}
+	exited := make(chan struct{})
+	go func() {
+		cmd.Wait()
+		close(exited)
+	}()
+
+	// mpv opens the socket shortly after starting
+	deadline := time.Now().Add(startTimeout)
+	for {
+		conn, err := net.Dial("unix", socketPath)
+		if err == nil {
+			p := attach(conn)
+			p.cmd = cmd
+			return p, nil
+		}
+		if time.Now().After(deadline) {
+			cmd.Process.Kill()
+			return nil, fmt.Errorf("mpv did not open its IPC socket: %w", err)
+		}
+		select {
+		case <-exited:
+			return nil, errors.New("mpv exited before playing")
+		case <-time.After(50 * time.Millisecond):
+		}
+	}
+}
+
+// Dial attaches to an mpv already listening on socketPath
+func Dial(socketPath string) (*Player, error) {
+	conn, err := net.Dial("unix", socketPath)
+	if err != nil {
+		return nil, err
+	}
+	return attach(conn), nil
+}
+
+func attach(conn net.Conn) *Player {
+	p := &Player{
+		conn:    conn,
+		pending: make(map[int]chan response),
+		done:    make(chan struct{}),
+	}
+	go p.read()
+	return p
+}
+
+// read hands responses to the commands waiting for them and notes when
+// the file ends, until mpv goes away
+func (p *Player) read() {
+	scanner := bufio.NewScanner(p.conn)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		var resp response
+		if err := json.Unmarshal(scanner.Bytes(), &resp); err != nil {
+			continue
+		}
+		p.mu.Lock()
+		if resp.Event == "end-file" && resp.Reason == "eof" {
+			p.finished = true
+		}
+		if ch, ok := p.pending[resp.RequestID]; ok && resp.Event == "" {
+			delete(p.pending, resp.RequestID)
+			ch <- resp
+		}
+		p.mu.Unlock()
+	}
+
+	// Marked closed in the same critical section that drains pending, so
+	// command cannot register a channel after the drain
+	p.mu.Lock()
+	p.closed = true
+	for id, ch := range p.pending {
+		delete(p.pending, id)
+		close(ch)
+	}
+	p.mu.Unlock()
+	p.conn.Close()
+	close(p.done)
+}
+
+// command sends a command and waits for its response
+func (p *Player) command(args ...any) (json.RawMessage, error) {
+	p.mu.Lock()
+	if p.closed {
+		p.mu.Unlock()
+		return nil, ErrClosed
+	}
+	p.nextID++
+	id := p.nextID
+	ch := make(chan response, 1)
+	p.pending[id] = ch
+	p.mu.Unlock()
+
+	msg, err := json.Marshal(map[string]any{"command": args, "request_id": id})
+	if err != nil {
+		return nil, err
+	}
+	if _, err := p.conn.Write(append(msg, '\n')); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrClosed, err)
+	}
+
+	select {
+	case resp, ok := <-ch:
+		if !ok {
+			return nil, ErrClosed
+		}
+		if resp.Error != "success" {
+			return nil, fmt.Errorf("mpv: %s", resp.Error)
+		}
+		return resp.Data, nil
+	case <-time.After(startTimeout):
+		p.mu.Lock()
+		delete(p.pending, id)
+		p.mu.Unlock()
+		return nil, errors.New("mpv did not answer")
+	}
+}
+
+// property reads a property into v. Properties mpv does not have yet,
+// such as the duration while the file opens, leave v unchanged.
+func (p *Player) property(name string, v any) error {
+	data, err := p.command("get_property", name)
+	if err != nil {
+		if err.Error() == "mpv: property unavailable" {
+			return nil
+		}
+		return err
+	}
+	return json.Unmarshal(data, v)
+}
+
+// TogglePause pauses or resumes playback
+func (p *Player) TogglePause() error {
+	_, err := p.command("cycle", "pause")
+	return err
+}
+
+// Seek moves playback by offset, backwards when negative
+func (p *Player) Seek(offset time.Duration) error {
+	_, err := p.command("seek", offset.Seconds(), "relative")
+	return err
+}
+
+// SetSpeed sets the playback speed, 1 being normal
+func (p *Player) SetSpeed(speed float64) error {
+	_, err := p.command("set_property", "speed", speed)
+	return err
+}
+
+// Status reads the playback position, duration, speed and pause state
+func (p *Player) Status() (Status, error) {
+	var position, duration float64
+	status := Status{Speed: 1}
+	if err := p.property("time-pos", &position); err != nil {
+		return status, err
+	}
+	if err := p.property("duration", &duration); err != nil {
+		return status, err
+	}
+	if err := p.property("speed", &status.Speed); err != nil {
+		return status, err
+	}
+	if err := p.property("pause", &status.Paused); err != nil {
+		return status, err
+	}
+	status.Position = time.Duration(position * float64(time.Second))
+	status.Duration = time.Duration(duration * float64(time.Second))
+	return status, nil
+}
+
+// Load replaces the playing file, keeping the window
+func (p *Player) Load(url string) error {
+	_, err := p.command("loadfile", url, "replace")
+	return err
+}
+
+// Close detaches from mpv, leaving it playing
+func (p *Player) Close() {
+	p.conn.Close()
+	<-p.done
+}
+
+// Done is closed once mpv has gone away, whether the file ended, it was
+// stopped or it crashed
+func (p *Player) Done() <-chan struct{} {
+	return p.done
+}
+
+// Finished reports whether the file played to its end
+func (p *Player) Finished() bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.finished
+}
+
+// Stop quits mpv and waits for it to go away
+func (p *Player) Stop() error {
+	_, err := p.command("quit")
+	if err != nil && !errors.Is(err, ErrClosed) {
+		// Not answering: make sure it goes
+		if p.cmd != nil {
+			p.cmd.Process.Kill()
+		}
+		p.conn.Close()
+	}
+	select {
+	case <-p.done:
+	case <-time.After(startTimeout):
+		p.conn.Close()
+		<-p.done
+	}
+	return nil
+}
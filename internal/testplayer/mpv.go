@@ -0,0 +1,201 @@
+// Package testplayer provides a stand-in for mpv's JSON IPC socket, so the
+// verification programs under cmd/ can drive podcast playback without
+// mpv or audio.
+package testplayer
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+// Server answers mpv IPC commands on a unix socket, keeping the state of
+// one file that plays only when told to seek
+type Server struct {
+	Socket string           // Path for player.Dial
+	OnLoad func(url string) // Called for every file loaded after the first
+
+	listener net.Listener
+	dir      string
+
+	mu       sync.Mutex
+	conn     net.Conn
+	position float64
+	duration float64
+	speed    float64
+	paused   bool
+	commands [][]any
+	quit     chan struct{}
+}
+
+// Start listens on a socket in a fresh temporary directory. Call Close
+// when done.
+func Start(duration float64) (*Server, error) {
+	dir, err := os.MkdirTemp("", "testplayer")
+	if err != nil {
+		return nil, err
+	}
+	s, err := Listen(filepath.Join(dir, "mpv.sock"), duration)
+	if err != nil {
+		os.RemoveAll(dir)
+		return nil, err
+	}
+	s.dir = dir
+	return s, nil
+}
+
+// Listen listens on the given socket, as mpv does for --input-ipc-server
+func Listen(socket string, duration float64) (*Server, error) {
+	listener, err := net.Listen("unix", socket)
+	if err != nil {
+		return nil, err
+	}
+	s := &Server{Socket: socket, listener: listener, duration: duration, speed: 1, quit: make(chan struct{})}
+	go s.accept()
+	return s, nil
+}
+
+// Close stops the server
+func (s *Server) Close() {
+	s.listener.Close()
+	s.mu.Lock()
+	if s.conn != nil {
+		s.conn.Close()
+	}
+	s.mu.Unlock()
+	if s.dir != "" {
+		os.RemoveAll(s.dir)
+	}
+}
+
+// Quit is closed once a client sent the quit command
+func (s *Server) Quit() <-chan struct{} {
+	return s.quit
+}
+
+// Commands returns the names of the commands received so far
+func (s *Server) Commands() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	names := make([]string, len(s.commands))
+	for i, c := range s.commands {
+		names[i] = fmt.Sprint(c[0])
+	}
+	return names
+}
+
+// Finish plays to the end of the file and exits like mpv does
+func (s *Server) Finish() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.position = s.duration
+	if s.conn != nil {
+		s.send(map[string]any{"event": "end-file", "reason": "eof"})
+		s.conn.Close()
+	}
+}
+
+func (s *Server) accept() {
+	for {
+		conn, err := s.listener.Accept()
+		if err != nil {
+			return
+		}
+		s.mu.Lock()
+		s.conn = conn
+		s.mu.Unlock()
+		go s.serve(conn)
+	}
+}
+
+func (s *Server) serve(conn net.Conn) {
+	scanner := bufio.NewScanner(conn)
+	for scanner.Scan() {
+		var req struct {
+			Command   []any `json:"command"`
+			RequestID int   `json:"request_id"`
+		}
+		if err := json.Unmarshal(scanner.Bytes(), &req); err != nil || len(req.Command) == 0 {
+			continue
+		}
+		s.mu.Lock()
+		s.commands = append(s.commands, req.Command)
+		data, errText := s.handle(req.Command)
+		s.send(map[string]any{"request_id": req.RequestID, "error": errText, "data": data})
+		quit := req.Command[0] == "quit"
+		s.mu.Unlock()
+		if quit {
+			conn.Close()
+			close(s.quit)
+			return
+		}
+	}
+}
+
+// handle runs a command; the caller holds s.mu
+func (s *Server) handle(cmd []any) (any, string) {
+	arg := func(i int) any {
+		if i < len(cmd) {
+			return cmd[i]
+		}
+		return nil
+	}
+	switch cmd[0] {
+	case "get_property":
+		switch arg(1) {
+		case "time-pos":
+			return s.position, "success"
+		case "duration":
+			return s.duration, "success"
+		case "speed":
+			return s.speed, "success"
+		case "pause":
+			return s.paused, "success"
+		}
+		return nil, "property unavailable"
+	case "set_property":
+		if arg(1) == "speed" {
+			if speed, ok := arg(2).(float64); ok {
+				s.speed = speed
+				return nil, "success"
+			}
+		}
+		return nil, "invalid parameter"
+	case "cycle":
+		if arg(1) == "pause" {
+			s.paused = !s.paused
+			return nil, "success"
+		}
+		return nil, "invalid parameter"
+	case "seek":
+		offset, ok := arg(1).(float64)
+		if !ok {
+			return nil, "invalid parameter"
+		}
+		s.position = min(max(s.position+offset, 0), s.duration)
+		return nil, "success"
+	case "loadfile":
+		url, ok := arg(1).(string)
+		if !ok {
+			return nil, "invalid parameter"
+		}
+		s.position = 0
+		if s.OnLoad != nil {
+			s.OnLoad(url)
+		}
+		return nil, "success"
+	case "quit":
+		return nil, "success"
+	}
+	return nil, "invalid parameter"
+}
+
+// send writes one message to the client; the caller holds s.mu
+func (s *Server) send(msg map[string]any) {
+	data, _ := json.Marshal(msg)
+	s.conn.Write(append(data, '\n'))
+}
@@ -0,0 +1,209 @@
+// Package testrelay provides a minimal in-memory Nostr relay for the local
+// verification programs under cmd/. It supports EVENT, REQ, CLOSE and
+// NIP-42 AUTH, which is enough to exercise the client without a network.
+package testrelay
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/coder/websocket"
+	"github.com/nbd-wtf/go-nostr"
+	"github.com/nbd-wtf/go-nostr/nip42"
+)
+
+// Relay is a local relay listening on 127.0.0.1
+type Relay struct {
+	URL         string
+	RequireAuth bool               // Refuse REQ and EVENT until the client authenticates
+	OnEvent     func(*nostr.Event) // Called for every accepted event
+
+	server *httptest.Server
+
+	mu     sync.Mutex
+	events []*nostr.Event
+	conns  map[*conn]bool
+	authed []string // Pubkeys that completed AUTH
+}
+
+type conn struct {
+	ws        *websocket.Conn
+	writeMu   sync.Mutex
+	challenge string
+	pubkey    string
+	subs      map[string]nostr.Filters
+}
+
+// Start starts a relay. Call Close when done.
+func Start(requireAuth bool) *Relay {
+	r := &Relay{
+		RequireAuth: requireAuth,
+		conns:       make(map[*conn]bool),
+	}
+	r.server = httptest.NewServer(http.HandlerFunc(r.serve))
+	r.URL = "ws" + strings.TrimPrefix(r.server.URL, "http")
+	return r
+}
+
+// Close stops the relay
+func (r *Relay) Close() {
+	r.server.Close()
+}
+
+// Authenticated returns the pubkeys that passed NIP-42 AUTH
+func (r *Relay) Authenticated() []string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]string{}, r.authed...)
+}
+
+// Events returns every event stored on the relay
+func (r *Relay) Events() []*nostr.Event {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]*nostr.Event{}, r.events...)
+}
+
+// Publish stores an event and delivers it to matching live subscriptions
+func (r *Relay) Publish(event *nostr.Event) {
+	r.mu.Lock()
+	r.events = append(r.events, event)
+	conns := make([]*conn, 0, len(r.conns))
+	for c := range r.conns {
+		conns = append(conns, c)
+	}
+	r.mu.Unlock()
+
+	for _, c := range conns {
+		c.writeMu.Lock()
+		subs := make(map[string]nostr.Filters, len(c.subs))
+		for id, filters := range c.subs {
+			subs[id] = filters
+		}
+		c.writeMu.Unlock()
+
+		for id, filters := range subs {
+			if filters.Match(event) {
+				c.send(nostr.EventEnvelope{SubscriptionID: &id, Event: *event})
+			}
+		}
+	}
+}
+
+func (r *Relay) serve(w http.ResponseWriter, req *http.Request) {
+	ws, err := websocket.Accept(w, req, nil)
+	if err != nil {
+		return
+	}
+	defer ws.CloseNow()
+
+	c := &conn{
+		ws:        ws,
+		challenge: fmt.Sprintf("challenge-%d", time.Now().UnixNano()),
+		subs:      make(map[string]nostr.Filters),
+	}
+
+	r.mu.Lock()
+	r.conns[c] = true
+	r.mu.Unlock()
+	defer func() {
+		r.mu.Lock()
+		delete(r.conns, c)
+		r.mu.Unlock()
+	}()
+
+	if r.RequireAuth {
+		c.send(nostr.AuthEnvelope{Challenge: &c.challenge})
+	}
+
+	ctx := req.Context()
+	for {
+		_, data, err := ws.Read(ctx)
+		if err != nil {
+			return
+		}
+
+		switch env := nostr.ParseMessage(string(data)).(type) {
+		case *nostr.EventEnvelope:
+			if r.RequireAuth && c.pubkey == "" {
+				c.send(nostr.OKEnvelope{EventID: env.Event.ID, OK: false, Reason: "auth-required: please authenticate"})
+				continue
+			}
+			if ok, _ := env.Event.CheckSignature(); !ok {
+				c.send(nostr.OKEnvelope{EventID: env.Event.ID, OK: false, Reason: "invalid: bad signature"})
+				continue
+			}
+			c.send(nostr.OKEnvelope{EventID: env.Event.ID, OK: true})
+			event := env.Event
+			if r.OnEvent != nil {
+				r.OnEvent(&event)
+			}
+			r.Publish(&event)
+
+		case *nostr.ReqEnvelope:
+			if r.RequireAuth && c.pubkey == "" {
+				c.send(nostr.ClosedEnvelope{SubscriptionID: env.SubscriptionID, Reason: "auth-required: please authenticate"})
+				continue
+			}
+			r.mu.Lock()
+			var matched []*nostr.Event
+			for _, event := range r.events {
+				if env.Filters.Match(event) {
+					matched = append(matched, event)
+				}
+			}
+			r.mu.Unlock()
+
+			id := env.SubscriptionID
+			for _, event := range matched {
+				c.send(nostr.EventEnvelope{SubscriptionID: &id, Event: *event})
+			}
+			eose := nostr.EOSEEnvelope(id)
+			c.send(eose)
+
+			c.writeMu.Lock()
+			c.subs[id] = env.Filters
+			c.writeMu.Unlock()
+
+		case *nostr.CloseEnvelope:
+			c.writeMu.Lock()
+			delete(c.subs, string(*env))
+			c.writeMu.Unlock()
+
+		case *nostr.AuthEnvelope:
+			pubkey, ok := nip42.ValidateAuthEvent(&env.Event, c.challenge, r.URL)
+			if ok {
+				ok, _ = env.Event.CheckSignature()
+			}
+			if !ok {
+				c.send(nostr.OKEnvelope{EventID: env.Event.ID, OK: false, Reason: "auth-required: invalid AUTH event"})
+				continue
+			}
+			c.pubkey = pubkey
+			r.mu.Lock()
+			r.authed = append(r.authed, pubkey)
+			r.mu.Unlock()
+			c.send(nostr.OKEnvelope{EventID: env.Event.ID, OK: true})
+		}
+	}
+}
+
+func (c *conn) send(env json.Marshaler) {
+	data, err := json.Marshal(env)
+	if err != nil {
+		return
+	}
+
+	c.writeMu.Lock()
+	defer c.writeMu.Unlock()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	c.ws.Write(ctx, websocket.MessageText, data)
+}
@@ -0,0 +1,106 @@
+package testwallet
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/nbd-wtf/go-nostr"
+)
+
+// LNURLServer serves a lightning address that accepts zap requests
+type LNURLServer struct {
+	Address string // Lightning address (lud16) of the recipient
+
+	server *httptest.Server
+	pubkey string
+
+	mu       sync.Mutex
+	requests []*nostr.Event
+}
+
+// StartLNURL starts a lightning address "<name>@127.0.0.1:<port>". Call Close when done.
+func StartLNURL(name string) *LNURLServer {
+	s := &LNURLServer{}
+	s.pubkey, _ = nostr.GetPublicKey(nostr.GeneratePrivateKey())
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/.well-known/lnurlp/"+name, s.payInfo)
+	mux.HandleFunc("/callback", s.callback)
+	s.server = httptest.NewServer(mux)
+	s.Address = name + "@" + strings.TrimPrefix(s.server.URL, "http://")
+	return s
+}
+
+// Close stops the server
+func (s *LNURLServer) Close() {
+	s.server.Close()
+}
+
+// ZapRequests returns the valid zap requests the server issued invoices for
+func (s *LNURLServer) ZapRequests() []*nostr.Event {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]*nostr.Event{}, s.requests...)
+}
+
+func (s *LNURLServer) payInfo(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, map[string]any{
+		"tag":         "payRequest",
+		"callback":    s.server.URL + "/callback",
+		"minSendable": 1000,
+		"maxSendable": 100_000_000,
+		"metadata":    `[["text/plain","test"]]`,
+		"allowsNostr": true,
+		"nostrPubkey": s.pubkey,
+	})
+}
+
+// callback validates the zap request as NIP-57 appendix D describes and
+// returns a fake invoice for the amount
+func (s *LNURLServer) callback(w http.ResponseWriter, r *http.Request) {
+	amount, err := strconv.ParseInt(r.URL.Query().Get("amount"), 10, 64)
+	if err != nil || amount <= 0 {
+		writeError(w, "invalid amount")
+		return
+	}
+
+	var zapRequest nostr.Event
+	if err := json.Unmarshal([]byte(r.URL.Query().Get("nostr")), &zapRequest); err != nil {
+		writeError(w, "invalid zap request")
+		return
+	}
+	if ok, _ := zapRequest.CheckSignature(); !ok || zapRequest.Kind != 9734 {
+		writeError(w, "zap request is not a signed kind 9734 event")
+		return
+	}
+	if tag := zapRequest.Tags.GetFirst([]string{"amount"}); tag == nil || (*tag)[1] != strconv.FormatInt(amount, 10) {
+		writeError(w, "zap request amount does not match")
+		return
+	}
+	if len(zapRequest.Tags.GetAll([]string{"p"})) != 1 || zapRequest.Tags.GetFirst([]string{"relays"}) == nil {
+		writeError(w, "zap request needs one p tag and a relays tag")
+		return
+	}
+
+	s.mu.Lock()
+	s.requests = append(s.requests, &zapRequest)
+	s.mu.Unlock()
+
+	// Picobitcoin amounts are exact for any msat value. The data part
+	// stays within the bech32 charset, which has no "1".
+	writeJSON(w, map[string]any{"pr": fmt.Sprintf("lnbcrt%dp1pjtestzap", amount*10), "routes": []any{}})
+}
+
+func writeJSON(w http.ResponseWriter, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(v)
+}
+
+func writeError(w http.ResponseWriter, reason string) {
+	writeJSON(w, map[string]string{"status": "ERROR", "reason": reason})
+}
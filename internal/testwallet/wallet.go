@@ -0,0 +1,95 @@
+// Package testwallet provides local stand-ins for a lightning address
+// (LNURL-pay with NIP-57 support) and a NIP-47 wallet service, so the
+// verification programs under cmd/ can exercise zaps without real sats.
+package testwallet
+
+import (
+	"encoding/json"
+	"fmt"
+	"sync"
+
+	"github.com/nbd-wtf/go-nostr"
+	"github.com/nbd-wtf/go-nostr/nip04"
+	"github.com/plebone/nostrfeedz-cli/internal/testrelay"
+)
+
+// Wallet answers NWC requests that arrive on a test relay
+type Wallet struct {
+	URI  string // nostr+walletconnect:// URI for the client
+	Fail string // When set, payments fail with this message
+
+	key          string
+	pubkey       string
+	clientPubkey string
+	relay        *testrelay.Relay
+
+	mu   sync.Mutex
+	paid []string
+}
+
+// StartWallet attaches a wallet service to a relay through its OnEvent hook
+func StartWallet(relay *testrelay.Relay) *Wallet {
+	w := &Wallet{key: nostr.GeneratePrivateKey(), relay: relay}
+	w.pubkey, _ = nostr.GetPublicKey(w.key)
+
+	secret := nostr.GeneratePrivateKey()
+	w.clientPubkey, _ = nostr.GetPublicKey(secret)
+	w.URI = fmt.Sprintf("nostr+walletconnect://%s?relay=%s&secret=%s", w.pubkey, relay.URL, secret)
+
+	relay.OnEvent = w.handle
+	return w
+}
+
+// Paid returns the invoices the wallet has paid
+func (w *Wallet) Paid() []string {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return append([]string{}, w.paid...)
+}
+
+func (w *Wallet) handle(ev *nostr.Event) {
+	if ev.Kind != 23194 || ev.PubKey != w.clientPubkey || ev.Tags.GetFirst([]string{"p", w.pubkey}) == nil {
+		return
+	}
+	sharedSecret, err := nip04.ComputeSharedSecret(ev.PubKey, w.key)
+	if err != nil {
+		return
+	}
+	plain, err := nip04.Decrypt(ev.Content, sharedSecret)
+	if err != nil {
+		return
+	}
+
+	var req struct {
+		Method string `json:"method"`
+		Params struct {
+			Invoice string `json:"invoice"`
+		} `json:"params"`
+	}
+	resp := map[string]any{"result_type": "pay_invoice"}
+	switch {
+	case json.Unmarshal([]byte(plain), &req) != nil || req.Method != "pay_invoice":
+		resp["error"] = map[string]string{"code": "NOT_IMPLEMENTED", "message": "unsupported request"}
+	case w.Fail != "":
+		resp["error"] = map[string]string{"code": "PAYMENT_FAILED", "message": w.Fail}
+	default:
+		w.mu.Lock()
+		w.paid = append(w.paid, req.Params.Invoice)
+		w.mu.Unlock()
+		resp["result"] = map[string]string{"preimage": fmt.Sprintf("%064x", len(w.Paid()))}
+	}
+
+	payload, _ := json.Marshal(resp)
+	content, err := nip04.Encrypt(string(payload), sharedSecret)
+	if err != nil {
+		return
+	}
+	response := &nostr.Event{
+		Kind:      23195,
+		CreatedAt: nostr.Now(),
+		Tags:      nostr.Tags{{"p", ev.PubKey}, {"e", ev.ID}},
+		Content:   content,
+	}
+	response.Sign(w.key)
+	w.relay.Publish(response)
+}
@@ -2,23 +2,63 @@ package styles
 
 import "github.com/charmbracelet/lipgloss"
 
-// Colors - Dark terminal optimized
+// Colors of the active theme. Set by Apply.
 var (
-	PrimaryColor   = lipgloss.Color("#A78BFA") // Brighter purple for dark bg
-	SecondaryColor = lipgloss.Color("#9CA3AF")
-	AccentColor    = lipgloss.Color("#60A5FA") // Brighter blue for dark bg
-	ErrorColor     = lipgloss.Color("#F87171") // Brighter red
-	SuccessColor   = lipgloss.Color("#34D399") // Brighter green
-	WarningColor   = lipgloss.Color("#FBBF24") // Brighter yellow
-	
-	BackgroundColor = lipgloss.Color("#1F2937") // Dark background
-	BorderColor     = lipgloss.Color("#4B5563")  // Gray border for dark bg
-	TextColor       = lipgloss.Color("#F9FAFB")  // Light text for dark bg
-	MutedTextColor  = lipgloss.Color("#9CA3AF")  // Muted gray text
+	PrimaryColor   lipgloss.Color
+	SecondaryColor lipgloss.Color
+	AccentColor    lipgloss.Color
+	ErrorColor     lipgloss.Color
+	SuccessColor   lipgloss.Color
+	WarningColor   lipgloss.Color
+
+	BackgroundColor lipgloss.Color
+	BorderColor     lipgloss.Color
+	TextColor       lipgloss.Color
+	MutedTextColor  lipgloss.Color
 )
 
-// Styles
+// Styles built from the active theme. Set by Apply.
 var (
+	TitleStyle     lipgloss.Style
+	HeaderStyle    lipgloss.Style
+	FeedItemStyle  lipgloss.Style
+	SelectedStyle  lipgloss.Style
+	UnreadBadge    lipgloss.Style
+	FavoriteBadge  lipgloss.Style
+	PanelBorder    lipgloss.Style
+	ErrorStyle     lipgloss.Style
+	SuccessStyle   lipgloss.Style
+	WarningStyle   lipgloss.Style
+	MutedStyle     lipgloss.Style
+	StatusBarStyle lipgloss.Style
+	KeyStyle       lipgloss.Style
+	ValueStyle     lipgloss.Style
+)
+
+var current Theme
+
+func init() {
+	Apply(Builtin["default"])
+}
+
+// Apply makes a theme active, rebuilding every color and style.
+// Unset colors are taken from the default theme.
+func Apply(t Theme) {
+	t = t.withDefaults(Builtin["default"])
+	current = t
+
+	PrimaryColor = lipgloss.Color(t.Primary)
+	SecondaryColor = lipgloss.Color(t.Secondary)
+	AccentColor = lipgloss.Color(t.Accent)
+	ErrorColor = lipgloss.Color(t.Error)
+	SuccessColor = lipgloss.Color(t.Success)
+	WarningColor = lipgloss.Color(t.Warning)
+
+	BackgroundColor = lipgloss.Color(t.Background)
+	BorderColor = lipgloss.Color(t.Border)
+	TextColor = lipgloss.Color(t.Text)
+	MutedTextColor = lipgloss.Color(t.MutedText)
+
 	TitleStyle = lipgloss.NewStyle().
 		Bold(true).
 		Foreground(PrimaryColor).
@@ -27,7 +67,7 @@ var (
 	HeaderStyle = lipgloss.NewStyle().
 		Bold(true).
 		Foreground(AccentColor).
-		Background(lipgloss.Color("#1E3A8A")). // Dark blue bg
+		Background(lipgloss.Color(t.HeaderBackground)).
 		Padding(0, 1)
 
 	FeedItemStyle = lipgloss.NewStyle().
@@ -36,13 +76,13 @@ var (
 
 	SelectedStyle = lipgloss.NewStyle().
 		Bold(true).
-		Foreground(lipgloss.Color("#FFFFFF")). // White text for selection
-		Background(AccentColor).                // Blue highlight
+		Foreground(lipgloss.Color(t.SelectedText)).
+		Background(AccentColor).
 		PaddingLeft(2)
 
 	UnreadBadge = lipgloss.NewStyle().
 		Background(AccentColor).
-		Foreground(lipgloss.Color("#FFFFFF")).
+		Foreground(lipgloss.Color(t.SelectedText)).
 		Padding(0, 1).
 		Bold(true)
 
@@ -63,11 +103,15 @@ var (
 		Foreground(SuccessColor).
 		Bold(true)
 
+	WarningStyle = lipgloss.NewStyle().
+		Foreground(WarningColor).
+		Bold(true)
+
 	MutedStyle = lipgloss.NewStyle().
 		Foreground(MutedTextColor)
 
 	StatusBarStyle = lipgloss.NewStyle().
-		Background(lipgloss.Color("#111827")). // Darker background
+		Background(lipgloss.Color(t.StatusBarBackground)).
 		Foreground(MutedTextColor).
 		Padding(0, 1)
 
@@ -77,7 +121,43 @@ var (
 
 	ValueStyle = lipgloss.NewStyle().
 		Foreground(TextColor)
-)
+}
+
+// Current returns the active theme
+func Current() Theme {
+	return current
+}
+
+// autoGlamour is the glamour style "auto" stands for, see DetectBackground
+var autoGlamour = "dark"
+
+// DetectBackground asks the terminal whether its background is dark, for
+// themes whose glamour style is "auto". Call it once before the TUI starts:
+// the answer arrives on stdin, which the TUI reads from afterwards.
+func DetectBackground() {
+	autoGlamour = "dark"
+	if !lipgloss.HasDarkBackground() {
+		autoGlamour = "light"
+	}
+}
+
+// GlamourStyle is the theme's glamour style with "auto" resolved by
+// DetectBackground, so renderers never query the terminal themselves
+func (t Theme) GlamourStyle() string {
+	if t.Glamour == "" || t.Glamour == "auto" {
+		return autoGlamour
+	}
+	return t.Glamour
+}
+
+// CategoryStyle colors text with a category's color, falling back to the
+// theme's text color when the category has none
+func CategoryStyle(color string) lipgloss.Style {
+	if color == "" {
+		return lipgloss.NewStyle().Foreground(TextColor)
+	}
+	return lipgloss.NewStyle().Foreground(lipgloss.Color(color))
+}
 
 // RenderKeyValue renders a key-value pair for status bar
 func RenderKeyValue(key, value string) string {
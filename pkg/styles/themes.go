@@ -0,0 +1,202 @@
+package styles
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/spf13/viper"
+)
+
+// Theme is a color palette plus the glamour style used for article text.
+// User themes are YAML or TOML files with the same keys.
+type Theme struct {
+	Name    string `mapstructure:"name"`
+	Extends string `mapstructure:"extends"` // Built-in theme that fills in unset colors
+	Glamour string `mapstructure:"glamour"` // Glamour style name ("auto", "dark", "light", ...) or JSON style path
+
+	Primary             string `mapstructure:"primary"`
+	Secondary           string `mapstructure:"secondary"`
+	Accent              string `mapstructure:"accent"`
+	Error               string `mapstructure:"error"`
+	Success             string `mapstructure:"success"`
+	Warning             string `mapstructure:"warning"`
+	Background          string `mapstructure:"background"`
+	Border              string `mapstructure:"border"`
+	Text                string `mapstructure:"text"`
+	MutedText           string `mapstructure:"muted_text"`
+	HeaderBackground    string `mapstructure:"header_background"`
+	StatusBarBackground string `mapstructure:"status_bar_background"`
+	SelectedText        string `mapstructure:"selected_text"`
+}
+
+// Builtin themes, selectable by name from display.theme
+var Builtin = map[string]Theme{
+	// Dark terminal optimized, with glamour picking dark or light itself
+	"default": {
+		Name:                "default",
+		Glamour:             "auto",
+		Primary:             "#A78BFA", // Brighter purple for dark bg
+		Secondary:           "#9CA3AF",
+		Accent:              "#60A5FA", // Brighter blue for dark bg
+		Error:               "#F87171", // Brighter red
+		Success:             "#34D399", // Brighter green
+		Warning:             "#FBBF24", // Brighter yellow
+		Background:          "#1F2937", // Dark background
+		Border:              "#4B5563", // Gray border for dark bg
+		Text:                "#F9FAFB", // Light text for dark bg
+		MutedText:           "#9CA3AF", // Muted gray text
+		HeaderBackground:    "#1E3A8A", // Dark blue bg
+		StatusBarBackground: "#111827", // Darker background
+		SelectedText:        "#FFFFFF", // White text for selection
+	},
+	"dark": {
+		Name:    "dark",
+		Extends: "default",
+		Glamour: "dark",
+	},
+	"light": {
+		Name:                "light",
+		Glamour:             "light",
+		Primary:             "#6D28D9",
+		Secondary:           "#4B5563",
+		Accent:              "#2563EB",
+		Error:               "#DC2626",
+		Success:             "#047857",
+		Warning:             "#B45309",
+		Background:          "#F9FAFB",
+		Border:              "#D1D5DB",
+		Text:                "#111827",
+		MutedText:           "#6B7280",
+		HeaderBackground:    "#DBEAFE",
+		StatusBarBackground: "#E5E7EB",
+		SelectedText:        "#FFFFFF",
+	},
+	"dracula": {
+		Name:                "dracula",
+		Glamour:             "dracula",
+		Primary:             "#BD93F9",
+		Secondary:           "#6272A4",
+		Accent:              "#8BE9FD",
+		Error:               "#FF5555",
+		Success:             "#50FA7B",
+		Warning:             "#F1FA8C",
+		Background:          "#282A36",
+		Border:              "#6272A4",
+		Text:                "#F8F8F2",
+		MutedText:           "#6272A4",
+		HeaderBackground:    "#44475A",
+		StatusBarBackground: "#21222C",
+		SelectedText:        "#282A36",
+	},
+}
+
+// Catalog holds the built-in themes plus any loaded from a directory
+type Catalog struct {
+	themes map[string]Theme
+	names  []string
+}
+
+// LoadCatalog returns the built-in themes and every *.yaml, *.yml and *.toml
+// theme in dir. A missing dir is not an error. Files that fail to load are
+// skipped and reported.
+func LoadCatalog(dir string) (*Catalog, []error) {
+	c := &Catalog{themes: make(map[string]Theme)}
+	for name, t := range Builtin {
+		c.themes[name] = t
+	}
+
+	var errs []error
+	entries, err := os.ReadDir(dir)
+	if err != nil && !os.IsNotExist(err) {
+		errs = append(errs, err)
+	}
+	for _, entry := range entries {
+		ext := strings.ToLower(filepath.Ext(entry.Name()))
+		if entry.IsDir() || (ext != ".yaml" && ext != ".yml" && ext != ".toml") {
+			continue
+		}
+		t, err := LoadTheme(filepath.Join(dir, entry.Name()))
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		c.themes[t.Name] = t
+	}
+
+	for name := range c.themes {
+		c.names = append(c.names, name)
+	}
+	sort.Strings(c.names)
+
+	return c, errs
+}
+
+// LoadTheme reads a theme file. The name defaults to the file name and
+// unset colors come from the theme named by extends, or the default theme.
+func LoadTheme(path string) (Theme, error) {
+	v := viper.New()
+	v.SetConfigFile(path)
+	if err := v.ReadInConfig(); err != nil {
+		return Theme{}, fmt.Errorf("theme %s: %w", path, err)
+	}
+
+	var t Theme
+	if err := v.Unmarshal(&t); err != nil {
+		return Theme{}, fmt.Errorf("theme %s: %w", path, err)
+	}
+
+	if t.Name == "" {
+		t.Name = strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+	}
+	if t.Extends != "" {
+		base, ok := Builtin[t.Extends]
+		if !ok {
+			return Theme{}, fmt.Errorf("theme %s: unknown base theme %q", path, t.Extends)
+		}
+		t = t.withDefaults(base)
+	}
+	return t.withDefaults(Builtin["default"]), nil
+}
+
+// Get returns a theme by name
+func (c *Catalog) Get(name string) (Theme, bool) {
+	t, ok := c.themes[name]
+	return t, ok
+}
+
+// Names returns all theme names in sorted order
+func (c *Catalog) Names() []string {
+	return c.names
+}
+
+// Next returns the theme after name, wrapping around
+func (c *Catalog) Next(name string) Theme {
+	for i, n := range c.names {
+		if n == name {
+			return c.themes[c.names[(i+1)%len(c.names)]]
+		}
+	}
+	return c.themes[c.names[0]]
+}
+
+// withDefaults fills every unset string field from base
+func (t Theme) withDefaults(base Theme) Theme {
+	if t.Extends != "" && t.Extends != base.Name {
+		if b, ok := Builtin[t.Extends]; ok {
+			base = b.withDefaults(base)
+		}
+	}
+
+	tv := reflect.ValueOf(&t).Elem()
+	bv := reflect.ValueOf(base)
+	for i := 0; i < tv.NumField(); i++ {
+		if tv.Field(i).Kind() == reflect.String && tv.Field(i).String() == "" {
+			tv.Field(i).SetString(bv.Field(i).String())
+		}
+	}
+	return t
+}